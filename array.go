@@ -7,6 +7,7 @@ package tiledb
 import "C"
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -90,6 +91,23 @@ func ConsolidateArray(tdbCtx *Context, uri string, config *Config) error {
 	return nil
 }
 
+// ConsolidateArrayContext consolidates the array at uri like ConsolidateArray, but returns an error
+// wrapping ErrCanceled when ctx is done before the consolidation completes. The consolidation runs on
+// its own Context with the config of tdbCtx, so canceling ctx does not affect the operations of tdbCtx.
+func ConsolidateArrayContext(ctx context.Context, tdbCtx *Context, uri string, config *Config) error {
+	if ctx.Err() != nil {
+		return canceledError(ctx)
+	}
+	opCtx, err := isolatedContext(tdbCtx)
+	if err != nil {
+		return err
+	}
+	defer opCtx.Free()
+	return runWithContext(ctx, opCtx, func() error {
+		return ConsolidateArray(opCtx, uri, config)
+	})
+}
+
 // CreateArray creates a new TileDB array given a context, URI and schema.
 func CreateArray(tdbCtx *Context, uri string, arraySchema *ArraySchema) error {
 	curi := C.CString(uri)
@@ -122,6 +140,23 @@ func VacuumArray(tdbCtx *Context, uri string, config *Config) error {
 	return nil
 }
 
+// VacuumArrayContext vacuums the array at uri like VacuumArray, but returns an error
+// wrapping ErrCanceled when ctx is done before the vacuum completes. The vacuum runs on
+// its own Context with the config of tdbCtx, like ConsolidateArrayContext.
+func VacuumArrayContext(ctx context.Context, tdbCtx *Context, uri string, config *Config) error {
+	if ctx.Err() != nil {
+		return canceledError(ctx)
+	}
+	opCtx, err := isolatedContext(tdbCtx)
+	if err != nil {
+		return err
+	}
+	defer opCtx.Free()
+	return runWithContext(ctx, opCtx, func() error {
+		return VacuumArray(opCtx, uri, config)
+	})
+}
+
 // NewArray allocates a new array.
 // If the provided Context is nil, a default context is allocated and used.
 func NewArray(tdbCtx *Context, uri string) (*Array, error) {
@@ -419,6 +454,8 @@ func makeNonEmptyDomain[T any](name string, bounds []T) (*NonEmptyDomain, error)
 
 // NonEmptyDomain retrieves the non-empty domain from an array.
 // This returns the bounding coordinates for each dimension.
+// If the array has a current domain, the non-empty domain is within it: cells cannot be written
+// outside the current domain, which can only be expanded.
 func (a *Array) NonEmptyDomain() ([]NonEmptyDomain, bool, error) {
 	schema, err := a.Schema()
 	if err != nil {
@@ -140,3 +140,39 @@ func (a *Array) ConsolidateFragments(config *Config, fragmentList []string) erro
 	runtime.KeepAlive(config)
 	return nil
 }
+
+// DeleteArray deletes all the data of the array at uri: its fragments, commits, metadata and schemas.
+// The array must not be open.
+func DeleteArray(tdbCtx *Context, uri string) error {
+	curi := C.CString(uri)
+	defer C.free(unsafe.Pointer(curi))
+
+	ret := C.tiledb_array_delete(tdbCtx.tiledbContext.Get(), curi)
+	runtime.KeepAlive(tdbCtx)
+	if ret != C.TILEDB_OK {
+		return fmt.Errorf("error deleting tiledb array: %w", tdbCtx.LastError())
+	}
+
+	return nil
+}
+
+// UpgradeArrayVersion upgrades the array at uri to the latest format version supported by the library.
+// The array must not be open. config can be nil.
+func UpgradeArrayVersion(tdbCtx *Context, uri string, config *Config) error {
+	curi := C.CString(uri)
+	defer C.free(unsafe.Pointer(curi))
+
+	var cfg *C.tiledb_config_t
+	if config != nil {
+		cfg = config.tiledbConfig.Get()
+	}
+
+	ret := C.tiledb_array_upgrade_version(tdbCtx.tiledbContext.Get(), curi, cfg)
+	runtime.KeepAlive(tdbCtx)
+	runtime.KeepAlive(config)
+	if ret != C.TILEDB_OK {
+		return fmt.Errorf("error upgrading tiledb array version: %w", tdbCtx.LastError())
+	}
+
+	return nil
+}
@@ -207,3 +207,47 @@ func TestConsolidateFragments(t *testing.T) {
 		})
 	}
 }
+
+func TestDeleteArray(t *testing.T) {
+	array := create1DTestArray(t)
+	write1DTestArray(t, array, []int32{0, 1, 2, 3, 4, 5, 6, 7, 8, 9})
+
+	objectType, err := ObjectType(array.context, array.uri)
+	require.NoError(t, err)
+	require.Equal(t, TILEDB_ARRAY, objectType)
+
+	require.NoError(t, DeleteArray(array.context, array.uri))
+
+	objectType, err = ObjectType(array.context, array.uri)
+	require.NoError(t, err)
+	assert.Equal(t, TILEDB_INVALID, objectType)
+
+	require.Error(t, array.Open(TILEDB_READ))
+}
+
+func TestUpgradeArrayVersion(t *testing.T) {
+	array := create1DTestArray(t)
+	write1DTestArray(t, array, []int32{0, 1, 2, 3, 4, 5, 6, 7, 8, 9})
+
+	// The array already has the latest version, upgrading it keeps it readable
+	require.NoError(t, UpgradeArrayVersion(array.context, array.uri, nil))
+
+	config, err := NewConfig()
+	require.NoError(t, err)
+	require.NoError(t, UpgradeArrayVersion(array.context, array.uri, config))
+
+	require.NoError(t, array.Open(TILEDB_READ))
+	t.Cleanup(func() { array.Close() })
+	schema, err := array.Schema()
+	require.NoError(t, err)
+	version, err := schema.Version()
+	require.NoError(t, err)
+
+	newSchema, err := NewArraySchema(array.context, TILEDB_DENSE)
+	require.NoError(t, err)
+	latest, err := newSchema.Version()
+	require.NoError(t, err)
+	assert.Equal(t, latest, version)
+
+	require.Error(t, UpgradeArrayVersion(array.context, filepath.Join(t.TempDir(), "missing"), nil))
+}
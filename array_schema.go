@@ -289,6 +289,47 @@ func (a *ArraySchema) Attributes() ([]*Attribute, error) {
 	return attributes, nil
 }
 
+// FieldNames returns the names of the dimensions of the schema, in domain order, and the names of its attributes.
+func (a *ArraySchema) FieldNames() (dimensions, attributes []string, err error) {
+	domain, err := a.Domain()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer domain.Free()
+
+	nDim, err := domain.NDim()
+	if err != nil {
+		return nil, nil, err
+	}
+	dimensions = make([]string, nDim)
+	for i := range dimensions {
+		dimension, err := domain.DimensionFromIndex(uint(i))
+		if err != nil {
+			return nil, nil, err
+		}
+		dimensions[i], err = dimension.Name()
+		dimension.Free()
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	attrs, err := a.Attributes()
+	if err != nil {
+		return nil, nil, err
+	}
+	attributes = make([]string, len(attrs))
+	for i, attribute := range attrs {
+		attributes[i], err = attribute.Name()
+		attribute.Free()
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return dimensions, attributes, nil
+}
+
 // SetDomain sets the array domain.
 func (a *ArraySchema) SetDomain(domain *Domain) error {
 	ret := C.tiledb_array_schema_set_domain(a.context.tiledbContext.Get(), a.tiledbArraySchema.Get(), domain.tiledbDomain.Get())
@@ -0,0 +1,651 @@
+package tiledb
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// SchemaChangeKind is the kind of a SchemaChange.
+type SchemaChangeKind uint8
+
+const (
+	// SchemaChangeAdded is an object that is only in the new schema
+	SchemaChangeAdded SchemaChangeKind = iota
+	// SchemaChangeRemoved is an object that is only in the old schema
+	SchemaChangeRemoved
+	// SchemaChangeModified is a property of an object that differs between the schemas
+	SchemaChangeModified
+)
+
+// String returns a string representation.
+func (k SchemaChangeKind) String() string {
+	switch k {
+	case SchemaChangeAdded:
+		return "added"
+	case SchemaChangeRemoved:
+		return "removed"
+	case SchemaChangeModified:
+		return "modified"
+	}
+	return "unknown"
+}
+
+// SchemaObject is the part of an array schema a SchemaChange applies to.
+type SchemaObject uint8
+
+const (
+	// SchemaObjectArray is the schema itself: array type, capacity, orders and filters
+	SchemaObjectArray SchemaObject = iota
+	// SchemaObjectDimension is a dimension of the domain
+	SchemaObjectDimension
+	// SchemaObjectAttribute is an attribute
+	SchemaObjectAttribute
+	// SchemaObjectEnumeration is an enumeration used by an attribute
+	SchemaObjectEnumeration
+	// SchemaObjectDimensionLabel is a dimension label
+	SchemaObjectDimensionLabel
+)
+
+// String returns a string representation.
+func (o SchemaObject) String() string {
+	switch o {
+	case SchemaObjectArray:
+		return "array"
+	case SchemaObjectDimension:
+		return "dimension"
+	case SchemaObjectAttribute:
+		return "attribute"
+	case SchemaObjectEnumeration:
+		return "enumeration"
+	case SchemaObjectDimensionLabel:
+		return "dimension label"
+	}
+	return "unknown"
+}
+
+// SchemaChange is a difference between two array schemas, see DiffArraySchemas.
+type SchemaChange struct {
+	Kind   SchemaChangeKind
+	Object SchemaObject
+	// Name is the name of the dimension, attribute, enumeration or dimension label. It is empty for SchemaObjectArray.
+	Name string
+	// Property is the name of the modified property, such as "type", "cell_val_num" or "filters".
+	// It is empty for added and removed objects.
+	Property string
+	// Old and New are the values of the modified property in the old and new schema.
+	// Filter lists are described by strings, enumeration values are slices of the enumeration type.
+	Old, New any
+}
+
+// String returns a string representation.
+func (c SchemaChange) String() string {
+	var b strings.Builder
+	b.WriteString(c.Object.String())
+	if c.Name != "" {
+		b.WriteString(" " + c.Name)
+	}
+	if c.Kind != SchemaChangeModified {
+		b.WriteString(" " + c.Kind.String())
+		return b.String()
+	}
+	fmt.Fprintf(&b, ": %s changed from %v to %v", c.Property, c.Old, c.New)
+	return b.String()
+}
+
+// Evolvable returns whether an ArraySchemaEvolution can apply the change: adding or dropping
+// an attribute, adding or dropping an enumeration, or appending values to an enumeration.
+func (c SchemaChange) Evolvable() bool {
+	switch c.Object {
+	case SchemaObjectAttribute:
+		return c.Kind == SchemaChangeAdded || c.Kind == SchemaChangeRemoved
+	case SchemaObjectEnumeration:
+		if c.Kind == SchemaChangeAdded || c.Kind == SchemaChangeRemoved {
+			return true
+		}
+		return c.Property == "values" && isEnumerationExtension(c.Old, c.New)
+	default:
+		return false
+	}
+}
+
+// isEnumerationExtension returns whether the values newValues start with oldValues and have more values.
+func isEnumerationExtension(oldValues, newValues any) bool {
+	o, n := reflect.ValueOf(oldValues), reflect.ValueOf(newValues)
+	if o.Kind() != reflect.Slice || o.Type() != n.Type() || n.Len() <= o.Len() {
+		return false
+	}
+	return reflect.DeepEqual(oldValues, n.Slice(0, o.Len()).Interface())
+}
+
+/*
+DiffArraySchemas returns the differences from oldSchema to newSchema. It compares:
+  - the array type, capacity, cell and tile orders, duplicates and the coordinates and offsets filters
+  - the dimensions, by name: index, type, domain, tile extent, cell_val_num and filters
+  - the attributes, by name: type, cell_val_num, nullability, fill value, filters and enumeration name
+  - the enumerations used by the attributes, by name: type, cell_val_num, order and values
+  - the dimension labels, by name: dimension index, type, order and cell_val_num
+
+The enumerations of a schema loaded from an array are only available once they are loaded,
+see Array.LoadAllEnumerations. The result is empty if the schemas are equivalent.
+*/
+func DiffArraySchemas(oldSchema, newSchema *ArraySchema) ([]SchemaChange, error) {
+	oldInfo, err := newSchemaInfo(oldSchema)
+	if err != nil {
+		return nil, fmt.Errorf("error reading old schema: %w", err)
+	}
+	newInfo, err := newSchemaInfo(newSchema)
+	if err != nil {
+		return nil, fmt.Errorf("error reading new schema: %w", err)
+	}
+
+	var changes []SchemaChange
+	changes = diffProperties(changes, SchemaObjectArray, "", oldInfo.properties, newInfo.properties)
+	changes = diffObjects(changes, SchemaObjectDimension, oldInfo.dimensions, newInfo.dimensions)
+	changes = diffObjects(changes, SchemaObjectAttribute, oldInfo.attributes, newInfo.attributes)
+	changes = diffObjects(changes, SchemaObjectEnumeration, oldInfo.enumerations, newInfo.enumerations)
+	changes = diffObjects(changes, SchemaObjectDimensionLabel, oldInfo.labels, newInfo.labels)
+
+	return changes, nil
+}
+
+/*
+NewArraySchemaEvolutionFromDiff creates an ArraySchemaEvolution that evolves oldSchema
+towards newSchema, with the changes of DiffArraySchemas that are Evolvable. It returns
+the other changes, which no evolution can apply; the evolution only makes oldSchema equal
+to newSchema if there are none.
+
+	evolution, incompatible, err := tiledb.NewArraySchemaEvolutionFromDiff(tdbCtx, onDisk, inCode)
+	if err != nil {
+		return err
+	}
+	if len(incompatible) > 0 {
+		return fmt.Errorf("cannot evolve schema: %v", incompatible)
+	}
+	return evolution.Evolve(uri)
+*/
+func NewArraySchemaEvolutionFromDiff(tdbCtx *Context, oldSchema, newSchema *ArraySchema) (*ArraySchemaEvolution, []SchemaChange, error) {
+	changes, err := DiffArraySchemas(oldSchema, newSchema)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	evolution, err := NewArraySchemaEvolution(tdbCtx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Enumerations must be added before the attributes using them and dropped after them
+	slices.SortStableFunc(changes, func(a, b SchemaChange) int {
+		return evolveOrder(a) - evolveOrder(b)
+	})
+
+	var incompatible []SchemaChange
+	for _, change := range changes {
+		if !change.Evolvable() {
+			incompatible = append(incompatible, change)
+			continue
+		}
+		if err := evolveChange(tdbCtx, evolution, oldSchema, newSchema, change); err != nil {
+			evolution.Free()
+			return nil, nil, fmt.Errorf("error evolving %v: %w", change, err)
+		}
+	}
+
+	return evolution, incompatible, nil
+}
+
+// evolveOrder ranks the changes applied by an evolution.
+func evolveOrder(change SchemaChange) int {
+	switch {
+	case change.Object != SchemaObjectEnumeration:
+		return 1
+	case change.Kind == SchemaChangeRemoved:
+		return 2
+	default:
+		return 0
+	}
+}
+
+// evolveChange adds the evolvable change to evolution.
+func evolveChange(tdbCtx *Context, evolution *ArraySchemaEvolution, oldSchema, newSchema *ArraySchema, change SchemaChange) error {
+	switch {
+	case change.Object == SchemaObjectAttribute && change.Kind == SchemaChangeAdded:
+		attribute, err := newSchema.AttributeFromName(change.Name)
+		if err != nil {
+			return err
+		}
+		defer attribute.Free()
+		return evolution.AddAttribute(attribute)
+	case change.Object == SchemaObjectAttribute:
+		return evolution.DropAttribute(change.Name)
+	case change.Kind == SchemaChangeAdded:
+		enumeration, err := newSchema.EnumerationFromName(change.Name)
+		if err != nil {
+			return err
+		}
+		defer enumeration.Free()
+		return evolution.AddEnumeration(enumeration)
+	case change.Kind == SchemaChangeRemoved:
+		return evolution.DropEnumeration(change.Name)
+	default:
+		enumeration, err := oldSchema.EnumerationFromName(change.Name)
+		if err != nil {
+			return err
+		}
+		defer enumeration.Free()
+		extended, err := extendEnumerationWith(tdbCtx, enumeration, reflect.ValueOf(change.New).Slice(reflect.ValueOf(change.Old).Len(), reflect.ValueOf(change.New).Len()).Interface())
+		if err != nil {
+			return err
+		}
+		defer extended.Free()
+		return evolution.ApplyExtendedEnumeration(extended)
+	}
+}
+
+// extendEnumerationWith calls ExtendEnumeration with the slice values.
+func extendEnumerationWith(tdbCtx *Context, e *Enumeration, values any) (*Enumeration, error) {
+	switch v := values.(type) {
+	case []string:
+		return ExtendEnumeration(tdbCtx, e, v)
+	case []bool:
+		return ExtendEnumeration(tdbCtx, e, v)
+	case []int8:
+		return ExtendEnumeration(tdbCtx, e, v)
+	case []int16:
+		return ExtendEnumeration(tdbCtx, e, v)
+	case []int32:
+		return ExtendEnumeration(tdbCtx, e, v)
+	case []int64:
+		return ExtendEnumeration(tdbCtx, e, v)
+	case []uint8:
+		return ExtendEnumeration(tdbCtx, e, v)
+	case []uint16:
+		return ExtendEnumeration(tdbCtx, e, v)
+	case []uint32:
+		return ExtendEnumeration(tdbCtx, e, v)
+	case []uint64:
+		return ExtendEnumeration(tdbCtx, e, v)
+	case []float32:
+		return ExtendEnumeration(tdbCtx, e, v)
+	case []float64:
+		return ExtendEnumeration(tdbCtx, e, v)
+	}
+	return nil, fmt.Errorf("unsupported enumeration values %T", values)
+}
+
+// schemaProperty is a named property of a schema object.
+type schemaProperty struct {
+	name  string
+	value any
+}
+
+// schemaObjectInfo holds the properties of a named schema object.
+type schemaObjectInfo struct {
+	name       string
+	properties []schemaProperty
+}
+
+// schemaInfo holds the comparable properties of an array schema.
+type schemaInfo struct {
+	properties   []schemaProperty
+	dimensions   []schemaObjectInfo
+	attributes   []schemaObjectInfo
+	enumerations []schemaObjectInfo
+	labels       []schemaObjectInfo
+}
+
+// diffProperties appends to changes the properties of the object name that differ.
+// Both lists have the same properties in the same order. NaN fill values are equal.
+func diffProperties(changes []SchemaChange, object SchemaObject, name string, oldProperties, newProperties []schemaProperty) []SchemaChange {
+	for i, property := range oldProperties {
+		if !sameSpecValue(property.value, newProperties[i].value) {
+			changes = append(changes, SchemaChange{
+				Kind:     SchemaChangeModified,
+				Object:   object,
+				Name:     name,
+				Property: property.name,
+				Old:      property.value,
+				New:      newProperties[i].value,
+			})
+		}
+	}
+	return changes
+}
+
+// diffObjects appends to changes the objects only in oldObjects, the objects with properties that differ
+// and the objects only in newObjects.
+func diffObjects(changes []SchemaChange, object SchemaObject, oldObjects, newObjects []schemaObjectInfo) []SchemaChange {
+	for _, o := range oldObjects {
+		i := slices.IndexFunc(newObjects, func(n schemaObjectInfo) bool { return n.name == o.name })
+		if i < 0 {
+			changes = append(changes, SchemaChange{Kind: SchemaChangeRemoved, Object: object, Name: o.name})
+			continue
+		}
+		changes = diffProperties(changes, object, o.name, o.properties, newObjects[i].properties)
+	}
+	for _, n := range newObjects {
+		if !slices.ContainsFunc(oldObjects, func(o schemaObjectInfo) bool { return o.name == n.name }) {
+			changes = append(changes, SchemaChange{Kind: SchemaChangeAdded, Object: object, Name: n.name})
+		}
+	}
+	return changes
+}
+
+// newSchemaInfo reads the comparable properties of schema.
+func newSchemaInfo(schema *ArraySchema) (*schemaInfo, error) {
+	var info schemaInfo
+
+	arrayType, err := schema.Type()
+	if err != nil {
+		return nil, err
+	}
+	capacity, err := schema.Capacity()
+	if err != nil {
+		return nil, err
+	}
+	cellOrder, err := schema.CellOrder()
+	if err != nil {
+		return nil, err
+	}
+	tileOrder, err := schema.TileOrder()
+	if err != nil {
+		return nil, err
+	}
+	allowsDups, err := schema.AllowsDups()
+	if err != nil {
+		return nil, err
+	}
+	coordsFilters, err := schemaFilterList(schema.CoordsFilterList())
+	if err != nil {
+		return nil, err
+	}
+	offsetsFilters, err := schemaFilterList(schema.OffsetsFilterList())
+	if err != nil {
+		return nil, err
+	}
+	info.properties = []schemaProperty{
+		{"type", arrayType},
+		{"capacity", capacity},
+		{"cell_order", cellOrder},
+		{"tile_order", tileOrder},
+		{"allows_dups", allowsDups},
+		{"coords_filters", coordsFilters},
+		{"offsets_filters", offsetsFilters},
+	}
+
+	domain, err := schema.Domain()
+	if err != nil {
+		return nil, err
+	}
+	defer domain.Free()
+	nDim, err := domain.NDim()
+	if err != nil {
+		return nil, err
+	}
+	for i := uint(0); i < nDim; i++ {
+		dimension, err := domain.DimensionFromIndex(i)
+		if err != nil {
+			return nil, err
+		}
+		dimensionInfo, err := newDimensionInfo(dimension, i)
+		dimension.Free()
+		if err != nil {
+			return nil, err
+		}
+		info.dimensions = append(info.dimensions, dimensionInfo)
+	}
+
+	attributes, err := schema.Attributes()
+	if err != nil {
+		return nil, err
+	}
+	var enumerationNames []string
+	for _, attribute := range attributes {
+		attributeInfo, enumerationName, err := newAttributeInfo(attribute)
+		attribute.Free()
+		if err != nil {
+			return nil, err
+		}
+		info.attributes = append(info.attributes, attributeInfo)
+		if enumerationName != "" && !slices.Contains(enumerationNames, enumerationName) {
+			enumerationNames = append(enumerationNames, enumerationName)
+		}
+	}
+
+	for _, name := range enumerationNames {
+		enumeration, err := schema.EnumerationFromName(name)
+		if err != nil {
+			return nil, err
+		}
+		enumerationInfo, err := newEnumerationInfo(enumeration, name)
+		enumeration.Free()
+		if err != nil {
+			return nil, err
+		}
+		info.enumerations = append(info.enumerations, enumerationInfo)
+	}
+
+	labelsNum, err := schema.DimensionLabelsNum()
+	if err != nil {
+		return nil, err
+	}
+	for i := uint64(0); i < labelsNum; i++ {
+		label, err := schema.DimensionLabelFromIndex(i)
+		if err != nil {
+			return nil, err
+		}
+		labelInfo, err := newDimensionLabelInfo(label)
+		label.Free()
+		if err != nil {
+			return nil, err
+		}
+		info.labels = append(info.labels, labelInfo)
+	}
+
+	return &info, nil
+}
+
+func newDimensionInfo(dimension *Dimension, index uint) (schemaObjectInfo, error) {
+	name, err := dimension.Name()
+	if err != nil {
+		return schemaObjectInfo{}, err
+	}
+	datatype, err := dimension.Type()
+	if err != nil {
+		return schemaObjectInfo{}, err
+	}
+	domain, err := dimension.Domain()
+	if err != nil {
+		return schemaObjectInfo{}, err
+	}
+	extent, err := dimension.Extent()
+	if err != nil {
+		return schemaObjectInfo{}, err
+	}
+	cellValNum, err := dimension.CellValNum()
+	if err != nil {
+		return schemaObjectInfo{}, err
+	}
+	filters, err := schemaFilterList(dimension.FilterList())
+	if err != nil {
+		return schemaObjectInfo{}, err
+	}
+
+	return schemaObjectInfo{name: name, properties: []schemaProperty{
+		{"index", index},
+		{"type", datatype},
+		{"domain", domain},
+		{"tile_extent", extent},
+		{"cell_val_num", cellValNum},
+		{"filters", filters},
+	}}, nil
+}
+
+// newAttributeInfo returns the properties of attribute and the name of its enumeration.
+func newAttributeInfo(attribute *Attribute) (schemaObjectInfo, string, error) {
+	name, err := attribute.Name()
+	if err != nil {
+		return schemaObjectInfo{}, "", err
+	}
+	datatype, err := attribute.Type()
+	if err != nil {
+		return schemaObjectInfo{}, "", err
+	}
+	cellValNum, err := attribute.CellValNum()
+	if err != nil {
+		return schemaObjectInfo{}, "", err
+	}
+	nullable, err := attribute.Nullable()
+	if err != nil {
+		return schemaObjectInfo{}, "", err
+	}
+	var fillValue any
+	if nullable {
+		value, _, valid, err := attribute.GetFillValueNullable()
+		if err != nil {
+			return schemaObjectInfo{}, "", err
+		}
+		if valid {
+			fillValue = value
+		}
+	} else {
+		fillValue, _, err = attribute.GetFillValue()
+		if err != nil {
+			return schemaObjectInfo{}, "", err
+		}
+	}
+	filters, err := schemaFilterList(attribute.FilterList())
+	if err != nil {
+		return schemaObjectInfo{}, "", err
+	}
+	enumerationName, err := attribute.GetEnumerationName()
+	if err != nil {
+		return schemaObjectInfo{}, "", err
+	}
+
+	return schemaObjectInfo{name: name, properties: []schemaProperty{
+		{"type", datatype},
+		{"cell_val_num", cellValNum},
+		{"nullable", nullable},
+		{"fill_value", fillValue},
+		{"filters", filters},
+		{"enumeration", enumerationName},
+	}}, enumerationName, nil
+}
+
+func newEnumerationInfo(enumeration *Enumeration, name string) (schemaObjectInfo, error) {
+	datatype, err := enumeration.Type()
+	if err != nil {
+		return schemaObjectInfo{}, err
+	}
+	cellValNum, err := enumeration.CellValNum()
+	if err != nil {
+		return schemaObjectInfo{}, err
+	}
+	ordered, err := enumeration.IsOrdered()
+	if err != nil {
+		return schemaObjectInfo{}, err
+	}
+	values, err := enumeration.Values()
+	if err != nil {
+		return schemaObjectInfo{}, err
+	}
+
+	return schemaObjectInfo{name: name, properties: []schemaProperty{
+		{"type", datatype},
+		{"cell_val_num", cellValNum},
+		{"ordered", ordered},
+		{"values", values},
+	}}, nil
+}
+
+func newDimensionLabelInfo(label *DimensionLabel) (schemaObjectInfo, error) {
+	name, err := label.Name()
+	if err != nil {
+		return schemaObjectInfo{}, err
+	}
+	dimensionIndex, err := label.DimensionIndex()
+	if err != nil {
+		return schemaObjectInfo{}, err
+	}
+	datatype, err := label.Type()
+	if err != nil {
+		return schemaObjectInfo{}, err
+	}
+	order, err := label.Order()
+	if err != nil {
+		return schemaObjectInfo{}, err
+	}
+	cellValNum, err := label.CellValNum()
+	if err != nil {
+		return schemaObjectInfo{}, err
+	}
+
+	return schemaObjectInfo{name: name, properties: []schemaProperty{
+		{"dimension_index", dimensionIndex},
+		{"type", datatype},
+		{"order", order},
+		{"cell_val_num", cellValNum},
+	}}, nil
+}
+
+// schemaFilterList describes the filter list returned by a getter, for comparisons.
+func schemaFilterList(filterList *FilterList, err error) (string, error) {
+	if err != nil {
+		return "", err
+	}
+	defer filterList.Free()
+	return describeFilterList(filterList)
+}
+
+// describeFilterList returns a description of the filters of filterList and their options,
+// for example "ZSTD(COMPRESSION_LEVEL=5), BYTESHUFFLE".
+func describeFilterList(filterList *FilterList) (string, error) {
+	filters, err := filterList.Filters()
+	if err != nil {
+		return "", err
+	}
+
+	descriptions := make([]string, len(filters))
+	for i, filter := range filters {
+		descriptions[i], err = describeFilter(filter)
+		filter.Free()
+		if err != nil {
+			return "", err
+		}
+	}
+
+	maxChunkSize, err := filterList.MaxChunkSize()
+	if err != nil {
+		return "", err
+	}
+	if len(descriptions) > 0 {
+		descriptions = append(descriptions, fmt.Sprintf("max_chunk_size=%d", maxChunkSize))
+	}
+
+	return strings.Join(descriptions, ", "), nil
+}
+
+func describeFilter(filter *Filter) (string, error) {
+	filterType, err := filter.Type()
+	if err != nil {
+		return "", err
+	}
+
+	options := filterTypeOptions(filterType)
+	if len(options) == 0 {
+		return filterType.String(), nil
+	}
+
+	values := make([]string, len(options))
+	for i, option := range options {
+		value, err := filter.Option(option)
+		if err != nil {
+			return "", err
+		}
+		values[i] = fmt.Sprintf("%s=%v", option, value)
+	}
+	return fmt.Sprintf("%s(%s)", filterType, strings.Join(values, ", ")), nil
+}
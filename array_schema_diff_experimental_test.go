@@ -0,0 +1,109 @@
+package tiledb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffArraySchemas(t *testing.T) {
+	tdbCtx, err := NewContext(nil)
+	require.NoError(t, err)
+
+	oldSchema := diffTestSchema(t, tdbCtx, 100, []string{"red", "green"}, "a")
+	newSchema := diffTestSchema(t, tdbCtx, 200, []string{"red", "green", "blue"}, "b")
+
+	changes, err := DiffArraySchemas(oldSchema, oldSchema)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+
+	// The float attribute has the NaN default fill value in both schemas
+	same := diffTestSchema(t, tdbCtx, 100, []string{"red", "green"}, "a")
+	changes, err = DiffArraySchemas(oldSchema, same)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+	evolution, incompatible, err := NewArraySchemaEvolutionFromDiff(tdbCtx, oldSchema, same)
+	require.NoError(t, err)
+	evolution.Free()
+	assert.Empty(t, incompatible)
+
+	changes, err = DiffArraySchemas(oldSchema, newSchema)
+	require.NoError(t, err)
+	assert.Equal(t, []SchemaChange{
+		{Kind: SchemaChangeModified, Object: SchemaObjectArray, Property: "capacity", Old: uint64(100), New: uint64(200)},
+		{Kind: SchemaChangeRemoved, Object: SchemaObjectAttribute, Name: "a"},
+		{Kind: SchemaChangeAdded, Object: SchemaObjectAttribute, Name: "b"},
+		{Kind: SchemaChangeModified, Object: SchemaObjectEnumeration, Name: "colors", Property: "values",
+			Old: []string{"red", "green"}, New: []string{"red", "green", "blue"}},
+	}, changes)
+	assert.Equal(t, "array: capacity changed from 100 to 200", changes[0].String())
+	assert.Equal(t, "attribute a removed", changes[1].String())
+	assert.False(t, changes[0].Evolvable())
+	assert.True(t, changes[1].Evolvable())
+	assert.True(t, changes[2].Evolvable())
+	assert.True(t, changes[3].Evolvable())
+
+	reordered := diffTestSchema(t, tdbCtx, 100, []string{"green", "red"}, "a")
+	changes, err = DiffArraySchemas(oldSchema, reordered)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	assert.False(t, changes[0].Evolvable())
+
+	t.Run("Evolution", func(t *testing.T) {
+		arrayPath := t.TempDir()
+		require.NoError(t, CreateArray(tdbCtx, arrayPath, oldSchema))
+
+		array, err := NewArray(tdbCtx, arrayPath)
+		require.NoError(t, err)
+		require.NoError(t, array.Open(TILEDB_READ))
+		require.NoError(t, array.LoadAllEnumerations())
+		onDisk, err := array.Schema()
+		require.NoError(t, err)
+
+		evolution, incompatible, err := NewArraySchemaEvolutionFromDiff(tdbCtx, onDisk, newSchema)
+		require.NoError(t, err)
+		require.NoError(t, array.Close())
+		defer evolution.Free()
+		require.Len(t, incompatible, 1)
+		assert.Equal(t, "capacity", incompatible[0].Property)
+
+		require.NoError(t, evolution.Evolve(arrayPath))
+
+		require.NoError(t, array.Open(TILEDB_READ))
+		defer func() { require.NoError(t, array.Close()) }()
+		require.NoError(t, array.LoadAllEnumerations())
+		evolved, err := array.Schema()
+		require.NoError(t, err)
+
+		changes, err := DiffArraySchemas(evolved, newSchema)
+		require.NoError(t, err)
+		assert.Equal(t, incompatible, changes)
+	})
+}
+
+func diffTestSchema(t testing.TB, tdbCtx *Context, capacity uint64, colors []string, attributeName string) *ArraySchema {
+	dimension, err := NewDimension(tdbCtx, "id", TILEDB_INT32, []int32{1, 100}, int32(10))
+	require.NoError(t, err)
+	domain, err := NewDomain(tdbCtx)
+	require.NoError(t, err)
+	require.NoError(t, domain.AddDimensions(dimension))
+
+	schema, err := NewArraySchema(tdbCtx, TILEDB_SPARSE)
+	require.NoError(t, err)
+	require.NoError(t, schema.SetDomain(domain))
+	require.NoError(t, schema.SetCapacity(capacity))
+
+	enumeration, err := NewUnorderedEnumeration(tdbCtx, "colors", colors)
+	require.NoError(t, err)
+	require.NoError(t, schema.AddEnumeration(enumeration))
+
+	color, err := NewAttribute(tdbCtx, "color", TILEDB_UINT8)
+	require.NoError(t, err)
+	require.NoError(t, color.SetEnumerationName("colors"))
+	attribute, err := NewAttribute(tdbCtx, attributeName, TILEDB_FLOAT64)
+	require.NoError(t, err)
+	require.NoError(t, schema.AddAttributes(color, attribute))
+
+	return schema
+}
@@ -22,3 +22,16 @@ func (a *ArraySchema) TimestampRange() (uint64, uint64, error) {
 	}
 	return uint64(lo), uint64(hi), nil
 }
+
+// Version returns the format version of the array schema. It is the format version
+// of the array when the schema was written, and can be compared with the version of a newly
+// created schema to find arrays that need UpgradeArrayVersion.
+func (a *ArraySchema) Version() (uint32, error) {
+	var version C.uint32_t
+	ret := C.tiledb_array_schema_get_version(a.context.tiledbContext.Get(), a.tiledbArraySchema.Get(), &version)
+	runtime.KeepAlive(a)
+	if ret != C.TILEDB_OK {
+		return 0, fmt.Errorf("error getting array schema version: %w", a.context.LastError())
+	}
+	return uint32(version), nil
+}
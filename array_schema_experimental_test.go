@@ -57,3 +57,15 @@ func TestArraySchemaAtTime(t *testing.T) {
 	require.EqualValues(t, lo, start)
 	require.EqualValues(t, hi, end)
 }
+
+func TestArraySchemaVersion(t *testing.T) {
+	context, err := NewContext(nil)
+	require.NoError(t, err)
+
+	arraySchema, err := NewArraySchema(context, TILEDB_SPARSE)
+	require.NoError(t, err)
+
+	version, err := arraySchema.Version()
+	require.NoError(t, err)
+	assert.NotZero(t, version)
+}
@@ -0,0 +1,882 @@
+package tiledb
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+/*
+ArraySchemaSpec is a declarative definition of an array schema, meant to be written by hand
+in YAML or JSON and kept in version control:
+
+	type: sparse
+	cell_order: row-major
+	tile_order: row-major
+	capacity: 10000
+	dimensions:
+	  - name: id
+	    type: INT32
+	    domain: [1, 1000]
+	    tile_extent: 100
+	  - name: key
+	    type: STRING_ASCII
+	enumerations:
+	  - name: colors
+	    type: STRING_ASCII
+	    values: [red, green, blue]
+	attributes:
+	  - name: name
+	    type: STRING_UTF8
+	    var: true
+	    filters:
+	      - type: ZSTD
+	        options: {COMPRESSION_LEVEL: 5}
+	  - name: count
+	    type: INT64
+	    nullable: true
+	    fill_value: 0
+	  - name: color
+	    type: UINT8
+	    enumeration: colors
+
+Types, orders, filters and filter options use the names of the TileDB C API, see
+DatatypeFromString, LayoutFromString, FilterTypeFromString and FilterOptionFromString.
+Use ParseArraySchemaSpec to read a spec, NewArraySchemaFromSpec to build the schema
+and ArraySchema.Spec to get the spec of an existing schema.
+*/
+type ArraySchemaSpec struct {
+	// Type is "dense" or "sparse".
+	Type       string `json:"type" yaml:"type"`
+	CellOrder  string `json:"cell_order,omitempty" yaml:"cell_order,omitempty"`
+	TileOrder  string `json:"tile_order,omitempty" yaml:"tile_order,omitempty"`
+	Capacity   uint64 `json:"capacity,omitempty" yaml:"capacity,omitempty"`
+	AllowsDups bool   `json:"allows_dups,omitempty" yaml:"allows_dups,omitempty"`
+
+	Dimensions     []DimensionSpec   `json:"dimensions" yaml:"dimensions"`
+	Attributes     []AttributeSpec   `json:"attributes" yaml:"attributes"`
+	Enumerations   []EnumerationSpec `json:"enumerations,omitempty" yaml:"enumerations,omitempty"`
+	CoordsFilters  []FilterSpec      `json:"coords_filters,omitempty" yaml:"coords_filters,omitempty"`
+	OffsetsFilters []FilterSpec      `json:"offsets_filters,omitempty" yaml:"offsets_filters,omitempty"`
+}
+
+// DimensionSpec is the declarative definition of a dimension. Domain and TileExtent
+// are required, except for STRING_ASCII dimensions which have neither.
+type DimensionSpec struct {
+	Name       string       `json:"name" yaml:"name"`
+	Type       string       `json:"type" yaml:"type"`
+	Domain     []any        `json:"domain,omitempty" yaml:"domain,omitempty,flow"`
+	TileExtent any          `json:"tile_extent,omitempty" yaml:"tile_extent,omitempty"`
+	Filters    []FilterSpec `json:"filters,omitempty" yaml:"filters,omitempty"`
+}
+
+// AttributeSpec is the declarative definition of an attribute. An attribute has a single
+// value per cell unless Var or CellValNum is set. FillValue can only be set on attributes
+// with a single value per cell.
+type AttributeSpec struct {
+	Name        string       `json:"name" yaml:"name"`
+	Type        string       `json:"type" yaml:"type"`
+	CellValNum  uint32       `json:"cell_val_num,omitempty" yaml:"cell_val_num,omitempty"`
+	Var         bool         `json:"var,omitempty" yaml:"var,omitempty"`
+	Nullable    bool         `json:"nullable,omitempty" yaml:"nullable,omitempty"`
+	FillValue   any          `json:"fill_value,omitempty" yaml:"fill_value,omitempty"`
+	Enumeration string       `json:"enumeration,omitempty" yaml:"enumeration,omitempty"`
+	Filters     []FilterSpec `json:"filters,omitempty" yaml:"filters,omitempty"`
+}
+
+// EnumerationSpec is the declarative definition of an enumeration. Type is STRING_ASCII,
+// BOOL or a fixed size numeric type.
+type EnumerationSpec struct {
+	Name    string `json:"name" yaml:"name"`
+	Type    string `json:"type" yaml:"type"`
+	Ordered bool   `json:"ordered,omitempty" yaml:"ordered,omitempty"`
+	Values  []any  `json:"values" yaml:"values,flow"`
+}
+
+// FilterSpec is the declarative definition of a filter. Options maps option names
+// to their values, the options not set keep their default values.
+// TILEDB_COMPRESSION_REINTERPRET_DATATYPE takes a datatype name.
+type FilterSpec struct {
+	Type    string         `json:"type" yaml:"type"`
+	Options map[string]any `json:"options,omitempty" yaml:"options,omitempty,flow"`
+}
+
+// ParseArraySchemaSpec parses a spec in YAML or JSON. Unknown fields are an error.
+func ParseArraySchemaSpec(data []byte) (*ArraySchemaSpec, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+
+	var spec ArraySchemaSpec
+	if err := decoder.Decode(&spec); err != nil {
+		return nil, fmt.Errorf("error parsing array schema spec: %w", err)
+	}
+	return &spec, nil
+}
+
+// NewArraySchemaFromSpec creates an array schema from spec. The schema is checked before it is returned.
+func NewArraySchemaFromSpec(tdbCtx *Context, spec *ArraySchemaSpec) (*ArraySchema, error) {
+	arrayType, err := ArrayTypeFromString(spec.Type)
+	if err != nil {
+		return nil, fmt.Errorf("error creating array schema from spec: %w", err)
+	}
+
+	schema, err := NewArraySchema(tdbCtx, arrayType)
+	if err != nil {
+		return nil, err
+	}
+	if err := setArraySchemaSpec(tdbCtx, schema, spec); err != nil {
+		schema.Free()
+		return nil, fmt.Errorf("error creating array schema from spec: %w", err)
+	}
+
+	return schema, nil
+}
+
+func setArraySchemaSpec(tdbCtx *Context, schema *ArraySchema, spec *ArraySchemaSpec) error {
+	if spec.CellOrder != "" {
+		cellOrder, err := LayoutFromString(spec.CellOrder)
+		if err != nil {
+			return err
+		}
+		if err := schema.SetCellOrder(cellOrder); err != nil {
+			return err
+		}
+	}
+	if spec.TileOrder != "" {
+		tileOrder, err := LayoutFromString(spec.TileOrder)
+		if err != nil {
+			return err
+		}
+		if err := schema.SetTileOrder(tileOrder); err != nil {
+			return err
+		}
+	}
+	if spec.Capacity != 0 {
+		if err := schema.SetCapacity(spec.Capacity); err != nil {
+			return err
+		}
+	}
+	if spec.AllowsDups {
+		if err := schema.SetAllowsDups(true); err != nil {
+			return err
+		}
+	}
+
+	domain, err := NewDomain(tdbCtx)
+	if err != nil {
+		return err
+	}
+	defer domain.Free()
+	for _, dimensionSpec := range spec.Dimensions {
+		dimension, err := newDimensionFromSpec(tdbCtx, dimensionSpec)
+		if err != nil {
+			return fmt.Errorf("dimension %s: %w", dimensionSpec.Name, err)
+		}
+		err = domain.AddDimensions(dimension)
+		dimension.Free()
+		if err != nil {
+			return err
+		}
+	}
+	if err := schema.SetDomain(domain); err != nil {
+		return err
+	}
+
+	for _, enumerationSpec := range spec.Enumerations {
+		enumeration, err := newEnumerationFromSpec(tdbCtx, enumerationSpec)
+		if err != nil {
+			return fmt.Errorf("enumeration %s: %w", enumerationSpec.Name, err)
+		}
+		err = schema.AddEnumeration(enumeration)
+		enumeration.Free()
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, attributeSpec := range spec.Attributes {
+		attribute, err := newAttributeFromSpec(tdbCtx, attributeSpec)
+		if err != nil {
+			return fmt.Errorf("attribute %s: %w", attributeSpec.Name, err)
+		}
+		err = schema.AddAttributes(attribute)
+		attribute.Free()
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(spec.CoordsFilters) > 0 {
+		filterList, err := newFilterListFromSpec(tdbCtx, spec.CoordsFilters)
+		if err != nil {
+			return fmt.Errorf("coords filters: %w", err)
+		}
+		defer filterList.Free()
+		if err := schema.SetCoordsFilterList(filterList); err != nil {
+			return err
+		}
+	}
+	if len(spec.OffsetsFilters) > 0 {
+		filterList, err := newFilterListFromSpec(tdbCtx, spec.OffsetsFilters)
+		if err != nil {
+			return fmt.Errorf("offsets filters: %w", err)
+		}
+		defer filterList.Free()
+		if err := schema.SetOffsetsFilterList(filterList); err != nil {
+			return err
+		}
+	}
+
+	return schema.Check()
+}
+
+func newDimensionFromSpec(tdbCtx *Context, spec DimensionSpec) (*Dimension, error) {
+	datatype, err := DatatypeFromString(spec.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	var dimension *Dimension
+	if datatype == TILEDB_STRING_ASCII {
+		if spec.Domain != nil || spec.TileExtent != nil {
+			return nil, errors.New("string dimensions have no domain and tile extent")
+		}
+		dimension, err = NewStringDimension(tdbCtx, spec.Name)
+	} else {
+		if len(spec.Domain) != 2 || spec.TileExtent == nil {
+			return nil, errors.New("a domain of two values and a tile extent are required")
+		}
+		valueType := datatype.ReflectType()
+		domain := reflect.MakeSlice(reflect.SliceOf(valueType), 2, 2)
+		for i, bound := range spec.Domain {
+			value, err := specValue(bound, valueType)
+			if err != nil {
+				return nil, fmt.Errorf("domain: %w", err)
+			}
+			domain.Index(i).Set(value)
+		}
+		extent, err := specValue(spec.TileExtent, valueType)
+		if err != nil {
+			return nil, fmt.Errorf("tile extent: %w", err)
+		}
+		dimension, err = NewDimension(tdbCtx, spec.Name, datatype, domain.Interface(), extent.Interface())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(spec.Filters) > 0 {
+		filterList, err := newFilterListFromSpec(tdbCtx, spec.Filters)
+		if err == nil {
+			err = dimension.SetFilterList(filterList)
+			filterList.Free()
+		}
+		if err != nil {
+			dimension.Free()
+			return nil, err
+		}
+	}
+
+	return dimension, nil
+}
+
+func newAttributeFromSpec(tdbCtx *Context, spec AttributeSpec) (*Attribute, error) {
+	datatype, err := DatatypeFromString(spec.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	attribute, err := NewAttribute(tdbCtx, spec.Name, datatype)
+	if err != nil {
+		return nil, err
+	}
+	if err := setAttributeSpec(tdbCtx, attribute, datatype, spec); err != nil {
+		attribute.Free()
+		return nil, err
+	}
+
+	return attribute, nil
+}
+
+func setAttributeSpec(tdbCtx *Context, attribute *Attribute, datatype Datatype, spec AttributeSpec) error {
+	switch {
+	case spec.Var && spec.CellValNum != 0:
+		return errors.New("var and cell_val_num are exclusive")
+	case spec.Var:
+		if err := attribute.SetCellValNum(TILEDB_VAR_NUM); err != nil {
+			return err
+		}
+	case spec.CellValNum != 0:
+		if err := attribute.SetCellValNum(spec.CellValNum); err != nil {
+			return err
+		}
+	}
+
+	if spec.Nullable {
+		if err := attribute.SetNullable(true); err != nil {
+			return err
+		}
+	}
+
+	if spec.FillValue != nil {
+		if spec.Var || spec.CellValNum > 1 {
+			return errors.New("fill_value requires a single value per cell")
+		}
+		value, err := specFillValue(spec.FillValue, datatype)
+		if err != nil {
+			return fmt.Errorf("fill value: %w", err)
+		}
+		if spec.Nullable {
+			err = attribute.SetFillValueNullable(value, true)
+		} else {
+			err = attribute.SetFillValue(value)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if spec.Enumeration != "" {
+		if err := attribute.SetEnumerationName(spec.Enumeration); err != nil {
+			return err
+		}
+	}
+
+	if len(spec.Filters) > 0 {
+		filterList, err := newFilterListFromSpec(tdbCtx, spec.Filters)
+		if err != nil {
+			return err
+		}
+		defer filterList.Free()
+		if err := attribute.SetFilterList(filterList); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// specFillValue converts the fill value of a spec to the Go type SetFillValue expects for datatype.
+func specFillValue(value any, datatype Datatype) (any, error) {
+	switch datatype {
+	case TILEDB_CHAR, TILEDB_STRING_ASCII, TILEDB_STRING_UTF8:
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("%v is not a string", value)
+		}
+		return s, nil
+	case TILEDB_BOOL:
+		b, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("%v is not a bool", value)
+		}
+		return b, nil
+	}
+
+	v, err := specValue(value, datatype.ReflectType())
+	if err != nil {
+		return nil, err
+	}
+	return v.Interface(), nil
+}
+
+func newEnumerationFromSpec(tdbCtx *Context, spec EnumerationSpec) (*Enumeration, error) {
+	datatype, err := DatatypeFromString(spec.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	var valueType reflect.Type
+	switch datatype {
+	case TILEDB_STRING_ASCII:
+		valueType = reflect.TypeOf("")
+	case TILEDB_BOOL:
+		valueType = reflect.TypeOf(false)
+	case TILEDB_INT8, TILEDB_INT16, TILEDB_INT32, TILEDB_INT64, TILEDB_UINT8, TILEDB_UINT16, TILEDB_UINT32, TILEDB_UINT64, TILEDB_FLOAT32, TILEDB_FLOAT64:
+		valueType = datatype.ReflectType()
+	default:
+		return nil, fmt.Errorf("unsupported enumeration type %s", datatype)
+	}
+
+	values := reflect.MakeSlice(reflect.SliceOf(valueType), len(spec.Values), len(spec.Values))
+	for i, element := range spec.Values {
+		value, err := specValue(element, valueType)
+		if err != nil {
+			return nil, fmt.Errorf("value %d: %w", i, err)
+		}
+		values.Index(i).Set(value)
+	}
+
+	switch v := values.Interface().(type) {
+	case []string:
+		return newEnumeration(tdbCtx, spec.Name, spec.Ordered, v)
+	case []bool:
+		return newEnumeration(tdbCtx, spec.Name, spec.Ordered, v)
+	case []int8:
+		return newEnumeration(tdbCtx, spec.Name, spec.Ordered, v)
+	case []int16:
+		return newEnumeration(tdbCtx, spec.Name, spec.Ordered, v)
+	case []int32:
+		return newEnumeration(tdbCtx, spec.Name, spec.Ordered, v)
+	case []int64:
+		return newEnumeration(tdbCtx, spec.Name, spec.Ordered, v)
+	case []uint8:
+		return newEnumeration(tdbCtx, spec.Name, spec.Ordered, v)
+	case []uint16:
+		return newEnumeration(tdbCtx, spec.Name, spec.Ordered, v)
+	case []uint32:
+		return newEnumeration(tdbCtx, spec.Name, spec.Ordered, v)
+	case []uint64:
+		return newEnumeration(tdbCtx, spec.Name, spec.Ordered, v)
+	case []float32:
+		return newEnumeration(tdbCtx, spec.Name, spec.Ordered, v)
+	default:
+		return newEnumeration(tdbCtx, spec.Name, spec.Ordered, v.([]float64))
+	}
+}
+
+func newFilterListFromSpec(tdbCtx *Context, specs []FilterSpec) (*FilterList, error) {
+	filterList, err := NewFilterList(tdbCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, spec := range specs {
+		filter, err := newFilterFromSpec(tdbCtx, spec)
+		if err != nil {
+			filterList.Free()
+			return nil, fmt.Errorf("filter %s: %w", spec.Type, err)
+		}
+		err = filterList.AddFilter(filter)
+		filter.Free()
+		if err != nil {
+			filterList.Free()
+			return nil, err
+		}
+	}
+
+	return filterList, nil
+}
+
+func newFilterFromSpec(tdbCtx *Context, spec FilterSpec) (*Filter, error) {
+	filterType, err := FilterTypeFromString(spec.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	filter, err := NewFilter(tdbCtx, filterType)
+	if err != nil {
+		return nil, err
+	}
+
+	for name, specOption := range spec.Options {
+		option, err := FilterOptionFromString(name)
+		if err == nil {
+			err = setFilterSpecOption(filter, option, specOption)
+		}
+		if err != nil {
+			filter.Free()
+			return nil, fmt.Errorf("option %s: %w", name, err)
+		}
+	}
+
+	return filter, nil
+}
+
+// setFilterSpecOption sets option on filter, converting value to the type of the current value of the option.
+func setFilterSpecOption(filter *Filter, option FilterOption, value any) error {
+	current, err := filter.Option(option)
+	if err != nil {
+		return err
+	}
+	if current == nil {
+		return fmt.Errorf("unsupported filter option %s", option)
+	}
+
+	if _, ok := current.(Datatype); ok {
+		name, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("%v is not a datatype name", value)
+		}
+		datatype, err := DatatypeFromString(name)
+		if err != nil {
+			return err
+		}
+		return filter.SetOption(option, datatype)
+	}
+
+	v, err := specValue(value, reflect.TypeOf(current))
+	if err != nil {
+		return err
+	}
+	return filter.SetOption(option, v.Interface())
+}
+
+// specValue converts a value decoded from YAML or JSON to type t. Numbers can be converted
+// to any numeric type as long as their value is kept, except for the precision of floats.
+func specValue(value any, t reflect.Type) (reflect.Value, error) {
+	v := reflect.ValueOf(value)
+	if !v.IsValid() {
+		return reflect.Value{}, fmt.Errorf("missing %s value", t)
+	}
+	if v.Type() == t {
+		return v, nil
+	}
+
+	if isSpecNumber(v.Kind()) && isSpecNumber(t.Kind()) {
+		converted := v.Convert(t)
+		switch t.Kind() {
+		case reflect.Float32, reflect.Float64:
+			return converted, nil
+		}
+		// Negative values wrap around when converted to unsigned types and back,
+		// and large unsigned values when converted to signed types and back.
+		negative := (v.CanInt() && v.Int() < 0) || (v.CanFloat() && v.Float() < 0)
+		if negative == (converted.CanInt() && converted.Int() < 0) && converted.Convert(v.Type()).Interface() == v.Interface() {
+			return converted, nil
+		}
+		return reflect.Value{}, fmt.Errorf("%v does not fit in %s", value, t)
+	}
+
+	return reflect.Value{}, fmt.Errorf("%v (%T) cannot be used as %s", value, value, t)
+}
+
+func isSpecNumber(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// Spec returns the declarative definition of the schema. Filter options and fill values
+// are only included when they differ from their defaults. The max chunk size of filter
+// lists and dimension labels are not part of the spec.
+//
+// The enumerations of a schema loaded from an array are only available once they are loaded,
+// see Array.LoadAllEnumerations.
+func (a *ArraySchema) Spec() (*ArraySchemaSpec, error) {
+	spec, err := arraySchemaSpec(a)
+	if err != nil {
+		return nil, fmt.Errorf("error getting array schema spec: %w", err)
+	}
+	return spec, nil
+}
+
+func arraySchemaSpec(a *ArraySchema) (*ArraySchemaSpec, error) {
+	var spec ArraySchemaSpec
+
+	arrayType, err := a.Type()
+	if err != nil {
+		return nil, err
+	}
+	spec.Type = arrayType.String()
+	cellOrder, err := a.CellOrder()
+	if err != nil {
+		return nil, err
+	}
+	spec.CellOrder = cellOrder.String()
+	tileOrder, err := a.TileOrder()
+	if err != nil {
+		return nil, err
+	}
+	spec.TileOrder = tileOrder.String()
+	if spec.Capacity, err = a.Capacity(); err != nil {
+		return nil, err
+	}
+	if spec.AllowsDups, err = a.AllowsDups(); err != nil {
+		return nil, err
+	}
+	if spec.CoordsFilters, err = filterListSpec(a.CoordsFilterList()); err != nil {
+		return nil, err
+	}
+	if spec.OffsetsFilters, err = filterListSpec(a.OffsetsFilterList()); err != nil {
+		return nil, err
+	}
+
+	domain, err := a.Domain()
+	if err != nil {
+		return nil, err
+	}
+	defer domain.Free()
+	nDim, err := domain.NDim()
+	if err != nil {
+		return nil, err
+	}
+	for i := uint(0); i < nDim; i++ {
+		dimension, err := domain.DimensionFromIndex(i)
+		if err != nil {
+			return nil, err
+		}
+		dimensionSpec, err := dimensionSpec(dimension)
+		dimension.Free()
+		if err != nil {
+			return nil, err
+		}
+		spec.Dimensions = append(spec.Dimensions, dimensionSpec)
+	}
+
+	attributes, err := a.Attributes()
+	if err != nil {
+		return nil, err
+	}
+	for _, attribute := range attributes {
+		attributeSpec, err := attributeSpec(a.context, attribute)
+		attribute.Free()
+		if err != nil {
+			return nil, err
+		}
+		spec.Attributes = append(spec.Attributes, attributeSpec)
+
+		if attributeSpec.Enumeration == "" || slices.ContainsFunc(spec.Enumerations, func(e EnumerationSpec) bool { return e.Name == attributeSpec.Enumeration }) {
+			continue
+		}
+		enumeration, err := a.EnumerationFromName(attributeSpec.Enumeration)
+		if err != nil {
+			return nil, err
+		}
+		enumerationSpec, err := enumerationSpec(enumeration, attributeSpec.Enumeration)
+		enumeration.Free()
+		if err != nil {
+			return nil, err
+		}
+		spec.Enumerations = append(spec.Enumerations, enumerationSpec)
+	}
+
+	return &spec, nil
+}
+
+func dimensionSpec(dimension *Dimension) (DimensionSpec, error) {
+	var spec DimensionSpec
+	var err error
+
+	if spec.Name, err = dimension.Name(); err != nil {
+		return spec, err
+	}
+	datatype, err := dimension.Type()
+	if err != nil {
+		return spec, err
+	}
+	spec.Type = datatype.String()
+	if spec.Filters, err = filterListSpec(dimension.FilterList()); err != nil {
+		return spec, err
+	}
+	if datatype == TILEDB_STRING_ASCII {
+		return spec, nil
+	}
+
+	domain, err := dimension.Domain()
+	if err != nil {
+		return spec, err
+	}
+	domainValue := reflect.ValueOf(domain)
+	spec.Domain = []any{domainValue.Index(0).Interface(), domainValue.Index(1).Interface()}
+	if spec.TileExtent, err = dimension.Extent(); err != nil {
+		return spec, err
+	}
+
+	return spec, nil
+}
+
+func attributeSpec(tdbCtx *Context, attribute *Attribute) (AttributeSpec, error) {
+	var spec AttributeSpec
+	var err error
+
+	if spec.Name, err = attribute.Name(); err != nil {
+		return spec, err
+	}
+	datatype, err := attribute.Type()
+	if err != nil {
+		return spec, err
+	}
+	spec.Type = datatype.String()
+	cellValNum, err := attribute.CellValNum()
+	if err != nil {
+		return spec, err
+	}
+	switch cellValNum {
+	case TILEDB_VAR_NUM:
+		spec.Var = true
+	case 1:
+	default:
+		spec.CellValNum = cellValNum
+	}
+	if spec.Nullable, err = attribute.Nullable(); err != nil {
+		return spec, err
+	}
+	if spec.Enumeration, err = attribute.GetEnumerationName(); err != nil {
+		return spec, err
+	}
+	if spec.Filters, err = filterListSpec(attribute.FilterList()); err != nil {
+		return spec, err
+	}
+
+	if cellValNum == 1 {
+		fillValue, err := attributeFillValueSpec(attribute, datatype, spec.Nullable)
+		if err != nil {
+			return spec, err
+		}
+		// Compare with the fill value of a new attribute to only keep non default values
+		defaultAttribute, err := NewAttribute(tdbCtx, spec.Name, datatype)
+		if err != nil {
+			return spec, err
+		}
+		defer defaultAttribute.Free()
+		if spec.Nullable {
+			if err := defaultAttribute.SetNullable(true); err != nil {
+				return spec, err
+			}
+		}
+		defaultFillValue, err := attributeFillValueSpec(defaultAttribute, datatype, spec.Nullable)
+		if err != nil {
+			return spec, err
+		}
+		if !sameSpecValue(fillValue, defaultFillValue) {
+			spec.FillValue = fillValue
+		}
+	}
+
+	return spec, nil
+}
+
+// attributeFillValueSpec returns the fill value of the attribute as written in a spec,
+// nil for the null fill value of nullable attributes.
+func attributeFillValueSpec(attribute *Attribute, datatype Datatype, nullable bool) (any, error) {
+	var value any
+	if nullable {
+		fillValue, _, valid, err := attribute.GetFillValueNullable()
+		if err != nil || !valid {
+			return nil, err
+		}
+		value = fillValue
+	} else {
+		fillValue, _, err := attribute.GetFillValue()
+		if err != nil {
+			return nil, err
+		}
+		value = fillValue
+	}
+
+	if t, ok := value.(time.Time); ok {
+		return GetTimestampFromTime(datatype, t)
+	}
+	return value, nil
+}
+
+// sameSpecValue returns whether a and b are equal, considering NaN floats equal
+// as they are the default fill values of float attributes.
+func sameSpecValue(a, b any) bool {
+	switch a := a.(type) {
+	case float32:
+		if b, ok := b.(float32); ok && a != a && b != b {
+			return true
+		}
+	case float64:
+		if b, ok := b.(float64); ok && a != a && b != b {
+			return true
+		}
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func enumerationSpec(enumeration *Enumeration, name string) (EnumerationSpec, error) {
+	spec := EnumerationSpec{Name: name}
+
+	datatype, err := enumeration.Type()
+	if err != nil {
+		return spec, err
+	}
+	spec.Type = datatype.String()
+	if spec.Ordered, err = enumeration.IsOrdered(); err != nil {
+		return spec, err
+	}
+	values, err := enumeration.Values()
+	if err != nil {
+		return spec, err
+	}
+	valuesValue := reflect.ValueOf(values)
+	spec.Values = make([]any, valuesValue.Len())
+	for i := range spec.Values {
+		spec.Values[i] = valuesValue.Index(i).Interface()
+	}
+
+	return spec, nil
+}
+
+// filterListSpec returns the spec of the filter list returned by a getter.
+func filterListSpec(filterList *FilterList, err error) ([]FilterSpec, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer filterList.Free()
+
+	filters, err := filterList.Filters()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		for _, filter := range filters {
+			filter.Free()
+		}
+	}()
+
+	var specs []FilterSpec
+	for _, filter := range filters {
+		spec, err := filterSpec(filterList.context, filter)
+		if err != nil {
+			return nil, err
+		}
+		specs = append(specs, spec)
+	}
+	return specs, nil
+}
+
+func filterSpec(tdbCtx *Context, filter *Filter) (FilterSpec, error) {
+	filterType, err := filter.Type()
+	if err != nil {
+		return FilterSpec{}, err
+	}
+	spec := FilterSpec{Type: filterType.String()}
+
+	options := filterTypeOptions(filterType)
+	if len(options) == 0 {
+		return spec, nil
+	}
+
+	// Compare with the options of a new filter to only keep non default values
+	defaultFilter, err := NewFilter(tdbCtx, filterType)
+	if err != nil {
+		return spec, err
+	}
+	defer defaultFilter.Free()
+
+	for _, option := range options {
+		value, err := filter.Option(option)
+		if err != nil {
+			return spec, err
+		}
+		defaultValue, err := defaultFilter.Option(option)
+		if err != nil {
+			return spec, err
+		}
+		if value == defaultValue {
+			continue
+		}
+		if datatype, ok := value.(Datatype); ok {
+			value = datatype.String()
+		}
+		if spec.Options == nil {
+			spec.Options = make(map[string]any)
+		}
+		spec.Options[option.String()] = value
+	}
+
+	return spec, nil
+}
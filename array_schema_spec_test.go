@@ -0,0 +1,115 @@
+package tiledb
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestArraySchemaSpec(t *testing.T) {
+	tdbCtx, err := NewContext(nil)
+	require.NoError(t, err)
+
+	data, err := os.ReadFile("testdata/schema.yaml")
+	require.NoError(t, err)
+	spec, err := ParseArraySchemaSpec(data)
+	require.NoError(t, err)
+
+	schema, err := NewArraySchemaFromSpec(tdbCtx, spec)
+	require.NoError(t, err)
+	defer schema.Free()
+
+	capacity, err := schema.Capacity()
+	require.NoError(t, err)
+	assert.EqualValues(t, 1000, capacity)
+	count, err := schema.AttributeFromName("count")
+	require.NoError(t, err)
+	fillValue, _, valid, err := count.GetFillValueNullable()
+	require.NoError(t, err)
+	assert.True(t, valid)
+	assert.Equal(t, int64(0), fillValue)
+
+	exported, err := schema.Spec()
+	require.NoError(t, err)
+	assert.Equal(t, "sparse", exported.Type)
+	require.Len(t, exported.Dimensions, 2)
+	assert.Equal(t, []any{int32(1), int32(1000)}, exported.Dimensions[0].Domain)
+	assert.Equal(t, int32(100), exported.Dimensions[0].TileExtent)
+	assert.Nil(t, exported.Dimensions[1].Domain)
+	require.Len(t, exported.Attributes, 5)
+	assert.Equal(t, AttributeSpec{
+		Name:    "name",
+		Type:    "STRING_UTF8",
+		Var:     true,
+		Filters: []FilterSpec{{Type: TILEDB_FILTER_ZSTD.String(), Options: map[string]any{TILEDB_COMPRESSION_LEVEL.String(): int32(5)}}},
+	}, exported.Attributes[0])
+	assert.Equal(t, uint32(2), exported.Attributes[2].CellValNum)
+	assert.Nil(t, exported.Attributes[2].FillValue)
+	assert.Equal(t, 0.5, exported.Attributes[3].FillValue)
+	assert.Equal(t, []EnumerationSpec{{Name: "colors", Type: "STRING_ASCII", Values: []any{"red", "green", "blue"}}}, exported.Enumerations)
+
+	t.Run("YAML", func(t *testing.T) {
+		data, err := yaml.Marshal(exported)
+		require.NoError(t, err)
+		assertSpecRoundTrip(t, tdbCtx, schema, data)
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		data, err := json.Marshal(exported)
+		require.NoError(t, err)
+		assertSpecRoundTrip(t, tdbCtx, schema, data)
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		_, err := ParseArraySchemaSpec([]byte("type: dense\nunknown: 1\n"))
+		require.Error(t, err)
+
+		for name, spec := range map[string]*ArraySchemaSpec{
+			"ArrayType": {Type: "other"},
+			"Domain": {Type: "dense", Dimensions: []DimensionSpec{
+				{Name: "d", Type: "INT8", Domain: []any{1, 1000}, TileExtent: 10},
+			}},
+			"NegativeUnsigned": {Type: "dense", Dimensions: []DimensionSpec{
+				{Name: "d", Type: "UINT64", Domain: []any{-1, 10}, TileExtent: 10},
+			}},
+			"NegativeUnsigned32": {Type: "dense", Dimensions: []DimensionSpec{
+				{Name: "d", Type: "UINT32", Domain: []any{int64(-1), 10}, TileExtent: 10},
+			}},
+			"UnsignedOverflow": {Type: "dense", Dimensions: []DimensionSpec{
+				{Name: "d", Type: "INT64", Domain: []any{1, uint64(1) << 63}, TileExtent: 10},
+			}},
+			"FillValue": {Type: "dense", Dimensions: []DimensionSpec{
+				{Name: "d", Type: "INT32", Domain: []any{1, 10}, TileExtent: 10},
+			}, Attributes: []AttributeSpec{
+				{Name: "a", Type: "STRING_UTF8", Var: true, FillValue: "x"},
+			}},
+			"FilterOption": {Type: "dense", Dimensions: []DimensionSpec{
+				{Name: "d", Type: "INT32", Domain: []any{1, 10}, TileExtent: 10, Filters: []FilterSpec{
+					{Type: TILEDB_FILTER_ZSTD.String(), Options: map[string]any{TILEDB_COMPRESSION_LEVEL.String(): "high"}},
+				}},
+			}},
+		} {
+			t.Run(name, func(t *testing.T) {
+				_, err := NewArraySchemaFromSpec(tdbCtx, spec)
+				require.Error(t, err)
+			})
+		}
+	})
+}
+
+// assertSpecRoundTrip checks that the schema built from the spec in data is equivalent to schema.
+func assertSpecRoundTrip(t *testing.T, tdbCtx *Context, schema *ArraySchema, data []byte) {
+	spec, err := ParseArraySchemaSpec(data)
+	require.NoError(t, err)
+	parsed, err := NewArraySchemaFromSpec(tdbCtx, spec)
+	require.NoError(t, err)
+	defer parsed.Free()
+
+	changes, err := DiffArraySchemas(schema, parsed)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+}
@@ -160,6 +160,12 @@ func TestArraySchema(t *testing.T) {
 	require.NoError(t, err)
 	assert.True(t, domainDatatype > -1)
 
+	// Get the names of the dimensions and attributes
+	dimNames, attrNames, err := arraySchema.FieldNames()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"dim1"}, dimNames)
+	assert.Equal(t, []string{"a1"}, attrNames)
+
 	// Set Cell Order
 	require.NoError(t, arraySchema.SetCellOrder(TILEDB_GLOBAL_ORDER))
 
@@ -0,0 +1,229 @@
+package arrow
+
+import (
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tiledb "github.com/TileDB-Inc/TileDB-Go"
+)
+
+type arrowTestRow struct {
+	ID     int32      `tiledb:"id"`
+	Count  *int32     `tiledb:"count"`
+	Name   string     `tiledb:"name"`
+	Values []float64  `tiledb:"values"`
+	Point  [2]float32 `tiledb:"point"`
+	Flag   bool       `tiledb:"flag"`
+	Color  uint8      `tiledb:"color"`
+}
+
+func arrowTestRows() []arrowTestRow {
+	count := int32(7)
+	return []arrowTestRow{
+		{ID: 1, Count: &count, Name: "one", Values: []float64{1}, Point: [2]float32{1, 1}, Flag: true, Color: 0},
+		{ID: 2, Name: "", Values: []float64{}, Point: [2]float32{2, 2}, Color: 1},
+		{ID: 3, Count: &count, Name: "three", Values: []float64{3, 3, 3}, Point: [2]float32{3, 3}, Flag: true, Color: 2},
+		{ID: 4, Name: "four", Values: []float64{4, 4}, Point: [2]float32{4, 4}, Color: 1},
+		{ID: 5, Count: &count, Name: "five", Values: []float64{5}, Point: [2]float32{5, 5}, Flag: true, Color: 0},
+	}
+}
+
+func TestExportSchema(t *testing.T) {
+	_, array := createArrowTestArray(t)
+	require.NoError(t, array.Open(tiledb.TILEDB_READ))
+	t.Cleanup(func() { require.NoError(t, array.Close()) })
+
+	exporter, err := NewExporter(array)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"id", "count", "name", "values", "point", "flag", "color"}, exporter.Fields())
+
+	var schema Schema
+	require.NoError(t, exporter.ExportSchema(&schema))
+	defer schema.Release()
+
+	assert.Equal(t, "+s", cString(schema.format))
+	children := schemaChildren(schema.c())
+	formats := make([]string, len(children))
+	for i, child := range children {
+		formats[i] = cString(child.format)
+	}
+	assert.Equal(t, []string{"i", "i", "U", "+L", "+w:2", "b", "C"}, formats)
+
+	assert.Equal(t, "count", cString(children[1].name))
+	assert.NotZero(t, children[1].flags&2, "count should be nullable")
+	assert.Equal(t, "g", cString(schemaChildren(children[3])[0].format))
+	require.NotNil(t, children[6].dictionary)
+	assert.Equal(t, "U", cString(children[6].dictionary.format))
+
+	schema.Release()
+	assert.True(t, schema.IsReleased())
+
+	t.Run("UnknownField", func(t *testing.T) {
+		_, err := NewExporter(array, "other")
+		require.Error(t, err)
+	})
+}
+
+func TestExportWriteBatch(t *testing.T) {
+	tdbCtx, src := createArrowTestArray(t)
+	rows := arrowTestRows()
+	require.NoError(t, src.Open(tiledb.TILEDB_WRITE))
+	require.NoError(t, tiledb.Write(tdbCtx, src, rows, tiledb.TILEDB_UNORDERED))
+	require.NoError(t, src.Close())
+
+	require.NoError(t, src.Open(tiledb.TILEDB_READ))
+	t.Cleanup(func() { require.NoError(t, src.Close()) })
+
+	exporter, err := NewExporter(src)
+	require.NoError(t, err)
+	var schema Schema
+	require.NoError(t, exporter.ExportSchema(&schema))
+	t.Cleanup(schema.Release)
+
+	readQuery, err := tiledb.NewQuery(tdbCtx, src)
+	require.NoError(t, err)
+	defer readQuery.Free()
+	require.NoError(t, readQuery.SetLayout(tiledb.TILEDB_ROW_MAJOR))
+
+	var batches []*Array
+	t.Cleanup(func() {
+		for _, batch := range batches {
+			batch.Release()
+		}
+	})
+	require.NoError(t, exporter.ExportBatches(readQuery, tiledb.BatchOptions{InitialCells: 2}, func(array *Array) error {
+		assert.False(t, array.IsReleased())
+		// Move the array, which is freed after the callback returns.
+		batch := new(Array)
+		*batch = *array
+		array.release = nil
+		batches = append(batches, batch)
+		return nil
+	}))
+	require.Greater(t, len(batches), 1)
+
+	var total int64
+	var nullCount int64
+	for _, batch := range batches {
+		total += int64(batch.length)
+		nullCount += int64(arrayChildren(batch.c())[1].null_count)
+		assert.NotNil(t, arrayChildren(batch.c())[6].dictionary)
+	}
+	assert.Equal(t, int64(len(rows)), total)
+	assert.Equal(t, int64(2), nullCount)
+
+	_, dst := createArrowTestArray(t)
+	writeBatch := func(batch *Array) {
+		require.NoError(t, dst.Open(tiledb.TILEDB_WRITE))
+		defer func() { require.NoError(t, dst.Close()) }()
+
+		query, err := tiledb.NewQuery(tdbCtx, dst)
+		require.NoError(t, err)
+		defer query.Free()
+		require.NoError(t, query.SetLayout(tiledb.TILEDB_UNORDERED))
+		require.NoError(t, WriteBatch(query, &schema, batch))
+		require.NoError(t, query.Finalize())
+	}
+	// Write the first batch in two slices
+	first := batches[0]
+	require.Equal(t, int64(2), int64(first.length))
+	first.length = 1
+	writeBatch(first)
+	first.offset = 1
+	writeBatch(first)
+	first.offset, first.length = 0, 2
+
+	for _, batch := range batches[1:] {
+		writeBatch(batch)
+	}
+
+	require.NoError(t, dst.Open(tiledb.TILEDB_READ))
+	defer func() { require.NoError(t, dst.Close()) }()
+	query, err := tiledb.NewQuery(tdbCtx, dst)
+	require.NoError(t, err)
+	defer query.Free()
+	require.NoError(t, query.SetLayout(tiledb.TILEDB_ROW_MAJOR))
+
+	var got []arrowTestRow
+	require.NoError(t, tiledb.ReadInto(query, &got))
+	assert.Equal(t, rows, got)
+
+	t.Run("Errors", func(t *testing.T) {
+		_, other := createArrowTestArray(t)
+		require.NoError(t, other.Open(tiledb.TILEDB_WRITE))
+		defer func() { require.NoError(t, other.Close()) }()
+
+		query, err := tiledb.NewQuery(tdbCtx, other)
+		require.NoError(t, err)
+		defer query.Free()
+
+		var released Array
+		require.Error(t, WriteBatch(query, &schema, &released))
+
+		// The schema of a single child does not describe the struct array
+		child := (*Schema)(schemaChildren(schema.c())[0])
+		require.Error(t, WriteBatch(query, child, batches[0]))
+	})
+}
+
+// cString returns the NUL-terminated string at p.
+func cString[T any](p *T) string {
+	if p == nil {
+		return ""
+	}
+	start := unsafe.Pointer(p)
+	var n int
+	for *(*byte)(unsafe.Add(start, n)) != 0 {
+		n++
+	}
+	return string(unsafe.Slice((*byte)(start), n))
+}
+
+func createArrowTestArray(t testing.TB) (*tiledb.Context, *tiledb.Array) {
+	tdbCtx, err := tiledb.NewContext(nil)
+	require.NoError(t, err)
+
+	dimension, err := tiledb.NewDimension(tdbCtx, "id", tiledb.TILEDB_INT32, []int32{1, 100}, int32(10))
+	require.NoError(t, err)
+	domain, err := tiledb.NewDomain(tdbCtx)
+	require.NoError(t, err)
+	require.NoError(t, domain.AddDimensions(dimension))
+
+	schema, err := tiledb.NewArraySchema(tdbCtx, tiledb.TILEDB_SPARSE)
+	require.NoError(t, err)
+	require.NoError(t, schema.SetDomain(domain))
+
+	colors, err := tiledb.NewUnorderedEnumeration(tdbCtx, "colors", []string{"red", "green", "blue"})
+	require.NoError(t, err)
+	require.NoError(t, schema.AddEnumeration(colors))
+
+	count, err := tiledb.NewAttribute(tdbCtx, "count", tiledb.TILEDB_INT32)
+	require.NoError(t, err)
+	require.NoError(t, count.SetNullable(true))
+	name, err := tiledb.NewAttribute(tdbCtx, "name", tiledb.TILEDB_STRING_UTF8)
+	require.NoError(t, err)
+	require.NoError(t, name.SetCellValNum(tiledb.TILEDB_VAR_NUM))
+	values, err := tiledb.NewAttribute(tdbCtx, "values", tiledb.TILEDB_FLOAT64)
+	require.NoError(t, err)
+	require.NoError(t, values.SetCellValNum(tiledb.TILEDB_VAR_NUM))
+	point, err := tiledb.NewAttribute(tdbCtx, "point", tiledb.TILEDB_FLOAT32)
+	require.NoError(t, err)
+	require.NoError(t, point.SetCellValNum(2))
+	flag, err := tiledb.NewAttribute(tdbCtx, "flag", tiledb.TILEDB_BOOL)
+	require.NoError(t, err)
+	color, err := tiledb.NewAttribute(tdbCtx, "color", tiledb.TILEDB_UINT8)
+	require.NoError(t, err)
+	require.NoError(t, color.SetEnumerationName("colors"))
+	require.NoError(t, schema.AddAttributes(count, name, values, point, flag, color))
+
+	arrayPath := t.TempDir()
+	require.NoError(t, tiledb.CreateArray(tdbCtx, arrayPath, schema))
+
+	array, err := tiledb.NewArray(tdbCtx, arrayPath)
+	require.NoError(t, err)
+
+	return tdbCtx, array
+}
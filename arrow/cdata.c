@@ -0,0 +1,110 @@
+#include "cdata.h"
+#include <stdlib.h>
+#include <string.h>
+
+static void release_schema(struct ArrowSchema* s) {
+  free((void*)s->format);
+  free((void*)s->name);
+  for (int64_t i = 0; i < s->n_children; i++) {
+    struct ArrowSchema* child = s->children[i];
+    if (child != NULL) {
+      if (child->release != NULL) {
+        child->release(child);
+      }
+      free(child);
+    }
+  }
+  free(s->children);
+  if (s->dictionary != NULL) {
+    if (s->dictionary->release != NULL) {
+      s->dictionary->release(s->dictionary);
+    }
+    free(s->dictionary);
+  }
+  s->release = NULL;
+}
+
+void tiledb_go_arrow_schema_init(
+    struct ArrowSchema* s,
+    const char* format,
+    const char* name,
+    int64_t flags,
+    int64_t n_children) {
+  s->format = strdup(format);
+  s->name = strdup(name);
+  s->metadata = NULL;
+  s->flags = flags;
+  s->n_children = n_children;
+  s->children = calloc(n_children > 0 ? n_children : 1, sizeof(struct ArrowSchema*));
+  s->dictionary = NULL;
+  s->release = release_schema;
+  s->private_data = NULL;
+}
+
+struct ArrowSchema* tiledb_go_arrow_schema_new(
+    const char* format, const char* name, int64_t flags, int64_t n_children) {
+  struct ArrowSchema* s = malloc(sizeof(struct ArrowSchema));
+  tiledb_go_arrow_schema_init(s, format, name, flags, n_children);
+  return s;
+}
+
+static void release_array(struct ArrowArray* a) {
+  for (int64_t i = 0; i < a->n_buffers; i++) {
+    free((void*)a->buffers[i]);
+  }
+  free(a->buffers);
+  for (int64_t i = 0; i < a->n_children; i++) {
+    struct ArrowArray* child = a->children[i];
+    if (child != NULL) {
+      if (child->release != NULL) {
+        child->release(child);
+      }
+      free(child);
+    }
+  }
+  free(a->children);
+  if (a->dictionary != NULL) {
+    if (a->dictionary->release != NULL) {
+      a->dictionary->release(a->dictionary);
+    }
+    free(a->dictionary);
+  }
+  a->release = NULL;
+}
+
+void tiledb_go_arrow_array_init(
+    struct ArrowArray* a,
+    int64_t length,
+    int64_t null_count,
+    int64_t n_buffers,
+    int64_t n_children) {
+  a->length = length;
+  a->null_count = null_count;
+  a->offset = 0;
+  a->n_buffers = n_buffers;
+  a->n_children = n_children;
+  a->buffers = calloc(n_buffers > 0 ? n_buffers : 1, sizeof(void*));
+  a->children = calloc(n_children > 0 ? n_children : 1, sizeof(struct ArrowArray*));
+  a->dictionary = NULL;
+  a->release = release_array;
+  a->private_data = NULL;
+}
+
+struct ArrowArray* tiledb_go_arrow_array_new(
+    int64_t length, int64_t null_count, int64_t n_buffers, int64_t n_children) {
+  struct ArrowArray* a = malloc(sizeof(struct ArrowArray));
+  tiledb_go_arrow_array_init(a, length, null_count, n_buffers, n_children);
+  return a;
+}
+
+void tiledb_go_arrow_schema_release(struct ArrowSchema* s) {
+  if (s->release != NULL) {
+    s->release(s);
+  }
+}
+
+void tiledb_go_arrow_array_release(struct ArrowArray* a) {
+  if (a->release != NULL) {
+    a->release(a);
+  }
+}
@@ -0,0 +1,112 @@
+package arrow
+
+/*
+#include "cdata.h"
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"unsafe"
+)
+
+// Schema is the ArrowSchema struct of the Arrow C data interface.
+type Schema C.struct_ArrowSchema
+
+// Array is the ArrowArray struct of the Arrow C data interface.
+type Array C.struct_ArrowArray
+
+func (s *Schema) c() *C.struct_ArrowSchema {
+	return (*C.struct_ArrowSchema)(s)
+}
+
+// Release calls the release callback of the schema. It does nothing if the schema
+// is already released or was moved to a consumer.
+func (s *Schema) Release() {
+	C.tiledb_go_arrow_schema_release(s.c())
+}
+
+// IsReleased returns whether the schema is released.
+func (s *Schema) IsReleased() bool {
+	return s.release == nil
+}
+
+func (a *Array) c() *C.struct_ArrowArray {
+	return (*C.struct_ArrowArray)(a)
+}
+
+// Release calls the release callback of the array. It does nothing if the array
+// is already released or was moved to a consumer.
+func (a *Array) Release() {
+	C.tiledb_go_arrow_array_release(a.c())
+}
+
+// IsReleased returns whether the array is released.
+func (a *Array) IsReleased() bool {
+	return a.release == nil
+}
+
+// newSchema allocates a child or dictionary schema.
+func newSchema(format, name string, flags int64, nChildren int) *C.struct_ArrowSchema {
+	cFormat := C.CString(format)
+	defer C.free(unsafe.Pointer(cFormat))
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+
+	return C.tiledb_go_arrow_schema_new(cFormat, cName, C.int64_t(flags), C.int64_t(nChildren))
+}
+
+// initSchema initializes the top-level schema s.
+func initSchema(s *C.struct_ArrowSchema, format, name string, flags int64, nChildren int) {
+	cFormat := C.CString(format)
+	defer C.free(unsafe.Pointer(cFormat))
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+
+	C.tiledb_go_arrow_schema_init(s, cFormat, cName, C.int64_t(flags), C.int64_t(nChildren))
+}
+
+// freeSchema releases and frees a schema allocated with newSchema.
+func freeSchema(s *C.struct_ArrowSchema) {
+	C.tiledb_go_arrow_schema_release(s)
+	C.free(unsafe.Pointer(s))
+}
+
+func schemaChildren(s *C.struct_ArrowSchema) []*C.struct_ArrowSchema {
+	return unsafe.Slice(s.children, int(s.n_children))
+}
+
+// newArray allocates a child or dictionary array.
+func newArray(length, nullCount int64, nBuffers, nChildren int) *C.struct_ArrowArray {
+	return C.tiledb_go_arrow_array_new(C.int64_t(length), C.int64_t(nullCount), C.int64_t(nBuffers), C.int64_t(nChildren))
+}
+
+// freeArray releases and frees an array allocated with newArray.
+func freeArray(a *C.struct_ArrowArray) {
+	C.tiledb_go_arrow_array_release(a)
+	C.free(unsafe.Pointer(a))
+}
+
+func arrayBuffers(a *C.struct_ArrowArray) []unsafe.Pointer {
+	return unsafe.Slice((*unsafe.Pointer)(unsafe.Pointer(a.buffers)), int(a.n_buffers))
+}
+
+func arrayChildren(a *C.struct_ArrowArray) []*C.struct_ArrowArray {
+	return unsafe.Slice(a.children, int(a.n_children))
+}
+
+// cBytes copies b to memory allocated with malloc, which is freed by the release callback
+// of the array the buffer is set to.
+func cBytes(b []byte) unsafe.Pointer {
+	ptr := C.malloc(C.size_t(max(len(b), 1)))
+	copy(unsafe.Slice((*byte)(ptr), len(b)), b)
+	return ptr
+}
+
+// cBytesView returns a slice over size bytes of C memory starting at ptr.
+func cBytesView(ptr unsafe.Pointer, size int) []byte {
+	if size == 0 {
+		return nil
+	}
+	return unsafe.Slice((*byte)(ptr), size)
+}
@@ -0,0 +1,84 @@
+#ifndef TILEDB_GO_ARROW_CDATA_H
+#define TILEDB_GO_ARROW_CDATA_H
+
+#include <stdint.h>
+
+// The structs of the Arrow C data interface,
+// see https://arrow.apache.org/docs/format/CDataInterface.html
+
+#ifndef ARROW_C_DATA_INTERFACE
+#define ARROW_C_DATA_INTERFACE
+
+#define ARROW_FLAG_DICTIONARY_ORDERED 1
+#define ARROW_FLAG_NULLABLE 2
+#define ARROW_FLAG_MAP_KEYS_SORTED 4
+
+struct ArrowSchema {
+  // Array type description
+  const char* format;
+  const char* name;
+  const char* metadata;
+  int64_t flags;
+  int64_t n_children;
+  struct ArrowSchema** children;
+  struct ArrowSchema* dictionary;
+
+  // Release callback
+  void (*release)(struct ArrowSchema*);
+  // Opaque producer-specific data
+  void* private_data;
+};
+
+struct ArrowArray {
+  // Array data description
+  int64_t length;
+  int64_t null_count;
+  int64_t offset;
+  int64_t n_buffers;
+  int64_t n_children;
+  const void** buffers;
+  struct ArrowArray** children;
+  struct ArrowArray* dictionary;
+
+  // Release callback
+  void (*release)(struct ArrowArray*);
+  // Opaque producer-specific data
+  void* private_data;
+};
+
+#endif  // ARROW_C_DATA_INTERFACE
+
+// Initialize the schema s, copying format and name. Its children are allocated but not set.
+// The release callback frees everything the schema points to, children and dictionary included.
+void tiledb_go_arrow_schema_init(
+    struct ArrowSchema* s,
+    const char* format,
+    const char* name,
+    int64_t flags,
+    int64_t n_children);
+
+// Allocate and initialize a schema to be used as a child or dictionary of another schema.
+struct ArrowSchema* tiledb_go_arrow_schema_new(
+    const char* format, const char* name, int64_t flags, int64_t n_children);
+
+// Initialize the array a. Its buffers and children are allocated but not set.
+// The buffers must be allocated with malloc: the release callback frees them,
+// with the children and dictionary.
+void tiledb_go_arrow_array_init(
+    struct ArrowArray* a,
+    int64_t length,
+    int64_t null_count,
+    int64_t n_buffers,
+    int64_t n_children);
+
+// Allocate and initialize an array to be used as a child or dictionary of another array.
+struct ArrowArray* tiledb_go_arrow_array_new(
+    int64_t length, int64_t null_count, int64_t n_buffers, int64_t n_children);
+
+// Call the release callback of s if it is not released.
+void tiledb_go_arrow_schema_release(struct ArrowSchema* s);
+
+// Call the release callback of a if it is not released.
+void tiledb_go_arrow_array_release(struct ArrowArray* a);
+
+#endif  // TILEDB_GO_ARROW_CDATA_H
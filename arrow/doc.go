@@ -0,0 +1,31 @@
+/*
+Package arrow exports the results of TileDB read queries to the Apache Arrow C data interface
+and writes Arrow data through TileDB write queries, without depending on an Arrow library.
+
+The Arrow schema of an array comes from its ArraySchema. Each dimension or attribute is a child
+of a struct, with the following types:
+  - numeric types as the Arrow type of the same width, datetimes in seconds, milliseconds,
+    microseconds and nanoseconds as timestamps and other datetimes and times as int64
+  - TILEDB_STRING_ASCII and TILEDB_STRING_UTF8 as large_utf8 when var-sized, fixed_size_binary otherwise
+  - TILEDB_CHAR, TILEDB_BLOB and the geometry types as large_binary when var-sized, fixed_size_binary otherwise
+  - other var-sized types as large_list and fixed-sized types with cell_val_num > 1 as fixed_size_list
+  - attributes with an enumeration as dictionary arrays with the enumeration values as dictionary
+
+Nullable attributes are nullable fields with a validity bitmap.
+
+ExportSchema and ExportBatch initialize a top-level Schema or Array provided by the caller,
+and ExportBatches passes fn an Array allocated in C memory, which is freed after fn returns.
+Everything these structs point to (children, dictionaries, buffers and names) is allocated in
+C memory and freed by their release callbacks, so they can be handed to any Arrow consumer such
+as DuckDB or Polars: pass unsafe.Pointer(schema) and unsafe.Pointer(array) where a struct
+ArrowSchema* or struct ArrowArray* is expected. A consumer that keeps the Array of ExportBatches
+after fn returns must move it to its own struct.
+
+A top-level struct in Go memory follows the cgo pointer passing rules: C code must not keep
+a pointer to it after the call it was passed to returns. A consumer that keeps the pointer must
+get a copy of the struct in memory allocated with C.malloc, after which the release callback of
+the Go struct is set to nil, or the Go struct must be pinned with a runtime.Pinner until it is
+released. Copying a struct moves it without copying the data it points to.
+See https://arrow.apache.org/docs/format/CDataInterface.html.
+*/
+package arrow
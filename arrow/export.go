@@ -0,0 +1,217 @@
+package arrow
+
+/*
+#include "cdata.h"
+*/
+import "C"
+
+import (
+	"encoding/binary"
+	"fmt"
+	"reflect"
+	"unsafe"
+
+	tiledb "github.com/TileDB-Inc/TileDB-Go"
+)
+
+// ExportBatch initializes out with a struct array holding the exported fields of batch.
+// The batch must have been read with all the fields of the exporter, see Exporter.BatchOptions.
+// The data is copied to C memory, so out stays valid after the next batch is read.
+// The caller owns out and must release it.
+func (e *Exporter) ExportBatch(batch *tiledb.QueryBatch, out *Array) error {
+	children := make([]*C.struct_ArrowArray, len(e.fields))
+	if err := e.exportChildren(batch, children); err != nil {
+		for _, c := range children {
+			if c != nil {
+				freeArray(c)
+			}
+		}
+		return err
+	}
+
+	C.tiledb_go_arrow_array_init(out.c(), C.int64_t(batch.NumCells), 0, 1, C.int64_t(len(children)))
+	copy(arrayChildren(out.c()), children)
+
+	return nil
+}
+
+// exportChildren sets children to the arrays of the fields of batch. On error, the arrays
+// exported before the failing field are left in children for the caller to free.
+func (e *Exporter) exportChildren(batch *tiledb.QueryBatch, children []*C.struct_ArrowArray) error {
+	for i, f := range e.fields {
+		child, err := exportFieldArray(f, batch)
+		if err != nil {
+			return fmt.Errorf("could not export %s to Arrow: %w", f.name, err)
+		}
+		children[i] = child
+	}
+	return nil
+}
+
+// BatchOptions returns opts with the fields of the exporter, to read batches with Query.Batches
+// that can be exported with ExportBatch.
+func (e *Exporter) BatchOptions(opts tiledb.BatchOptions) tiledb.BatchOptions {
+	opts.Fields = e.Fields()
+	return opts
+}
+
+// ExportBatches reads the query with Query.Batches and calls fn with each batch exported
+// to an Arrow struct array allocated in C memory. The array is released and freed after fn
+// returns, so a consumer that keeps it must move it: copy the struct and set the release
+// callback of the array to nil. Reading stops at the first error, which is returned.
+func (e *Exporter) ExportBatches(query *tiledb.Query, opts tiledb.BatchOptions, fn func(*Array) error) error {
+	var err error
+	query.Batches(e.BatchOptions(opts))(func(batch *tiledb.QueryBatch, batchErr error) bool {
+		if batchErr != nil {
+			err = batchErr
+			return false
+		}
+
+		array := newArray(int64(batch.NumCells), 0, 1, len(e.fields))
+		if err = e.exportChildren(batch, arrayChildren(array)); err == nil {
+			err = fn((*Array)(array))
+		}
+		// This does not release the array again if fn released it or moved it.
+		freeArray(array)
+		return err == nil
+	})
+
+	return err
+}
+
+// exportFieldArray allocates the array of field f of batch.
+func exportFieldArray(f field, batch *tiledb.QueryBatch) (*C.struct_ArrowArray, error) {
+	numCells := int64(batch.NumCells)
+
+	data, err := batch.Data(f.name)
+	if err != nil {
+		return nil, err
+	}
+
+	var validity unsafe.Pointer
+	var nullCount int64
+	if f.nullable {
+		cells, err := batch.Validity(f.name)
+		if err != nil {
+			return nil, err
+		}
+		var bitmap []byte
+		bitmap, nullCount = packValidity(cells)
+		validity = cBytes(bitmap)
+	}
+
+	var a *C.struct_ArrowArray
+	switch f.layout() {
+	case layoutPrimitive:
+		a = newArray(numCells, nullCount, 2, 0)
+		arrayBuffers(a)[1] = cBytes(valueBytes(data))
+	case layoutFixedBinary:
+		a = newArray(numCells, nullCount, 2, 0)
+		arrayBuffers(a)[1] = cBytes(valueBytes(data))
+	case layoutBinary:
+		offsets, err := batch.Offsets(f.name)
+		if err != nil {
+			return nil, err
+		}
+		raw := valueBytes(data)
+		a = newArray(numCells, nullCount, 3, 0)
+		arrayBuffers(a)[1] = cBytes(arrowOffsets(offsets, 1, uint64(len(raw))))
+		arrayBuffers(a)[2] = cBytes(raw)
+	case layoutList:
+		offsets, err := batch.Offsets(f.name)
+		if err != nil {
+			return nil, err
+		}
+		numValues := reflect.ValueOf(data).Len()
+		a = newArray(numCells, nullCount, 2, 1)
+		arrayBuffers(a)[1] = cBytes(arrowOffsets(offsets, f.datatype.Size(), uint64(numValues)))
+		arrayChildren(a)[0] = exportValues(data)
+	case layoutFixedList:
+		a = newArray(numCells, nullCount, 1, 1)
+		arrayChildren(a)[0] = exportValues(data)
+	}
+	arrayBuffers(a)[0] = validity
+
+	if f.enumeration != nil {
+		a.dictionary = exportEnumeration(f.enumeration)
+	}
+
+	return a, nil
+}
+
+// exportValues allocates a primitive array holding the values of the slice data.
+func exportValues(data any) *C.struct_ArrowArray {
+	values := newArray(int64(reflect.ValueOf(data).Len()), 0, 2, 0)
+	arrayBuffers(values)[1] = cBytes(valueBytes(data))
+	return values
+}
+
+// exportEnumeration allocates the dictionary array of the values of enumeration e.
+func exportEnumeration(e *enumeration) *C.struct_ArrowArray {
+	strs, ok := e.values.([]string)
+	if !ok {
+		return exportValues(e.values)
+	}
+
+	var data []byte
+	offsets := make([]byte, 0, 8*(len(strs)+1))
+	for _, s := range strs {
+		offsets = binary.NativeEndian.AppendUint64(offsets, uint64(len(data)))
+		data = append(data, s...)
+	}
+	offsets = binary.NativeEndian.AppendUint64(offsets, uint64(len(data)))
+
+	dictionary := newArray(int64(len(strs)), 0, 3, 0)
+	arrayBuffers(dictionary)[1] = cBytes(offsets)
+	arrayBuffers(dictionary)[2] = cBytes(data)
+	return dictionary
+}
+
+// valueBytes returns the memory of the slice data. Booleans are bit-packed.
+func valueBytes(data any) []byte {
+	if bools, ok := data.([]bool); ok {
+		return packBools(bools)
+	}
+
+	v := reflect.ValueOf(data)
+	size := v.Len() * int(v.Type().Elem().Size())
+	if size == 0 {
+		return nil
+	}
+	return unsafe.Slice((*byte)(v.UnsafePointer()), size)
+}
+
+// arrowOffsets converts TileDB offsets in bytes to Arrow 64-bit offsets in values of elemSize bytes,
+// with the extra end offset numValues.
+func arrowOffsets(offsets []uint64, elemSize, numValues uint64) []byte {
+	b := make([]byte, 0, 8*(len(offsets)+1))
+	for _, offset := range offsets {
+		b = binary.NativeEndian.AppendUint64(b, offset/elemSize)
+	}
+	return binary.NativeEndian.AppendUint64(b, numValues)
+}
+
+// packValidity converts TileDB validity bytes to an Arrow validity bitmap and returns the number of nulls.
+func packValidity(validity []uint8) ([]byte, int64) {
+	bitmap := make([]byte, (len(validity)+7)/8)
+	var nullCount int64
+	for i, valid := range validity {
+		if valid != 0 {
+			bitmap[i/8] |= 1 << (i % 8)
+		} else {
+			nullCount++
+		}
+	}
+	return bitmap, nullCount
+}
+
+// packBools converts booleans to an Arrow bitmap.
+func packBools(bools []bool) []byte {
+	bitmap := make([]byte, (len(bools)+7)/8)
+	for i, b := range bools {
+		if b {
+			bitmap[i/8] |= 1 << (i % 8)
+		}
+	}
+	return bitmap
+}
@@ -0,0 +1,235 @@
+package arrow
+
+/*
+#include "cdata.h"
+*/
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"unsafe"
+
+	tiledb "github.com/TileDB-Inc/TileDB-Go"
+)
+
+// WriteBatch sets the buffers of the write query from the struct array described by schema
+// and submits the query. Each child of the struct is written to the dimension or attribute
+// with the same name, and must have the Arrow type ExportSchema gives it. Utf8, binary and list
+// children with 32-bit offsets are accepted as well, and timestamps can have a time zone.
+// Dictionary arrays are written as their indices.
+//
+// The data is copied, schema and array still belong to the caller who must release them.
+// The query is not finalized, so several batches can be written with global order layout.
+func WriteBatch(query *tiledb.Query, schema *Schema, array *Array) error {
+	s, a := schema.c(), array.c()
+	if s.release == nil || a.release == nil {
+		return errors.New("cannot write released Arrow schema or array")
+	}
+	if format := C.GoString(s.format); format != "+s" {
+		return fmt.Errorf("cannot write Arrow array of format %q, a struct is expected", format)
+	}
+	if s.n_children != a.n_children {
+		return fmt.Errorf("Arrow schema has %d children and array has %d", s.n_children, a.n_children)
+	}
+	if a.null_count != 0 {
+		return errors.New("cannot write Arrow struct array with null elements")
+	}
+	if a.length == 0 {
+		return errors.New("cannot write empty Arrow array")
+	}
+
+	tdbArray, err := query.Array()
+	if err != nil {
+		return err
+	}
+
+	childSchemas := schemaChildren(s)
+	names := make([]string, len(childSchemas))
+	for i, child := range childSchemas {
+		names[i] = C.GoString(child.name)
+	}
+	fields, err := arrayFields(tdbArray, names, false)
+	if err != nil {
+		return fmt.Errorf("could not map Arrow schema to array: %w", err)
+	}
+
+	childArrays := arrayChildren(a)
+	for i, f := range fields {
+		if err := importField(query, f, childSchemas[i], childArrays[i], int64(a.offset), int64(a.length)); err != nil {
+			return fmt.Errorf("could not write %s from Arrow: %w", f.name, err)
+		}
+	}
+
+	return query.Submit()
+}
+
+// importField sets the buffers of f on the query from numCells elements of a starting at start,
+// in addition to the offset of a.
+func importField(query *tiledb.Query, f field, s *C.struct_ArrowSchema, a *C.struct_ArrowArray, start, numCells int64) error {
+	format := C.GoString(s.format)
+	large, err := checkImportFormat(f, format)
+	if err != nil {
+		return err
+	}
+	if int64(a.length) < start+numCells {
+		return fmt.Errorf("Arrow array has %d elements, %d are needed", a.length, start+numCells)
+	}
+
+	start += int64(a.offset)
+	buffers := arrayBuffers(a)
+
+	if f.nullable {
+		validity := make([]uint8, numCells)
+		for i := range validity {
+			validity[i] = 1
+		}
+		if a.null_count != 0 && buffers[0] != nil {
+			validity = unpackBits(buffers[0], start, numCells, validity)
+		}
+		if _, err := query.SetValidityBuffer(f.name, validity); err != nil {
+			return err
+		}
+	} else if a.null_count != 0 && buffers[0] != nil {
+		return errors.New("cannot write null values to a non-nullable field")
+	}
+
+	switch f.layout() {
+	case layoutPrimitive:
+		data, err := importValues(f.datatype, buffers[1], start, numCells)
+		if err != nil {
+			return err
+		}
+		_, err = query.SetDataBuffer(f.name, data)
+		return err
+	case layoutFixedBinary:
+		cellSize := int64(f.cellValNum)
+		data := cBytesView(unsafe.Add(buffers[1], start*cellSize), int(numCells*cellSize))
+		return setDataBytes(query, f, data)
+	case layoutFixedList:
+		values := arrayChildren(a)[0]
+		cellValNum := int64(f.cellValNum)
+		data, err := importValues(f.datatype, arrayBuffers(values)[1], int64(values.offset)+start*cellValNum, numCells*cellValNum)
+		if err != nil {
+			return err
+		}
+		_, err = query.SetDataBuffer(f.name, data)
+		return err
+	}
+
+	// Var-sized cells
+	arrowOffsets := importOffsets(buffers[1], large, start, numCells)
+	offsets := make([]uint64, numCells)
+	elemSize := uint64(1)
+	if f.layout() == layoutList {
+		elemSize = f.datatype.Size()
+	}
+	for i := range offsets {
+		offsets[i] = uint64(arrowOffsets[i]-arrowOffsets[0]) * elemSize
+	}
+	if _, err := query.SetOffsetsBuffer(f.name, offsets); err != nil {
+		return err
+	}
+
+	first, last := arrowOffsets[0], arrowOffsets[numCells]
+	if f.layout() == layoutBinary {
+		return setDataBytes(query, f, cBytesView(unsafe.Add(buffers[2], first), int(last-first)))
+	}
+
+	values := arrayChildren(a)[0]
+	data, err := importValues(f.datatype, arrayBuffers(values)[1], int64(values.offset)+first, last-first)
+	if err != nil {
+		return err
+	}
+	return setData(query, f, data)
+}
+
+// checkImportFormat checks that the Arrow format of a child matches field f.
+// It returns whether var-sized cells have 64-bit offsets.
+func checkImportFormat(f field, format string) (bool, error) {
+	expected, err := f.format()
+	if err != nil {
+		return false, err
+	}
+
+	switch {
+	case format == expected:
+		return true, nil
+	case f.layout() == layoutBinary && format == strings.ToLower(expected):
+		return false, nil
+	case f.layout() == layoutList && format == "+l":
+		return false, nil
+	case strings.HasPrefix(expected, "ts") && strings.HasPrefix(format, expected):
+		// Timestamps with a time zone
+		return true, nil
+	default:
+		return false, fmt.Errorf("Arrow format %q does not match %q of %s %s", format, expected, f.datatype, f.name)
+	}
+}
+
+// importOffsets returns the numCells+1 offsets of var-sized cells from start, as 64-bit integers.
+func importOffsets(buffer unsafe.Pointer, large bool, start, numCells int64) []int64 {
+	if large {
+		return unsafe.Slice((*int64)(buffer), start+numCells+1)[start:]
+	}
+
+	offsets32 := unsafe.Slice((*int32)(buffer), start+numCells+1)[start:]
+	offsets := make([]int64, len(offsets32))
+	for i, offset := range offsets32 {
+		offsets[i] = int64(offset)
+	}
+	return offsets
+}
+
+// importValues copies count values of datatype starting at index start of buffer to a Go slice.
+func importValues(datatype tiledb.Datatype, buffer unsafe.Pointer, start, count int64) (any, error) {
+	if datatype == tiledb.TILEDB_BOOL {
+		bits := unpackBits(buffer, start, count, make([]uint8, count))
+		bools := make([]bool, count)
+		for i, bit := range bits {
+			bools[i] = bit != 0
+		}
+		return bools, nil
+	}
+
+	data, ptr, err := datatype.MakeSlice(uint64(count))
+	if err != nil {
+		return nil, err
+	}
+	size := int(count) * int(datatype.Size())
+	copy(cBytesView(ptr, size), cBytesView(unsafe.Add(buffer, start*int64(datatype.Size())), size))
+	return data, nil
+}
+
+// setDataBytes sets the data buffer of the byte-sized field f from a copy of data.
+func setDataBytes(query *tiledb.Query, f field, data []byte) error {
+	return setData(query, f, append([]byte(nil), data...))
+}
+
+// setData sets the data buffer of f. The var-sized cells of a write can all be empty,
+// in which case the core still needs a non-nil buffer.
+func setData(query *tiledb.Query, f field, data any) error {
+	if reflect.ValueOf(data).Len() == 0 {
+		_, ptr, err := f.datatype.MakeSlice(1)
+		if err != nil {
+			return err
+		}
+		_, err = query.SetDataBufferUnsafe(f.name, ptr, 0)
+		return err
+	}
+
+	_, err := query.SetDataBuffer(f.name, data)
+	return err
+}
+
+// unpackBits sets validity[i] to bit start+i of the bitmap and returns it.
+func unpackBits(bitmap unsafe.Pointer, start, count int64, validity []uint8) []uint8 {
+	bits := cBytesView(bitmap, int((start+count+7)/8))
+	for i := int64(0); i < count; i++ {
+		bit := start + i
+		validity[i] = (bits[bit/8] >> (bit % 8)) & 1
+	}
+	return validity
+}
@@ -0,0 +1,349 @@
+package arrow
+
+/*
+#include "cdata.h"
+*/
+import "C"
+
+import (
+	"fmt"
+
+	tiledb "github.com/TileDB-Inc/TileDB-Go"
+)
+
+// layout is how the cells of a field are laid out in Arrow.
+type layout uint8
+
+const (
+	layoutPrimitive   layout = iota // single-valued cells
+	layoutBinary                    // var-sized string or binary cells: large_utf8 or large_binary
+	layoutFixedBinary               // fixed-sized string or binary cells: fixed_size_binary
+	layoutList                      // var-sized cells of other types: large_list
+	layoutFixedList                 // fixed-sized cells of other types: fixed_size_list
+)
+
+// field is a dimension or attribute of a TileDB array.
+type field struct {
+	name        string
+	datatype    tiledb.Datatype
+	cellValNum  uint32
+	nullable    bool
+	enumeration *enumeration // nil if the attribute has no enumeration
+}
+
+// enumeration holds the values of the enumeration of an attribute.
+type enumeration struct {
+	datatype tiledb.Datatype
+	ordered  bool
+	values   any // a slice of the enumeration type, []string for string enumerations
+}
+
+// arrayFields returns the fields names of the open array, or all its dimensions and attributes
+// if names is empty. The enumeration values are only loaded if withEnumerations is true.
+func arrayFields(array *tiledb.Array, names []string, withEnumerations bool) ([]field, error) {
+	schema, err := array.Schema()
+	if err != nil {
+		return nil, err
+	}
+	defer schema.Free()
+
+	domain, err := schema.Domain()
+	if err != nil {
+		return nil, err
+	}
+	defer domain.Free()
+
+	if len(names) == 0 {
+		dimensions, attributes, err := schema.FieldNames()
+		if err != nil {
+			return nil, err
+		}
+		names = append(dimensions, attributes...)
+	}
+
+	fields := make([]field, len(names))
+	for i, name := range names {
+		fields[i], err = arrayField(array, schema, domain, name, withEnumerations)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := fields[i].format(); err != nil {
+			return nil, err
+		}
+	}
+
+	return fields, nil
+}
+
+func arrayField(array *tiledb.Array, schema *tiledb.ArraySchema, domain *tiledb.Domain, name string, withEnumerations bool) (field, error) {
+	f := field{name: name}
+
+	hasDim, err := domain.HasDimension(name)
+	if err != nil {
+		return f, err
+	}
+	if hasDim {
+		dimension, err := domain.DimensionFromName(name)
+		if err != nil {
+			return f, err
+		}
+		defer dimension.Free()
+
+		if f.datatype, err = dimension.Type(); err != nil {
+			return f, err
+		}
+		if f.cellValNum, err = dimension.CellValNum(); err != nil {
+			return f, err
+		}
+		return f, nil
+	}
+
+	hasAttr, err := schema.HasAttribute(name)
+	if err != nil {
+		return f, err
+	}
+	if !hasAttr {
+		return f, fmt.Errorf("no attribute or dimension named %s", name)
+	}
+
+	attribute, err := schema.AttributeFromName(name)
+	if err != nil {
+		return f, err
+	}
+	defer attribute.Free()
+
+	if f.datatype, err = attribute.Type(); err != nil {
+		return f, err
+	}
+	if f.cellValNum, err = attribute.CellValNum(); err != nil {
+		return f, err
+	}
+	if f.nullable, err = attribute.Nullable(); err != nil {
+		return f, err
+	}
+
+	enumerationName, err := attribute.GetEnumerationName()
+	if err != nil {
+		return f, err
+	}
+	if enumerationName == "" || !withEnumerations {
+		return f, nil
+	}
+
+	e, err := array.GetEnumeration(enumerationName)
+	if err != nil {
+		return f, err
+	}
+	defer e.Free()
+
+	f.enumeration = &enumeration{}
+	if f.enumeration.datatype, err = e.Type(); err != nil {
+		return f, err
+	}
+	if f.enumeration.ordered, err = e.IsOrdered(); err != nil {
+		return f, err
+	}
+	if f.enumeration.values, err = e.Values(); err != nil {
+		return f, err
+	}
+
+	return f, nil
+}
+
+// isVar returns whether the cells of f are var-sized.
+func (f field) isVar() bool {
+	return f.cellValNum == tiledb.TILEDB_VAR_NUM
+}
+
+// isBinaryType returns whether datatype holds bytes which are represented as binary
+// or, for strings, utf8 in Arrow instead of lists of uint8.
+func isBinaryType(datatype tiledb.Datatype) bool {
+	switch datatype {
+	case tiledb.TILEDB_STRING_ASCII, tiledb.TILEDB_STRING_UTF8, tiledb.TILEDB_CHAR, tiledb.TILEDB_BLOB, tiledb.TILEDB_GEOM_WKB, tiledb.TILEDB_GEOM_WKT:
+		return true
+	default:
+		return false
+	}
+}
+
+func (f field) layout() layout {
+	switch {
+	case isBinaryType(f.datatype) && f.isVar():
+		return layoutBinary
+	case isBinaryType(f.datatype):
+		return layoutFixedBinary
+	case f.isVar():
+		return layoutList
+	case f.cellValNum > 1:
+		return layoutFixedList
+	default:
+		return layoutPrimitive
+	}
+}
+
+// format returns the Arrow format string of f. For fields with an enumeration
+// it is the format of the indices.
+func (f field) format() (string, error) {
+	switch f.layout() {
+	case layoutBinary:
+		if f.datatype == tiledb.TILEDB_STRING_ASCII || f.datatype == tiledb.TILEDB_STRING_UTF8 {
+			return "U", nil
+		}
+		return "Z", nil
+	case layoutFixedBinary:
+		return fmt.Sprintf("w:%d", f.cellValNum), nil
+	case layoutList:
+		if _, err := primitiveFormat(f.datatype); err != nil {
+			return "", fmt.Errorf("cannot represent %s: %w", f.name, err)
+		}
+		return "+L", nil
+	case layoutFixedList:
+		if _, err := primitiveFormat(f.datatype); err != nil {
+			return "", fmt.Errorf("cannot represent %s: %w", f.name, err)
+		}
+		return fmt.Sprintf("+w:%d", f.cellValNum), nil
+	default:
+		format, err := primitiveFormat(f.datatype)
+		if err != nil {
+			return "", fmt.Errorf("cannot represent %s: %w", f.name, err)
+		}
+		return format, nil
+	}
+}
+
+// primitiveFormat returns the Arrow format string of single values of datatype.
+func primitiveFormat(datatype tiledb.Datatype) (string, error) {
+	switch datatype {
+	case tiledb.TILEDB_INT8:
+		return "c", nil
+	case tiledb.TILEDB_UINT8:
+		return "C", nil
+	case tiledb.TILEDB_INT16:
+		return "s", nil
+	case tiledb.TILEDB_UINT16:
+		return "S", nil
+	case tiledb.TILEDB_INT32:
+		return "i", nil
+	case tiledb.TILEDB_UINT32:
+		return "I", nil
+	case tiledb.TILEDB_INT64:
+		return "l", nil
+	case tiledb.TILEDB_UINT64:
+		return "L", nil
+	case tiledb.TILEDB_FLOAT32:
+		return "f", nil
+	case tiledb.TILEDB_FLOAT64:
+		return "g", nil
+	case tiledb.TILEDB_BOOL:
+		return "b", nil
+	case tiledb.TILEDB_DATETIME_SEC:
+		return "tss:", nil
+	case tiledb.TILEDB_DATETIME_MS:
+		return "tsm:", nil
+	case tiledb.TILEDB_DATETIME_US:
+		return "tsu:", nil
+	case tiledb.TILEDB_DATETIME_NS:
+		return "tsn:", nil
+	case tiledb.TILEDB_TIME_US:
+		return "ttu", nil
+	case tiledb.TILEDB_TIME_NS:
+		return "ttn", nil
+	case tiledb.TILEDB_DATETIME_YEAR, tiledb.TILEDB_DATETIME_MONTH, tiledb.TILEDB_DATETIME_WEEK, tiledb.TILEDB_DATETIME_DAY, tiledb.TILEDB_DATETIME_HR, tiledb.TILEDB_DATETIME_MIN, tiledb.TILEDB_DATETIME_PS, tiledb.TILEDB_DATETIME_FS, tiledb.TILEDB_DATETIME_AS, tiledb.TILEDB_TIME_HR, tiledb.TILEDB_TIME_MIN, tiledb.TILEDB_TIME_SEC, tiledb.TILEDB_TIME_MS, tiledb.TILEDB_TIME_PS, tiledb.TILEDB_TIME_FS, tiledb.TILEDB_TIME_AS:
+		// Arrow has no type with these units
+		return "l", nil
+	default:
+		return "", fmt.Errorf("no Arrow type for %s", datatype)
+	}
+}
+
+// dictionaryFormat returns the Arrow format string of the values of enumeration e.
+func (e *enumeration) format() (string, error) {
+	if _, ok := e.values.([]string); ok {
+		return "U", nil
+	}
+	return primitiveFormat(e.datatype)
+}
+
+// exportFieldSchema allocates the schema of f.
+func exportFieldSchema(f field) (*C.struct_ArrowSchema, error) {
+	format, err := f.format()
+	if err != nil {
+		return nil, err
+	}
+
+	var flags int64
+	if f.nullable {
+		flags |= C.ARROW_FLAG_NULLABLE
+	}
+
+	var dictionary *C.struct_ArrowSchema
+	if f.enumeration != nil {
+		dictionaryFormat, err := f.enumeration.format()
+		if err != nil {
+			return nil, fmt.Errorf("cannot represent enumeration of %s: %w", f.name, err)
+		}
+		dictionary = newSchema(dictionaryFormat, "", 0, 0)
+		if f.enumeration.ordered {
+			flags |= C.ARROW_FLAG_DICTIONARY_ORDERED
+		}
+	}
+
+	switch f.layout() {
+	case layoutList, layoutFixedList:
+		s := newSchema(format, f.name, flags, 1)
+		itemFormat, _ := primitiveFormat(f.datatype)
+		schemaChildren(s)[0] = newSchema(itemFormat, "item", 0, 0)
+		s.dictionary = dictionary
+		return s, nil
+	default:
+		s := newSchema(format, f.name, flags, 0)
+		s.dictionary = dictionary
+		return s, nil
+	}
+}
+
+// Exporter exports dimensions and attributes of an array to the Arrow C data interface.
+type Exporter struct {
+	fields []field
+}
+
+// NewExporter creates an Exporter for the dimensions and attributes names of the open array,
+// or all of them if names is empty. It loads the values of the enumerations of the attributes.
+func NewExporter(array *tiledb.Array, names ...string) (*Exporter, error) {
+	fields, err := arrayFields(array, names, true)
+	if err != nil {
+		return nil, fmt.Errorf("could not create Arrow exporter: %w", err)
+	}
+
+	return &Exporter{fields: fields}, nil
+}
+
+// Fields returns the names of the exported dimensions and attributes.
+func (e *Exporter) Fields() []string {
+	names := make([]string, len(e.fields))
+	for i, f := range e.fields {
+		names[i] = f.name
+	}
+	return names
+}
+
+// ExportSchema initializes out with a struct schema with a child for each exported field.
+// The caller owns out and must release it.
+func (e *Exporter) ExportSchema(out *Schema) error {
+	children := make([]*C.struct_ArrowSchema, 0, len(e.fields))
+	for _, f := range e.fields {
+		child, err := exportFieldSchema(f)
+		if err != nil {
+			for _, c := range children {
+				freeSchema(c)
+			}
+			return err
+		}
+		children = append(children, child)
+	}
+
+	initSchema(out.c(), "+s", "", 0, len(children))
+	copy(schemaChildren(out.c()), children)
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package tiledb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrCanceled is returned by the context-aware operations, such as Query.SubmitContext, when they
+// stop because their context.Context is done. The error also wraps the cause of the context,
+// so errors.Is(err, context.Canceled) or errors.Is(err, context.DeadlineExceeded) can be used as well.
+var ErrCanceled = errors.New("tiledb operation canceled")
+
+// canceledError returns the error of an operation stopped because ctx is done.
+func canceledError(ctx context.Context) error {
+	return fmt.Errorf("%w: %w", ErrCanceled, context.Cause(ctx))
+}
+
+// cancelAllTasks cancels the tasks of a Context. It is a variable so that tests can observe the cancellations.
+var cancelAllTasks = (*Context).CancelAllTasks
+
+/*
+runWithContext runs op, which blocks in the TileDB core, on opCtx, a Context that no other operation uses,
+see isolatedContext. It is not run if ctx is already done. If ctx is done while op runs, op is interrupted
+with CancelAllTasks on opCtx. An interrupted op returns a canceled error; an op that completes anyway
+returns its result.
+*/
+func runWithContext(ctx context.Context, opCtx *Context, op func() error) error {
+	if ctx.Err() != nil {
+		return canceledError(ctx)
+	}
+
+	interrupted := make(chan struct{})
+	stop := context.AfterFunc(ctx, func() {
+		defer close(interrupted)
+		// CancelAllTasks only fails if the context is invalid, in which case op fails too.
+		_ = cancelAllTasks(opCtx)
+	})
+	err := op()
+	if stop() {
+		return err
+	}
+
+	<-interrupted
+	if err != nil {
+		return canceledError(ctx)
+	}
+	return err
+}
+
+// isolatedContext returns a new Context with the config of tdbCtx, so that the operations
+// run on it can be canceled without waiting for the other operations of tdbCtx.
+func isolatedContext(tdbCtx *Context) (*Context, error) {
+	config, err := tdbCtx.Config()
+	if err != nil {
+		return nil, err
+	}
+	defer config.Free()
+	return NewContext(config)
+}
@@ -0,0 +1,243 @@
+package tiledb
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubmitContext(t *testing.T) {
+	tdbCtx, err := NewContext(nil)
+	require.NoError(t, err)
+
+	arrayPath := t.TempDir()
+	require.NoError(t, CreateArray(tdbCtx, arrayPath, buildArraySchema(tdbCtx, t)))
+	array, err := NewArray(tdbCtx, arrayPath)
+	require.NoError(t, err)
+
+	type row struct {
+		Dim int8   `tiledb:"dim1"`
+		A1  int32  `tiledb:"a1"`
+		A2  string `tiledb:"a2"`
+	}
+	written := []row{{Dim: 1, A1: 1, A2: "a"}, {Dim: 2, A1: 2, A2: "bb"}}
+	require.NoError(t, array.Open(TILEDB_WRITE))
+	require.NoError(t, Write(tdbCtx, array, written, TILEDB_ROW_MAJOR))
+	require.NoError(t, array.Close())
+
+	require.NoError(t, array.Open(TILEDB_READ))
+	t.Cleanup(func() { require.NoError(t, array.Close()) })
+
+	newQuery := func() *Query {
+		query, err := NewQuery(tdbCtx, array)
+		require.NoError(t, err)
+		require.NoError(t, query.SetLayout(TILEDB_ROW_MAJOR))
+		subarray, err := array.NewSubarray()
+		require.NoError(t, err)
+		require.NoError(t, subarray.SetSubArray([]int8{1, 2}))
+		require.NoError(t, query.SetSubarray(subarray))
+		return query
+	}
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var rows []row
+	err = ReadIntoContext(canceled, newQuery(), &rows)
+	assert.True(t, errors.Is(err, ErrCanceled))
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.Empty(t, rows)
+
+	query := newQuery()
+	assert.True(t, errors.Is(query.SubmitContext(canceled), ErrCanceled))
+	status, err := query.Status()
+	require.NoError(t, err)
+	assert.Equal(t, TILEDB_UNINITIALIZED, status)
+
+	require.NoError(t, ReadIntoContext(context.Background(), newQuery(), &rows))
+	assert.Equal(t, written, rows)
+
+	config, err := NewConfig()
+	require.NoError(t, err)
+	assert.True(t, errors.Is(ConsolidateArrayContext(canceled, tdbCtx, arrayPath, config), ErrCanceled))
+	assert.True(t, errors.Is(VacuumArrayContext(canceled, tdbCtx, arrayPath, config), ErrCanceled))
+}
+
+func TestVFSContext(t *testing.T) {
+	config, err := NewConfig()
+	require.NoError(t, err)
+	tdbCtx, err := NewContext(config)
+	require.NoError(t, err)
+	vfs, err := NewVFS(tdbCtx, config)
+	require.NoError(t, err)
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	path := filepath.Join(t.TempDir(), "file")
+	data := []byte("some data")
+	fh, err := vfs.Open(path, TILEDB_VFS_WRITE)
+	require.NoError(t, err)
+	assert.True(t, errors.Is(vfs.WriteContext(canceled, fh, data), ErrCanceled))
+	require.NoError(t, vfs.WriteContext(context.Background(), fh, data))
+	require.NoError(t, vfs.Close(fh))
+
+	fh, err = vfs.Open(path, TILEDB_VFS_READ)
+	require.NoError(t, err)
+	defer vfs.Close(fh)
+	_, err = vfs.ReadContext(canceled, fh, 0, uint64(len(data)))
+	assert.True(t, errors.Is(err, ErrCanceled))
+	read, err := vfs.ReadContext(context.Background(), fh, 5, 4)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("data"), read)
+}
+
+// fakeCancelAllTasks replaces the cancellation of the tasks of a Context for the test,
+// and returns a channel closed by the first cancellation and the number of cancellations.
+func fakeCancelAllTasks(t *testing.T) (<-chan struct{}, *atomic.Int32) {
+	interrupt := make(chan struct{})
+	var calls atomic.Int32
+	cancelAllTasks = func(*Context) error {
+		if calls.Add(1) == 1 {
+			close(interrupt)
+		}
+		return nil
+	}
+	t.Cleanup(func() { cancelAllTasks = (*Context).CancelAllTasks })
+	return interrupt, &calls
+}
+
+func TestRunWithContextInFlight(t *testing.T) {
+	errInterrupted := errors.New("interrupted by the core")
+	tdbCtx, err := NewContext(nil)
+	require.NoError(t, err)
+	interrupt, calls := fakeCancelAllTasks(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	started := make(chan struct{})
+	go func() {
+		<-started
+		cancel()
+	}()
+	err = runWithContext(ctx, tdbCtx, func() error {
+		close(started)
+		<-interrupt
+		return errInterrupted
+	})
+	assert.True(t, errors.Is(err, ErrCanceled))
+	assert.False(t, errors.Is(err, errInterrupted))
+	assert.EqualValues(t, 1, calls.Load())
+
+	// An operation that completes is not canceled.
+	assert.NoError(t, runWithContext(context.Background(), tdbCtx, func() error { return nil }))
+	assert.EqualValues(t, 1, calls.Load())
+}
+
+func TestSubmitContextInFlight(t *testing.T) {
+	tdbCtx, err := NewContext(nil)
+	require.NoError(t, err)
+	array, cells := createLargeTestArray(t, tdbCtx)
+
+	readQuery := func(end int64) (*Query, []int64) {
+		query, err := NewQuery(tdbCtx, array)
+		require.NoError(t, err)
+		require.NoError(t, query.SetLayout(TILEDB_ROW_MAJOR))
+		subarray, err := array.NewSubarray()
+		require.NoError(t, err)
+		require.NoError(t, subarray.AddRange(0, MakeRange[int64](0, end)))
+		require.NoError(t, query.SetSubarray(subarray))
+		ids := make([]int64, end+1)
+		_, err = query.SetDataBuffer("id", ids)
+		require.NoError(t, err)
+		return query, ids
+	}
+
+	for range 5 {
+		ctx, cancel := context.WithCancel(context.Background())
+		canceled, _ := readQuery(int64(cells - 1))
+		bystander, ids := readQuery(9)
+
+		var wg sync.WaitGroup
+		var canceledErr, bystanderErr error
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			canceledErr = canceled.SubmitContext(ctx)
+		}()
+		go func() {
+			defer wg.Done()
+			bystanderErr = bystander.Submit()
+		}()
+		time.Sleep(time.Millisecond)
+		cancel()
+		wg.Wait()
+
+		// The canceled query may have completed before ctx was canceled.
+		assert.True(t, canceledErr == nil || errors.Is(canceledErr, ErrCanceled), canceledErr)
+		require.NoError(t, bystanderErr)
+		assert.Equal(t, []int64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, ids)
+		// The query is released once its submission completes.
+		canceled.Free()
+		bystander.Free()
+	}
+
+	t.Run("FreeInFlight", func(t *testing.T) {
+		// A submission running in the background keeps the query until it completes.
+		query, _ := readQuery(9)
+		query.beginInflight()
+		query.Free()
+		_, err := query.Status()
+		require.NoError(t, err)
+		query.endInflight()
+	})
+
+	// A query submitted after the cancellations is not affected.
+	query, ids := readQuery(9)
+	require.NoError(t, query.SubmitContext(context.Background()))
+	assert.Equal(t, []int64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, ids)
+}
+
+// createLargeTestArray creates a sparse array with a int64 dimension "id" and a int64 attribute "v",
+// with cells cells whose id and v are 0 to cells-1, so that reading them all takes a while.
+// It returns the array open for reading.
+func createLargeTestArray(t *testing.T, tdbCtx *Context) (array *Array, cells int) {
+	dimension, err := NewDimension(tdbCtx, "id", TILEDB_INT64, []int64{0, 1 << 30}, int64(1<<10))
+	require.NoError(t, err)
+	domain, err := NewDomain(tdbCtx)
+	require.NoError(t, err)
+	require.NoError(t, domain.AddDimensions(dimension))
+	schema, err := NewArraySchema(tdbCtx, TILEDB_SPARSE)
+	require.NoError(t, err)
+	require.NoError(t, schema.SetDomain(domain))
+	attribute, err := NewAttribute(tdbCtx, "v", TILEDB_INT64)
+	require.NoError(t, err)
+	require.NoError(t, schema.AddAttributes(attribute))
+
+	arrayPath := t.TempDir()
+	require.NoError(t, CreateArray(tdbCtx, arrayPath, schema))
+	array, err = NewArray(tdbCtx, arrayPath)
+	require.NoError(t, err)
+
+	type row struct {
+		ID int64 `tiledb:"id"`
+		V  int64 `tiledb:"v"`
+	}
+	rows := make([]row, 1<<20)
+	for i := range rows {
+		rows[i] = row{ID: int64(i), V: int64(i)}
+	}
+	require.NoError(t, array.Open(TILEDB_WRITE))
+	require.NoError(t, Write(tdbCtx, array, rows, TILEDB_UNORDERED))
+	require.NoError(t, array.Close())
+
+	require.NoError(t, array.Open(TILEDB_READ))
+	t.Cleanup(func() { require.NoError(t, array.Close()) })
+	return array, len(rows)
+}
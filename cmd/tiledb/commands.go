@@ -0,0 +1,537 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	tiledb "github.com/TileDB-Inc/TileDB-Go"
+)
+
+// openArray opens the array at uri for reading, with its enumerations loaded.
+func (c *cli) openArray(uri string) (*tiledb.Array, error) {
+	array, err := tiledb.NewArray(c.tdbCtx, uri)
+	if err != nil {
+		return nil, err
+	}
+	if err := array.Open(tiledb.TILEDB_READ); err != nil {
+		array.Free()
+		return nil, err
+	}
+	if err := array.LoadAllEnumerations(); err != nil {
+		closeArray(array)
+		return nil, err
+	}
+	return array, nil
+}
+
+func closeArray(array *tiledb.Array) {
+	_ = array.Close()
+	array.Free()
+}
+
+// openGroup opens the group at uri for reading.
+func (c *cli) openGroup(uri string) (*tiledb.Group, error) {
+	group, err := tiledb.NewGroup(c.tdbCtx, uri)
+	if err != nil {
+		return nil, err
+	}
+	if err := group.Open(tiledb.TILEDB_READ); err != nil {
+		group.Free()
+		return nil, err
+	}
+	return group, nil
+}
+
+func closeGroup(group *tiledb.Group) {
+	_ = group.Close()
+	group.Free()
+}
+
+// objectType returns the type of the object at uri, which must be an array or a group.
+func (c *cli) objectType(uri string) (tiledb.ObjectTypeEnum, error) {
+	objectType, err := tiledb.ObjectType(c.tdbCtx, uri)
+	if err != nil {
+		return tiledb.TILEDB_INVALID, err
+	}
+	if objectType != tiledb.TILEDB_ARRAY && objectType != tiledb.TILEDB_GROUP {
+		return tiledb.TILEDB_INVALID, fmt.Errorf("%s is not a TileDB array or group", uri)
+	}
+	return objectType, nil
+}
+
+func runSchema(c *cli, uri string) error {
+	array, err := c.openArray(uri)
+	if err != nil {
+		return err
+	}
+	defer closeArray(array)
+
+	schema, err := array.Schema()
+	if err != nil {
+		return err
+	}
+	defer schema.Free()
+
+	if c.json {
+		spec, err := schema.Spec()
+		if err != nil {
+			return err
+		}
+		return c.printJSON(spec)
+	}
+
+	dump, err := schema.DumpToString()
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprint(c.stdout, dump)
+	return err
+}
+
+// fragment is the description of a fragment printed by the fragments command.
+type fragment struct {
+	URI            string            `json:"uri"`
+	Type           string            `json:"type"`
+	TimestampStart uint64            `json:"timestamp_start"`
+	TimestampEnd   uint64            `json:"timestamp_end"`
+	CellNum        uint64            `json:"cell_num"`
+	Size           uint64            `json:"size"`
+	Version        uint32            `json:"version"`
+	SchemaName     string            `json:"schema_name"`
+	NonEmptyDomain []dimensionDomain `json:"non_empty_domain"`
+}
+
+// dimensionDomain is the non-empty domain of a dimension, Bounds is nil if it is empty.
+type dimensionDomain struct {
+	Name   string `json:"name"`
+	Bounds []any  `json:"bounds"`
+}
+
+func runFragments(c *cli, uri string) error {
+	fragmentInfo, err := tiledb.NewFragmentInfo(c.tdbCtx, uri)
+	if err != nil {
+		return err
+	}
+	defer fragmentInfo.Free()
+	if err := fragmentInfo.Load(); err != nil {
+		return err
+	}
+
+	num, err := fragmentInfo.GetFragmentNum()
+	if err != nil {
+		return err
+	}
+	fragments := make([]fragment, num)
+	for fid := range fragments {
+		if fragments[fid], err = loadFragment(fragmentInfo, uint32(fid)); err != nil {
+			return fmt.Errorf("fragment %d: %w", fid, err)
+		}
+	}
+	toVacuum, err := fragmentInfo.GetToVacuumNum()
+	if err != nil {
+		return err
+	}
+
+	if c.json {
+		return c.printJSON(fragments)
+	}
+
+	tw := tabwriter.NewWriter(c.stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "#\tTYPE\tSTART\tEND\tCELLS\tSIZE\tVERSION\tNON-EMPTY DOMAIN\tURI")
+	for fid, f := range fragments {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%d\t%d\t%d\t%s\t%s\n", fid, f.Type,
+			formatTimestamp(f.TimestampStart), formatTimestamp(f.TimestampEnd),
+			f.CellNum, f.Size, f.Version, formatDomains(f.NonEmptyDomain, " "), f.URI)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(c.stdout, "%d fragments, %d to vacuum\n", num, toVacuum)
+	return err
+}
+
+func loadFragment(fragmentInfo *tiledb.FragmentInfo, fid uint32) (fragment, error) {
+	var f fragment
+	var err error
+
+	if f.URI, err = fragmentInfo.GetFragmentURI(fid); err != nil {
+		return f, err
+	}
+	dense, err := fragmentInfo.GetDense(fid)
+	if err != nil {
+		return f, err
+	}
+	f.Type = "sparse"
+	if dense {
+		f.Type = "dense"
+	}
+	if f.TimestampStart, f.TimestampEnd, err = fragmentInfo.GetTimestampRange(fid); err != nil {
+		return f, err
+	}
+	if f.CellNum, err = fragmentInfo.GetCellNum(fid); err != nil {
+		return f, err
+	}
+	if f.Size, err = fragmentInfo.GetFragmentSize(fid); err != nil {
+		return f, err
+	}
+	if f.Version, err = fragmentInfo.GetVersion(fid); err != nil {
+		return f, err
+	}
+	if f.SchemaName, err = fragmentInfo.GetArraySchemaName(fid); err != nil {
+		return f, err
+	}
+
+	schema, err := fragmentInfo.GetArraySchema(fid)
+	if err != nil {
+		return f, err
+	}
+	defer schema.Free()
+	dimensions, err := schemaDimensions(schema)
+	if err != nil {
+		return f, err
+	}
+	for did, dimension := range dimensions {
+		var nonEmptyDomain *tiledb.NonEmptyDomain
+		if dimension.isVar {
+			nonEmptyDomain, err = fragmentInfo.GetNonEmptyDomainVarFromIndex(fid, uint32(did))
+		} else {
+			nonEmptyDomain, err = fragmentInfo.GetNonEmptyDomainFromIndex(fid, uint32(did))
+		}
+		if err != nil {
+			return f, err
+		}
+		f.NonEmptyDomain = append(f.NonEmptyDomain, dimensionDomain{Name: dimension.name, Bounds: boundsValues(nonEmptyDomain.Bounds)})
+	}
+
+	return f, nil
+}
+
+// schemaDimension is the name of a dimension and whether it is var-sized.
+type schemaDimension struct {
+	name  string
+	isVar bool
+}
+
+func schemaDimensions(schema *tiledb.ArraySchema) ([]schemaDimension, error) {
+	domain, err := schema.Domain()
+	if err != nil {
+		return nil, err
+	}
+	defer domain.Free()
+	nDim, err := domain.NDim()
+	if err != nil {
+		return nil, err
+	}
+
+	dimensions := make([]schemaDimension, nDim)
+	for i := range dimensions {
+		dimension, err := domain.DimensionFromIndex(uint(i))
+		if err != nil {
+			return nil, err
+		}
+		name, err := dimension.Name()
+		if err != nil {
+			dimension.Free()
+			return nil, err
+		}
+		cellValNum, err := dimension.CellValNum()
+		dimension.Free()
+		if err != nil {
+			return nil, err
+		}
+		dimensions[i] = schemaDimension{name: name, isVar: cellValNum == tiledb.TILEDB_VAR_NUM}
+	}
+	return dimensions, nil
+}
+
+func runDomain(c *cli, uri string) error {
+	array, err := c.openArray(uri)
+	if err != nil {
+		return err
+	}
+	defer closeArray(array)
+
+	schema, err := array.Schema()
+	if err != nil {
+		return err
+	}
+	defer schema.Free()
+	dimensions, err := schemaDimensions(schema)
+	if err != nil {
+		return err
+	}
+	nonEmptyDomains, err := array.NonEmptyDomainMap()
+	if err != nil {
+		return err
+	}
+
+	domains := make([]dimensionDomain, len(dimensions))
+	for i, dimension := range dimensions {
+		domains[i] = dimensionDomain{Name: dimension.name, Bounds: boundsValues(nonEmptyDomains[dimension.name])}
+	}
+
+	if c.json {
+		return c.printJSON(domains)
+	}
+	_, err = fmt.Fprintln(c.stdout, formatDomains(domains, "\n"))
+	return err
+}
+
+// boundsValues returns the lower and upper bounds of a non-empty domain, or nil if it is empty.
+// Byte slices would be encoded to JSON as base64, so the values are copied to a []any.
+func boundsValues(bounds any) []any {
+	v := reflect.ValueOf(bounds)
+	if !v.IsValid() || v.Kind() != reflect.Slice || v.Len() != 2 {
+		return nil
+	}
+	return []any{v.Index(0).Interface(), v.Index(1).Interface()}
+}
+
+func formatDomains(domains []dimensionDomain, sep string) string {
+	parts := make([]string, len(domains))
+	for i, d := range domains {
+		if d.Bounds == nil {
+			parts[i] = d.Name + ": empty"
+		} else {
+			parts[i] = fmt.Sprintf("%s: [%v, %v]", d.Name, d.Bounds[0], d.Bounds[1])
+		}
+	}
+	return strings.Join(parts, sep)
+}
+
+func formatTimestamp(ms uint64) string {
+	return time.UnixMilli(int64(ms)).UTC().Format("2006-01-02T15:04:05.000Z")
+}
+
+// metadataValue is a metadata item printed by the metadata command.
+type metadataValue struct {
+	datatype tiledb.Datatype
+	valueNum uint
+	value    any
+}
+
+func runMetadata(c *cli, uri string) error {
+	objectType, err := c.objectType(uri)
+	if err != nil {
+		return err
+	}
+
+	metadata := make(map[string]metadataValue)
+	var jsonValue any
+	if objectType == tiledb.TILEDB_ARRAY {
+		array, err := c.openArray(uri)
+		if err != nil {
+			return err
+		}
+		defer closeArray(array)
+		arrayMetadata, err := array.GetMetadataMap()
+		if err != nil {
+			return err
+		}
+		for key, m := range arrayMetadata {
+			metadata[key] = metadataValue{datatype: m.Datatype, valueNum: m.ValueNum, value: m.Value}
+		}
+		jsonValue = arrayMetadata
+	} else {
+		group, err := c.openGroup(uri)
+		if err != nil {
+			return err
+		}
+		defer closeGroup(group)
+		groupMetadata, err := group.GetMetadataMap()
+		if err != nil {
+			return err
+		}
+		for key, m := range groupMetadata {
+			metadata[key] = metadataValue{datatype: m.Datatype, valueNum: m.ValueNum, value: m.Value}
+		}
+		jsonValue = groupMetadata
+	}
+
+	if c.json {
+		return c.printJSON(jsonValue)
+	}
+
+	keys := make([]string, 0, len(metadata))
+	for key := range metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	tw := tabwriter.NewWriter(c.stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "KEY\tTYPE\tNUM\tVALUE")
+	for _, key := range keys {
+		m := metadata[key]
+		value := m.value
+		if b, ok := value.([]byte); ok {
+			value = string(b)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%v\n", key, m.datatype, m.valueNum, value)
+	}
+	return tw.Flush()
+}
+
+func setModeFlag(c *cli, fs *flag.FlagSet) {
+	fs.StringVar(&c.mode, "mode", "",
+		"what to consolidate or vacuum: fragments, fragment_meta, commits or array_meta (default fragments for arrays, array_meta for groups)")
+}
+
+// parseMode returns the consolidation mode of the -mode flag for an object of objectType.
+// Only the metadata of groups can be consolidated and vacuumed.
+func (c *cli) parseMode(objectType tiledb.ObjectTypeEnum) (tiledb.ConsolidationMode, error) {
+	mode := tiledb.ConsolidationMode(c.mode)
+	if objectType == tiledb.TILEDB_GROUP {
+		if mode != "" && mode != tiledb.TILEDB_CONSOLIDATION_ARRAY_META {
+			return "", fmt.Errorf("mode %q is not supported for groups, only %s", c.mode, tiledb.TILEDB_CONSOLIDATION_ARRAY_META)
+		}
+		return tiledb.TILEDB_CONSOLIDATION_ARRAY_META, nil
+	}
+	if mode == "" {
+		return tiledb.TILEDB_CONSOLIDATION_FRAGMENTS, nil
+	}
+	switch mode {
+	case tiledb.TILEDB_CONSOLIDATION_FRAGMENTS, tiledb.TILEDB_CONSOLIDATION_FRAGMENT_META,
+		tiledb.TILEDB_CONSOLIDATION_COMMITS, tiledb.TILEDB_CONSOLIDATION_ARRAY_META:
+		return mode, nil
+	}
+	return "", fmt.Errorf("unknown mode %q", c.mode)
+}
+
+// maintenanceResult is printed by the consolidate and vacuum commands.
+type maintenanceResult struct {
+	URI  string `json:"uri"`
+	Type string `json:"type"`
+	Mode string `json:"mode"`
+}
+
+func runConsolidate(c *cli, uri string) error {
+	return c.maintain(uri, "consolidated", tiledb.ConsolidateArrayWithMode, (*tiledb.Group).ConsolidateMetadata)
+}
+
+func runVacuum(c *cli, uri string) error {
+	return c.maintain(uri, "vacuumed", tiledb.VacuumArrayWithMode, (*tiledb.Group).VacuumMetadata)
+}
+
+// maintain consolidates or vacuums the array or group at uri.
+func (c *cli) maintain(uri, done string,
+	arrayFunc func(*tiledb.Context, string, tiledb.ConsolidationMode, *tiledb.Config) error,
+	groupFunc func(*tiledb.Group, *tiledb.Config) error) error {
+	objectType, err := c.objectType(uri)
+	if err != nil {
+		return err
+	}
+	mode, err := c.parseMode(objectType)
+	if err != nil {
+		return err
+	}
+
+	if objectType == tiledb.TILEDB_ARRAY {
+		err = arrayFunc(c.tdbCtx, uri, mode, c.config)
+	} else {
+		var group *tiledb.Group
+		if group, err = tiledb.NewGroup(c.tdbCtx, uri); err == nil {
+			err = groupFunc(group, c.config)
+			group.Free()
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	result := maintenanceResult{URI: uri, Type: strings.ToLower(objectType.String()), Mode: mode.String()}
+	if c.json {
+		return c.printJSON(result)
+	}
+	_, err = fmt.Fprintf(c.stdout, "%s %s of %s %s\n", done, result.Mode, result.Type, uri)
+	return err
+}
+
+func setWalkFlags(c *cli, fs *flag.FlagSet) {
+	fs.StringVar(&c.order, "order", "preorder", "traversal order: preorder or postorder")
+	fs.BoolVar(&c.ls, "ls", false, "only list the objects directly under the path")
+}
+
+// object is a TileDB object printed by the walk command.
+type object struct {
+	Type string `json:"type"`
+	URI  string `json:"uri"`
+}
+
+func runWalk(c *cli, uri string) error {
+	var objects *tiledb.ObjectList
+	var err error
+	switch {
+	case c.ls:
+		objects, err = tiledb.ObjectLs(c.tdbCtx, uri)
+	case c.order == "preorder":
+		objects, err = tiledb.ObjectWalk(c.tdbCtx, uri, tiledb.TILEDB_PREORDER)
+	case c.order == "postorder":
+		objects, err = tiledb.ObjectWalk(c.tdbCtx, uri, tiledb.TILEDB_POSTORDER)
+	default:
+		return fmt.Errorf("unknown order %q", c.order)
+	}
+	if err != nil {
+		return err
+	}
+
+	list := make([]object, objects.Len())
+	for i := range list {
+		path, objectType := objects.At(i)
+		list[i] = object{Type: strings.ToLower(objectType.String()), URI: path}
+	}
+
+	if c.json {
+		return c.printJSON(list)
+	}
+	tw := tabwriter.NewWriter(c.stdout, 0, 4, 2, ' ', 0)
+	for _, o := range list {
+		fmt.Fprintf(tw, "%s\t%s\n", o.Type, o.URI)
+	}
+	return tw.Flush()
+}
+
+func setGroupFlags(c *cli, fs *flag.FlagSet) {
+	fs.BoolVar(&c.recursive, "r", false, "include the members of subgroups in the text output")
+}
+
+// member is a group member printed by the group command.
+type member struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	URI  string `json:"uri"`
+}
+
+func runGroup(c *cli, uri string) error {
+	group, err := c.openGroup(uri)
+	if err != nil {
+		return err
+	}
+	defer closeGroup(group)
+
+	if !c.json {
+		dump, err := group.Dump(c.recursive)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(c.stdout, strings.TrimRight(dump, "\n"))
+		return err
+	}
+
+	count, err := group.GetMemberCount()
+	if err != nil {
+		return err
+	}
+	members := make([]member, count)
+	for i := range members {
+		memberURI, name, objectType, err := group.GetMemberFromIndex(uint64(i))
+		if err != nil {
+			return err
+		}
+		members[i] = member{Name: name, Type: strings.ToLower(objectType.String()), URI: memberURI}
+	}
+	return c.printJSON(members)
+}
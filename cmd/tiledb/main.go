@@ -0,0 +1,190 @@
+// tiledb inspects and manages TileDB arrays and groups.
+//
+// Usage:
+//
+//	tiledb <command> [flags] <uri>
+//
+// The commands are:
+//
+//	schema       print the schema of an array
+//	fragments    list the fragments of an array
+//	domain       print the non-empty domain of an array
+//	metadata     print the metadata of an array or a group
+//	consolidate  consolidate an array, or the metadata of a group
+//	vacuum       vacuum an array, or the metadata of a group
+//	walk         list the TileDB objects under a path
+//	group        print the members of a group
+//
+// Every command prints human-readable text, or JSON with -json, and accepts
+// -config param=value, which can be repeated, to set TileDB config parameters:
+//
+//	tiledb fragments -json -config vfs.s3.region=us-east-1 s3://bucket/array
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	tiledb "github.com/TileDB-Inc/TileDB-Go"
+)
+
+// command is a subcommand of the CLI.
+type command struct {
+	name    string
+	summary string
+	// setFlags registers the flags of the command, it can be nil.
+	setFlags func(c *cli, fs *flag.FlagSet)
+	run      func(c *cli, uri string) error
+}
+
+var commands = []command{
+	{name: "schema", summary: "print the schema of an array", run: runSchema},
+	{name: "fragments", summary: "list the fragments of an array", run: runFragments},
+	{name: "domain", summary: "print the non-empty domain of an array", run: runDomain},
+	{name: "metadata", summary: "print the metadata of an array or a group", run: runMetadata},
+	{name: "consolidate", summary: "consolidate an array, or the metadata of a group", setFlags: setModeFlag, run: runConsolidate},
+	{name: "vacuum", summary: "vacuum an array, or the metadata of a group", setFlags: setModeFlag, run: runVacuum},
+	{name: "walk", summary: "list the TileDB objects under a path", setFlags: setWalkFlags, run: runWalk},
+	{name: "group", summary: "print the members of a group", setFlags: setGroupFlags, run: runGroup},
+}
+
+// cli holds the state shared by the commands.
+type cli struct {
+	stdout io.Writer
+	json   bool
+	params configParams
+	config *tiledb.Config
+	tdbCtx *tiledb.Context
+
+	// Flags of the commands
+	mode      string
+	order     string
+	ls        bool
+	recursive bool
+}
+
+// errUsage is returned for invalid command lines, after the usage has been printed.
+var errUsage = errors.New("invalid usage")
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run runs the command line args and returns the exit code.
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "-help" || args[0] == "help" {
+		printUsage(stderr)
+		return 2
+	}
+
+	for _, cmd := range commands {
+		if cmd.name != args[0] {
+			continue
+		}
+
+		c := &cli{stdout: stdout}
+		err := c.run(cmd, args[1:], stderr)
+		switch {
+		case err == nil:
+			return 0
+		case errors.Is(err, errUsage) || errors.Is(err, flag.ErrHelp):
+			return 2
+		default:
+			fmt.Fprintf(stderr, "tiledb %s: %v\n", cmd.name, err)
+			return 1
+		}
+	}
+
+	fmt.Fprintf(stderr, "tiledb: unknown command %q\n", args[0])
+	printUsage(stderr)
+	return 2
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: tiledb <command> [flags] <uri>")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-12s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Run 'tiledb <command> -h' for the flags of a command.")
+}
+
+// run parses the flags of cmd, creates the TileDB context and runs cmd.
+func (c *cli) run(cmd command, args []string, stderr io.Writer) error {
+	fs := flag.NewFlagSet("tiledb "+cmd.name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: tiledb %s [flags] <uri>\n\n%s.\n\nFlags:\n", cmd.name, cmd.summary)
+		fs.PrintDefaults()
+	}
+	fs.BoolVar(&c.json, "json", false, "print JSON instead of text")
+	fs.Var(&c.params, "config", "set the TileDB config `param=value`, can be repeated")
+	if cmd.setFlags != nil {
+		cmd.setFlags(c, fs)
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errUsage
+	}
+
+	config, err := tiledb.NewConfig()
+	if err != nil {
+		return err
+	}
+	defer config.Free()
+	for _, param := range c.params {
+		if err := config.Set(param.name, param.value); err != nil {
+			return err
+		}
+	}
+	tdbCtx, err := tiledb.NewContext(config)
+	if err != nil {
+		return err
+	}
+	defer tdbCtx.Free()
+
+	c.config, c.tdbCtx = config, tdbCtx
+	return cmd.run(c, fs.Arg(0))
+}
+
+// printJSON prints v as indented JSON.
+func (c *cli) printJSON(v any) error {
+	encoder := json.NewEncoder(c.stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// configParams is the flag of the TileDB config parameters.
+type configParams []configParam
+
+type configParam struct {
+	name, value string
+}
+
+func (p *configParams) String() string {
+	params := make([]string, len(*p))
+	for i, param := range *p {
+		params[i] = param.name + "=" + param.value
+	}
+	return strings.Join(params, ",")
+}
+
+func (p *configParams) Set(s string) error {
+	name, value, ok := strings.Cut(s, "=")
+	if !ok || name == "" {
+		return fmt.Errorf("config %q is not param=value", s)
+	}
+	*p = append(*p, configParam{name: name, value: value})
+	return nil
+}
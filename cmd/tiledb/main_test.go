@@ -0,0 +1,142 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tiledb "github.com/TileDB-Inc/TileDB-Go"
+)
+
+// createTestGroup creates a group with metadata, and a sparse array member "array"
+// with a int32 dimension "d", a int32 attribute "a", three cells and metadata.
+// It returns the URIs of the group and of the array.
+func createTestGroup(t *testing.T) (groupURI, arrayURI string) {
+	tdbCtx, err := tiledb.NewContext(nil)
+	require.NoError(t, err)
+	t.Cleanup(tdbCtx.Free)
+
+	groupURI = t.TempDir()
+	arrayURI = filepath.Join(groupURI, "array")
+
+	dimension, err := tiledb.NewDimension(tdbCtx, "d", tiledb.TILEDB_INT32, []int32{1, 10}, int32(5))
+	require.NoError(t, err)
+	domain, err := tiledb.NewDomain(tdbCtx)
+	require.NoError(t, err)
+	require.NoError(t, domain.AddDimensions(dimension))
+	schema, err := tiledb.NewArraySchema(tdbCtx, tiledb.TILEDB_SPARSE)
+	require.NoError(t, err)
+	require.NoError(t, schema.SetDomain(domain))
+	attribute, err := tiledb.NewAttribute(tdbCtx, "a", tiledb.TILEDB_INT32)
+	require.NoError(t, err)
+	require.NoError(t, schema.AddAttributes(attribute))
+	require.NoError(t, tiledb.CreateArray(tdbCtx, arrayURI, schema))
+
+	array, err := tiledb.NewArray(tdbCtx, arrayURI)
+	require.NoError(t, err)
+	defer array.Free()
+	type cell struct {
+		D int32 `tiledb:"d"`
+		A int32 `tiledb:"a"`
+	}
+	require.NoError(t, array.Open(tiledb.TILEDB_WRITE))
+	require.NoError(t, tiledb.Write(tdbCtx, array, []cell{{1, 10}, {2, 20}, {3, 30}}, tiledb.TILEDB_UNORDERED))
+	require.NoError(t, array.PutMetadata("version", int32(3)))
+	require.NoError(t, array.Close())
+
+	require.NoError(t, tiledb.CreateGroup(tdbCtx, groupURI))
+	group, err := tiledb.NewGroup(tdbCtx, groupURI)
+	require.NoError(t, err)
+	defer group.Free()
+	require.NoError(t, group.Open(tiledb.TILEDB_WRITE))
+	require.NoError(t, group.AddMember(arrayURI, "array", false))
+	require.NoError(t, group.PutMetadata("owner", "team"))
+	require.NoError(t, group.Close())
+
+	return groupURI, arrayURI
+}
+
+func TestRun(t *testing.T) {
+	groupURI, arrayURI := createTestGroup(t)
+
+	tests := []struct {
+		name string
+		args []string
+		code int
+		// stdout and stderr must contain these strings, and the output of -json must be valid JSON.
+		stdout []string
+		stderr []string
+	}{
+		{name: "Schema", args: []string{"schema", arrayURI}, stdout: []string{"sparse", "d", "a"}},
+		{name: "SchemaJSON", args: []string{"schema", "-json", arrayURI},
+			stdout: []string{`"type": "sparse"`, `"name": "d"`, `"name": "a"`}},
+		{name: "Fragments", args: []string{"fragments", arrayURI},
+			stdout: []string{"NON-EMPTY DOMAIN", "d: [1, 3]", "1 fragments, 0 to vacuum"}},
+		{name: "FragmentsJSON", args: []string{"fragments", "-json", arrayURI},
+			stdout: []string{`"type": "sparse"`, `"cell_num": 3`}},
+		{name: "Domain", args: []string{"domain", arrayURI}, stdout: []string{"d: [1, 3]"}},
+		{name: "DomainJSON", args: []string{"domain", "-json", arrayURI}, stdout: []string{`"name": "d"`, `"bounds"`}},
+		{name: "ArrayMetadata", args: []string{"metadata", arrayURI}, stdout: []string{"KEY", "version", "INT32"}},
+		{name: "ArrayMetadataJSON", args: []string{"metadata", "-json", arrayURI}, stdout: []string{`"version": 3`}},
+		{name: "GroupMetadata", args: []string{"metadata", groupURI}, stdout: []string{"KEY", "owner", "team"}},
+		{name: "GroupMetadataJSON", args: []string{"metadata", "-json", groupURI}, stdout: []string{`"owner": "team"`}},
+		{name: "Walk", args: []string{"walk", groupURI}, stdout: []string{"array"}},
+		{name: "WalkJSON", args: []string{"walk", "-json", groupURI}, stdout: []string{`"type": "array"`}},
+		{name: "Ls", args: []string{"walk", "-ls", "-json", groupURI}, stdout: []string{`"type": "array"`}},
+		{name: "Group", args: []string{"group", groupURI}, stdout: []string{"array"}},
+		{name: "GroupJSON", args: []string{"group", "-json", groupURI},
+			stdout: []string{`"name": "array"`, `"type": "array"`}},
+		{name: "ConsolidateArray", args: []string{"consolidate", arrayURI},
+			stdout: []string{"consolidated fragments of array " + arrayURI}},
+		{name: "ConsolidateArrayJSON", args: []string{"consolidate", "-json", "-mode", "array_meta", arrayURI},
+			stdout: []string{`"type": "array"`, `"mode": "array_meta"`}},
+		{name: "ConsolidateGroup", args: []string{"consolidate", groupURI},
+			stdout: []string{"consolidated array_meta of group " + groupURI}},
+		{name: "ConsolidateGroupJSON", args: []string{"consolidate", "-json", "-mode", "array_meta", groupURI},
+			stdout: []string{`"type": "group"`, `"mode": "array_meta"`}},
+		{name: "VacuumArray", args: []string{"vacuum", arrayURI},
+			stdout: []string{"vacuumed fragments of array " + arrayURI}},
+		{name: "VacuumArrayJSON", args: []string{"vacuum", "-json", "-mode", "commits", arrayURI},
+			stdout: []string{`"type": "array"`, `"mode": "commits"`}},
+		{name: "VacuumGroup", args: []string{"vacuum", groupURI},
+			stdout: []string{"vacuumed array_meta of group " + groupURI}},
+		{name: "VacuumGroupJSON", args: []string{"vacuum", "-json", groupURI},
+			stdout: []string{`"type": "group"`, `"mode": "array_meta"`}},
+
+		{name: "GroupMode", args: []string{"consolidate", "-mode", "fragments", groupURI}, code: 1,
+			stderr: []string{"tiledb consolidate:", `mode "fragments" is not supported for groups`}},
+		{name: "UnknownMode", args: []string{"vacuum", "-mode", "everything", arrayURI}, code: 1,
+			stderr: []string{`unknown mode "everything"`}},
+		{name: "UnknownOrder", args: []string{"walk", "-order", "inorder", groupURI}, code: 1,
+			stderr: []string{`unknown order "inorder"`}},
+		{name: "NotAnArray", args: []string{"schema", groupURI}, code: 1, stderr: []string{"tiledb schema:"}},
+		{name: "NotAnObject", args: []string{"metadata", t.TempDir()}, code: 1,
+			stderr: []string{"is not a TileDB array or group"}},
+		{name: "NoURI", args: []string{"domain"}, code: 2, stderr: []string{"Usage: tiledb domain"}},
+		{name: "UnknownFlag", args: []string{"domain", "-x", arrayURI}, code: 2},
+		{name: "Help", args: []string{"help"}, code: 2, stderr: []string{"Usage: tiledb <command>", "consolidate"}},
+		{name: "NoCommand", code: 2, stderr: []string{"Usage: tiledb <command>"}},
+		{name: "UnknownCommand", args: []string{"list", arrayURI}, code: 2, stderr: []string{`unknown command "list"`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			code := run(tt.args, &stdout, &stderr)
+			require.Equal(t, tt.code, code, stderr.String())
+			for _, s := range tt.stdout {
+				assert.Contains(t, stdout.String(), s)
+			}
+			for _, s := range tt.stderr {
+				assert.Contains(t, stderr.String(), s)
+			}
+			if code == 0 && slices.Contains(tt.args, "-json") {
+				assert.True(t, json.Valid(stdout.Bytes()), stdout.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package tiledb
+
+import "fmt"
+
+// ConsolidationMode selects what ConsolidateArray consolidates and VacuumArray removes.
+// It is the value of the sm.consolidation.mode and sm.vacuum.mode config parameters.
+type ConsolidationMode string
+
+const (
+	// TILEDB_CONSOLIDATION_FRAGMENTS consolidates fragments into a single fragment,
+	// vacuuming removes the consolidated fragments. It is the default mode.
+	TILEDB_CONSOLIDATION_FRAGMENTS ConsolidationMode = "fragments"
+	// TILEDB_CONSOLIDATION_FRAGMENT_META consolidates the footers of the fragments into a single file,
+	// vacuuming removes the consolidated fragment metadata files.
+	TILEDB_CONSOLIDATION_FRAGMENT_META ConsolidationMode = "fragment_meta"
+	// TILEDB_CONSOLIDATION_COMMITS consolidates the commit files of the fragments into a single file,
+	// vacuuming removes the consolidated commit files.
+	TILEDB_CONSOLIDATION_COMMITS ConsolidationMode = "commits"
+	// TILEDB_CONSOLIDATION_ARRAY_META consolidates the array metadata,
+	// vacuuming removes the consolidated array metadata files.
+	TILEDB_CONSOLIDATION_ARRAY_META ConsolidationMode = "array_meta"
+)
+
+// String returns the config value of the mode.
+func (m ConsolidationMode) String() string {
+	return string(m)
+}
+
+// SetConsolidationMode sets the sm.consolidation.mode parameter used by ConsolidateArray.
+func (c *Config) SetConsolidationMode(mode ConsolidationMode) error {
+	return c.Set("sm.consolidation.mode", mode.String())
+}
+
+// SetVacuumMode sets the sm.vacuum.mode parameter used by VacuumArray.
+func (c *Config) SetVacuumMode(mode ConsolidationMode) error {
+	return c.Set("sm.vacuum.mode", mode.String())
+}
+
+// ConsolidateArrayWithMode consolidates the array at uri with the given mode.
+// The parameters of config are used except for sm.consolidation.mode, config is not modified and can be nil.
+func ConsolidateArrayWithMode(tdbCtx *Context, uri string, mode ConsolidationMode, config *Config) error {
+	modeConfig, err := configWith(config, "sm.consolidation.mode", mode.String())
+	if err != nil {
+		return fmt.Errorf("error consolidating tiledb array: %w", err)
+	}
+	defer modeConfig.Free()
+
+	return ConsolidateArray(tdbCtx, uri, modeConfig)
+}
+
+// VacuumArrayWithMode vacuums the array at uri with the given mode.
+// The parameters of config are used except for sm.vacuum.mode, config is not modified and can be nil.
+func VacuumArrayWithMode(tdbCtx *Context, uri string, mode ConsolidationMode, config *Config) error {
+	modeConfig, err := configWith(config, "sm.vacuum.mode", mode.String())
+	if err != nil {
+		return fmt.Errorf("error vacuuming tiledb array: %w", err)
+	}
+	defer modeConfig.Free()
+
+	return VacuumArray(tdbCtx, uri, modeConfig)
+}
+
+// configWith returns a new config with the parameters of config, which can be nil,
+// and param set to value.
+func configWith(config *Config, param, value string) (*Config, error) {
+	newConfig, err := NewConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	if config != nil {
+		iter, err := config.Iterate("")
+		if err != nil {
+			newConfig.Free()
+			return nil, err
+		}
+		defer iter.Free()
+
+		for !iter.IsDone() {
+			p, v, err := iter.Here()
+			if err == nil {
+				err = newConfig.Set(*p, *v)
+			}
+			if err == nil {
+				err = iter.Next()
+			}
+			if err != nil {
+				newConfig.Free()
+				return nil, err
+			}
+		}
+	}
+
+	if err := newConfig.Set(param, value); err != nil {
+		newConfig.Free()
+		return nil, err
+	}
+
+	return newConfig, nil
+}
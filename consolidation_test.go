@@ -0,0 +1,63 @@
+package tiledb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsolidationMode(t *testing.T) {
+	config, err := NewConfig()
+	require.NoError(t, err)
+
+	require.NoError(t, config.SetConsolidationMode(TILEDB_CONSOLIDATION_COMMITS))
+	mode, err := config.Get("sm.consolidation.mode")
+	require.NoError(t, err)
+	assert.Equal(t, "commits", mode)
+
+	require.NoError(t, config.SetVacuumMode(TILEDB_CONSOLIDATION_ARRAY_META))
+	mode, err = config.Get("sm.vacuum.mode")
+	require.NoError(t, err)
+	assert.Equal(t, "array_meta", mode)
+}
+
+func TestConsolidateArrayWithMode(t *testing.T) {
+	array := create1DTestArray(t)
+	for i := 0; i < 3; i++ {
+		write1DTestArray(t, array, []int32{0, 1, 2, 3, 4, 5, 6, 7, 8, 9})
+	}
+
+	fragmentNum := func() uint32 {
+		fragmentInfo, err := NewFragmentInfo(array.context, array.uri)
+		require.NoError(t, err)
+		defer fragmentInfo.Free()
+		require.NoError(t, fragmentInfo.Load())
+		num, err := fragmentInfo.GetFragmentNum()
+		require.NoError(t, err)
+		return num
+	}
+
+	config, err := NewConfig()
+	require.NoError(t, err)
+	require.NoError(t, config.Set("sm.consolidation.steps", "1"))
+
+	// Consolidating commits and fragment metadata keeps the fragments
+	require.NoError(t, ConsolidateArrayWithMode(array.context, array.uri, TILEDB_CONSOLIDATION_COMMITS, config))
+	require.NoError(t, VacuumArrayWithMode(array.context, array.uri, TILEDB_CONSOLIDATION_COMMITS, config))
+	require.NoError(t, ConsolidateArrayWithMode(array.context, array.uri, TILEDB_CONSOLIDATION_FRAGMENT_META, nil))
+	require.NoError(t, VacuumArrayWithMode(array.context, array.uri, TILEDB_CONSOLIDATION_FRAGMENT_META, nil))
+	assert.Equal(t, uint32(3), fragmentNum())
+
+	require.NoError(t, ConsolidateArrayWithMode(array.context, array.uri, TILEDB_CONSOLIDATION_FRAGMENTS, config))
+	require.NoError(t, VacuumArrayWithMode(array.context, array.uri, TILEDB_CONSOLIDATION_FRAGMENTS, config))
+	assert.Equal(t, uint32(1), fragmentNum())
+
+	// The config of the caller is not modified
+	mode, err := config.Get("sm.consolidation.mode")
+	require.NoError(t, err)
+	assert.Equal(t, "fragments", mode)
+	steps, err := config.Get("sm.consolidation.steps")
+	require.NoError(t, err)
+	assert.Equal(t, "1", steps)
+}
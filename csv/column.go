@@ -0,0 +1,233 @@
+package csv
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+
+	tiledb "github.com/TileDB-Inc/TileDB-Go"
+)
+
+// column is a dimension or attribute of a TileDB array.
+type column struct {
+	name       string
+	datatype   tiledb.Datatype
+	cellValNum uint32
+	nullable   bool
+	dimension  bool
+}
+
+// arrayColumns returns the columns names of the open array, or all its dimensions and attributes
+// if names is empty. It fails if a column cannot be represented in CSV.
+func arrayColumns(array *tiledb.Array, names []string) ([]*column, error) {
+	schema, err := array.Schema()
+	if err != nil {
+		return nil, err
+	}
+	defer schema.Free()
+
+	domain, err := schema.Domain()
+	if err != nil {
+		return nil, err
+	}
+	defer domain.Free()
+
+	if len(names) == 0 {
+		names, err = columnNames(schema, domain)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	columns := make([]*column, len(names))
+	for i, name := range names {
+		columns[i], err = arrayColumn(schema, domain, name)
+		if err != nil {
+			return nil, err
+		}
+		if err := columns[i].check(); err != nil {
+			return nil, err
+		}
+	}
+
+	return columns, nil
+}
+
+// columnNames returns the names of the dimensions and the attributes of schema.
+func columnNames(schema *tiledb.ArraySchema, domain *tiledb.Domain) ([]string, error) {
+	nDim, err := domain.NDim()
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for i := uint(0); i < nDim; i++ {
+		dimension, err := domain.DimensionFromIndex(i)
+		if err != nil {
+			return nil, err
+		}
+		name, err := dimension.Name()
+		dimension.Free()
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+
+	attributes, err := schema.Attributes()
+	if err != nil {
+		return nil, err
+	}
+	for _, attribute := range attributes {
+		name, err := attribute.Name()
+		attribute.Free()
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+
+	return names, nil
+}
+
+func arrayColumn(schema *tiledb.ArraySchema, domain *tiledb.Domain, name string) (*column, error) {
+	c := &column{name: name}
+
+	hasDim, err := domain.HasDimension(name)
+	if err != nil {
+		return nil, err
+	}
+	if hasDim {
+		dimension, err := domain.DimensionFromName(name)
+		if err != nil {
+			return nil, err
+		}
+		defer dimension.Free()
+
+		c.dimension = true
+		if c.datatype, err = dimension.Type(); err != nil {
+			return nil, err
+		}
+		if c.cellValNum, err = dimension.CellValNum(); err != nil {
+			return nil, err
+		}
+		return c, nil
+	}
+
+	hasAttr, err := schema.HasAttribute(name)
+	if err != nil {
+		return nil, err
+	}
+	if !hasAttr {
+		return nil, fmt.Errorf("no attribute or dimension named %s", name)
+	}
+
+	attribute, err := schema.AttributeFromName(name)
+	if err != nil {
+		return nil, err
+	}
+	defer attribute.Free()
+
+	if c.datatype, err = attribute.Type(); err != nil {
+		return nil, err
+	}
+	if c.cellValNum, err = attribute.CellValNum(); err != nil {
+		return nil, err
+	}
+	if c.nullable, err = attribute.Nullable(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// check returns an error if the cells of c cannot be represented in CSV.
+func (c *column) check() error {
+	if isText(c.datatype) {
+		return nil
+	}
+	if c.cellValNum != 1 {
+		return fmt.Errorf("%s has %s cells with cell_val_num %d, only single values and text can be represented in CSV",
+			c.name, c.datatype, c.cellValNum)
+	}
+	switch c.datatype.ReflectKind() {
+	case reflect.Bool, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+		return nil
+	default:
+		return fmt.Errorf("%s has datatype %s which cannot be represented in CSV", c.name, c.datatype)
+	}
+}
+
+// isVar returns whether the cells of c are var-sized.
+func (c *column) isVar() bool {
+	return c.cellValNum == tiledb.TILEDB_VAR_NUM
+}
+
+// isText returns whether the cells of datatype are text.
+func isText(datatype tiledb.Datatype) bool {
+	switch datatype {
+	case tiledb.TILEDB_STRING_ASCII, tiledb.TILEDB_STRING_UTF8, tiledb.TILEDB_CHAR:
+		return true
+	default:
+		return false
+	}
+}
+
+// isDatetime returns whether datatype is a datetime, which is represented as an RFC 3339 timestamp.
+func isDatetime(datatype tiledb.Datatype) bool {
+	switch datatype {
+	case tiledb.TILEDB_DATETIME_YEAR, tiledb.TILEDB_DATETIME_MONTH, tiledb.TILEDB_DATETIME_WEEK, tiledb.TILEDB_DATETIME_DAY,
+		tiledb.TILEDB_DATETIME_HR, tiledb.TILEDB_DATETIME_MIN, tiledb.TILEDB_DATETIME_SEC, tiledb.TILEDB_DATETIME_MS,
+		tiledb.TILEDB_DATETIME_US, tiledb.TILEDB_DATETIME_NS, tiledb.TILEDB_DATETIME_PS, tiledb.TILEDB_DATETIME_FS,
+		tiledb.TILEDB_DATETIME_AS:
+		return true
+	default:
+		return false
+	}
+}
+
+// parseValue parses the single value s of datatype, which is not text.
+func parseValue(datatype tiledb.Datatype, s string) (reflect.Value, error) {
+	typ := datatype.ReflectType()
+	switch typ.Kind() {
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		return reflect.ValueOf(b), err
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s, 10, typ.Bits())
+		if err != nil && isDatetime(datatype) {
+			t, timeErr := time.Parse(time.RFC3339Nano, s)
+			if timeErr != nil {
+				return reflect.Value{}, fmt.Errorf("%q is neither an integer nor an RFC 3339 timestamp", s)
+			}
+			i, err = tiledb.GetTimestampFromTime(datatype, t)
+		}
+		return reflect.ValueOf(i).Convert(typ), err
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(s, 10, typ.Bits())
+		return reflect.ValueOf(u).Convert(typ), err
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, typ.Bits())
+		return reflect.ValueOf(f).Convert(typ), err
+	default:
+		return reflect.Value{}, fmt.Errorf("cannot parse %s values", datatype)
+	}
+}
+
+// formatValue formats the single value v of datatype, which is not text.
+func formatValue(datatype tiledb.Datatype, v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if isDatetime(datatype) {
+			return tiledb.GetTimeFromTimestamp(datatype, v.Int()).Format(time.RFC3339Nano)
+		}
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10)
+	default:
+		return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits())
+	}
+}
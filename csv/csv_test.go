@@ -0,0 +1,299 @@
+package csv
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tiledb "github.com/TileDB-Inc/TileDB-Go"
+)
+
+const sparseCSV = `city,population,area,capital,country
+Lyon,522250,47.87,false,FR
+Berlin,3850809,891.1,true,
+Paris,2102650,105.4,true,FR
+Bonn,335789,141.1,false,DE
+`
+
+func TestImportExportSparse(t *testing.T) {
+	tdbCtx, err := tiledb.NewContext(nil)
+	require.NoError(t, err)
+
+	csvPath := filepath.Join(t.TempDir(), "cities.csv")
+	require.NoError(t, os.WriteFile(csvPath, []byte(sparseCSV), 0o644))
+	arrayPath := t.TempDir()
+	require.NoError(t, ImportFile(tdbCtx, arrayPath, csvPath, nil, ImportOptions{
+		BatchSize: 3,
+		Infer:     InferOptions{ArrayType: tiledb.TILEDB_SPARSE},
+	}))
+
+	array, err := tiledb.NewArray(tdbCtx, arrayPath)
+	require.NoError(t, err)
+	require.NoError(t, array.Open(tiledb.TILEDB_READ))
+	t.Cleanup(func() { require.NoError(t, array.Close()) })
+
+	var out strings.Builder
+	require.NoError(t, Export(tdbCtx, &out, array, nil, ExportOptions{NullValue: "NA"}))
+	assert.Equal(t, `city,population,area,capital,country
+Berlin,3850809,891.1,true,NA
+Bonn,335789,141.1,false,DE
+Lyon,522250,47.87,false,FR
+Paris,2102650,105.4,true,FR
+`, out.String())
+
+	t.Run("Subarray", func(t *testing.T) {
+		subarray, err := array.NewSubarray()
+		require.NoError(t, err)
+		defer subarray.Free()
+		require.NoError(t, subarray.AddRangeByName("city", tiledb.MakeRange("C", "M")))
+
+		var out strings.Builder
+		require.NoError(t, Export(tdbCtx, &out, array, subarray, ExportOptions{
+			Comma:    ';',
+			Fields:   []string{"country", "city"},
+			NoHeader: true,
+		}))
+		assert.Equal(t, "DE;Bonn\nFR;Lyon\n", out.String())
+	})
+}
+
+func TestImportExportDense(t *testing.T) {
+	tdbCtx, err := tiledb.NewContext(nil)
+	require.NoError(t, err)
+
+	schema := denseTestSchema(t, tdbCtx)
+	arrayPath := t.TempDir()
+	require.NoError(t, tiledb.CreateArray(tdbCtx, arrayPath, schema))
+	array, err := tiledb.NewArray(tdbCtx, arrayPath)
+	require.NoError(t, err)
+
+	// Each batch is a row of the array
+	input := `label,col,row,value,day
+a,1,1,1.5,1970-01-02T00:00:00Z
+b,2,1,2,1970-01-03T00:00:00Z
+,3,1,-3.25,3
+d,1,2,4,4
+e,2,2,5,5
+f,3,2,6,6
+`
+	require.NoError(t, array.Open(tiledb.TILEDB_WRITE))
+	require.NoError(t, Import(tdbCtx, array, strings.NewReader(input), ImportOptions{BatchSize: 3}))
+	require.NoError(t, array.Close())
+
+	require.NoError(t, array.Open(tiledb.TILEDB_READ))
+	var out strings.Builder
+	require.NoError(t, Export(tdbCtx, &out, array, nil, ExportOptions{}))
+	require.NoError(t, array.Close())
+	assert.Equal(t, `row,col,value,label,day
+1,1,1.5,a,1970-01-02T00:00:00Z
+1,2,2,b,1970-01-03T00:00:00Z
+1,3,-3.25,,1970-01-04T00:00:00Z
+2,1,4,d,1970-01-05T00:00:00Z
+2,2,5,e,1970-01-06T00:00:00Z
+2,3,6,f,1970-01-07T00:00:00Z
+`, out.String())
+
+	t.Run("Errors", func(t *testing.T) {
+		require.NoError(t, array.Open(tiledb.TILEDB_WRITE))
+		t.Cleanup(func() { require.NoError(t, array.Close()) })
+
+		for name, input := range map[string]string{
+			"Order":    "row,col,value,label,day\n1,2,1,a,1\n1,1,1,b,1\n",
+			"Gap":      "row,col,value,label,day\n1,1,1,a,1\n1,3,1,b,1\n",
+			"Value":    "row,col,value,label,day\n1,1,x,a,1\n",
+			"Missing":  "row,col,value,label\n1,1,1,a\n",
+			"Unknown":  "row,col,value,label,day,other\n1,1,1,a,1,1\n",
+			"Datetime": "row,col,value,label,day\n1,1,1,a,yesterday\n",
+		} {
+			t.Run(name, func(t *testing.T) {
+				require.Error(t, Import(tdbCtx, array, strings.NewReader(input), ImportOptions{}))
+			})
+		}
+	})
+}
+
+func TestImportDenseBatches(t *testing.T) {
+	tdbCtx, err := tiledb.NewContext(nil)
+	require.NoError(t, err)
+
+	t.Run("DefaultBatchSize", func(t *testing.T) {
+		// DefaultBatchSize is not a multiple of the number of columns.
+		const numRows, numCols = 150, 101
+
+		domain, err := tiledb.NewDomain(tdbCtx)
+		require.NoError(t, err)
+		row, err := tiledb.NewDimension(tdbCtx, "row", tiledb.TILEDB_INT32, []int32{1, numRows}, int32(10))
+		require.NoError(t, err)
+		col, err := tiledb.NewDimension(tdbCtx, "col", tiledb.TILEDB_INT32, []int32{1, numCols}, int32(numCols))
+		require.NoError(t, err)
+		require.NoError(t, domain.AddDimensions(row, col))
+		schema, err := tiledb.NewArraySchema(tdbCtx, tiledb.TILEDB_DENSE)
+		require.NoError(t, err)
+		require.NoError(t, schema.SetDomain(domain))
+		value, err := tiledb.NewAttribute(tdbCtx, "value", tiledb.TILEDB_INT64)
+		require.NoError(t, err)
+		require.NoError(t, schema.AddAttributes(value))
+
+		arrayPath := t.TempDir()
+		require.NoError(t, tiledb.CreateArray(tdbCtx, arrayPath, schema))
+		array, err := tiledb.NewArray(tdbCtx, arrayPath)
+		require.NoError(t, err)
+
+		var input strings.Builder
+		input.WriteString("row,col,value\n")
+		for r := 1; r <= numRows; r++ {
+			for c := 1; c <= numCols; c++ {
+				fmt.Fprintf(&input, "%d,%d,%d\n", r, c, r*1000+c)
+			}
+		}
+		require.Greater(t, numRows*numCols, DefaultBatchSize)
+
+		require.NoError(t, array.Open(tiledb.TILEDB_WRITE))
+		require.NoError(t, Import(tdbCtx, array, strings.NewReader(input.String()), ImportOptions{}))
+		require.NoError(t, array.Close())
+
+		require.NoError(t, array.Open(tiledb.TILEDB_READ))
+		var out strings.Builder
+		require.NoError(t, Export(tdbCtx, &out, array, nil, ExportOptions{}))
+		require.NoError(t, array.Close())
+		assert.Equal(t, input.String(), out.String())
+	})
+
+	t.Run("ColMajor", func(t *testing.T) {
+		arrayPath := t.TempDir()
+		require.NoError(t, tiledb.CreateArray(tdbCtx, arrayPath, denseTestSchema(t, tdbCtx)))
+		array, err := tiledb.NewArray(tdbCtx, arrayPath)
+		require.NoError(t, err)
+
+		// The batches of 4 records end after the first and the second column, and carry var-sized labels over.
+		input := `row,col,value,label,day
+1,1,1,a,1
+2,1,2,,2
+1,2,3,ccc,3
+2,2,4,d,4
+1,3,5,,5
+2,3,6,ff,6
+`
+		require.NoError(t, array.Open(tiledb.TILEDB_WRITE))
+		require.NoError(t, Import(tdbCtx, array, strings.NewReader(input), ImportOptions{BatchSize: 4, Layout: tiledb.TILEDB_COL_MAJOR}))
+		require.NoError(t, array.Close())
+
+		require.NoError(t, array.Open(tiledb.TILEDB_READ))
+		var out strings.Builder
+		require.NoError(t, Export(tdbCtx, &out, array, nil, ExportOptions{}))
+		require.NoError(t, array.Close())
+		assert.Equal(t, `row,col,value,label,day
+1,1,1,a,1970-01-02T00:00:00Z
+1,2,3,ccc,1970-01-04T00:00:00Z
+1,3,5,,1970-01-06T00:00:00Z
+2,1,2,,1970-01-03T00:00:00Z
+2,2,4,d,1970-01-05T00:00:00Z
+2,3,6,ff,1970-01-07T00:00:00Z
+`, out.String())
+	})
+}
+
+func TestInferSchema(t *testing.T) {
+	tdbCtx, err := tiledb.NewContext(nil)
+	require.NoError(t, err)
+
+	input := "x,y,count,ratio,flag,name\n3,1,1,0.5,true,a\n5,2,,1,false,\n4,3,3,2,true,c\n"
+	schema, err := InferSchema(tdbCtx, strings.NewReader(input), InferOptions{
+		Dimensions: []string{"y", "x"},
+		TileExtent: 2,
+	})
+	require.NoError(t, err)
+	defer schema.Free()
+
+	arrayType, err := schema.Type()
+	require.NoError(t, err)
+	assert.Equal(t, tiledb.TILEDB_DENSE, arrayType)
+
+	domain, err := schema.Domain()
+	require.NoError(t, err)
+	defer domain.Free()
+	for i, expected := range []struct {
+		name   string
+		domain []int64
+		extent int64
+	}{
+		{"y", []int64{1, 3}, 2},
+		{"x", []int64{3, 5}, 2},
+	} {
+		dimension, err := domain.DimensionFromIndex(uint(i))
+		require.NoError(t, err)
+		name, err := dimension.Name()
+		require.NoError(t, err)
+		assert.Equal(t, expected.name, name)
+		dimDomain, err := dimension.Domain()
+		require.NoError(t, err)
+		assert.Equal(t, expected.domain, dimDomain)
+		extent, err := dimension.Extent()
+		require.NoError(t, err)
+		assert.Equal(t, expected.extent, extent)
+		dimension.Free()
+	}
+
+	for _, expected := range []struct {
+		name     string
+		datatype tiledb.Datatype
+		isVar    bool
+		nullable bool
+	}{
+		{"count", tiledb.TILEDB_INT64, false, true},
+		{"ratio", tiledb.TILEDB_FLOAT64, false, false},
+		{"flag", tiledb.TILEDB_BOOL, false, false},
+		{"name", tiledb.TILEDB_STRING_UTF8, true, true},
+	} {
+		attribute, err := schema.AttributeFromName(expected.name)
+		require.NoError(t, err)
+		datatype, err := attribute.Type()
+		require.NoError(t, err)
+		assert.Equal(t, expected.datatype, datatype, expected.name)
+		cellValNum, err := attribute.CellValNum()
+		require.NoError(t, err)
+		assert.Equal(t, expected.isVar, cellValNum == tiledb.TILEDB_VAR_NUM, expected.name)
+		nullable, err := attribute.Nullable()
+		require.NoError(t, err)
+		assert.Equal(t, expected.nullable, nullable, expected.name)
+		attribute.Free()
+	}
+
+	_, err = InferSchema(tdbCtx, strings.NewReader(input), InferOptions{Dimensions: []string{"ratio"}})
+	assert.Error(t, err)
+	_, err = InferSchema(tdbCtx, strings.NewReader(input), InferOptions{Dimensions: []string{"count"}})
+	assert.Error(t, err)
+}
+
+// denseTestSchema returns the schema of a 2x3 dense array with a nullable var-sized
+// string attribute and a datetime attribute.
+func denseTestSchema(t *testing.T, tdbCtx *tiledb.Context) *tiledb.ArraySchema {
+	domain, err := tiledb.NewDomain(tdbCtx)
+	require.NoError(t, err)
+	row, err := tiledb.NewDimension(tdbCtx, "row", tiledb.TILEDB_INT32, []int32{1, 2}, int32(2))
+	require.NoError(t, err)
+	col, err := tiledb.NewDimension(tdbCtx, "col", tiledb.TILEDB_INT32, []int32{1, 3}, int32(3))
+	require.NoError(t, err)
+	require.NoError(t, domain.AddDimensions(row, col))
+
+	schema, err := tiledb.NewArraySchema(tdbCtx, tiledb.TILEDB_DENSE)
+	require.NoError(t, err)
+	require.NoError(t, schema.SetDomain(domain))
+
+	value, err := tiledb.NewAttribute(tdbCtx, "value", tiledb.TILEDB_FLOAT32)
+	require.NoError(t, err)
+	label, err := tiledb.NewAttribute(tdbCtx, "label", tiledb.TILEDB_STRING_UTF8)
+	require.NoError(t, err)
+	require.NoError(t, label.SetCellValNum(tiledb.TILEDB_VAR_NUM))
+	require.NoError(t, label.SetNullable(true))
+	day, err := tiledb.NewAttribute(tdbCtx, "day", tiledb.TILEDB_DATETIME_DAY)
+	require.NoError(t, err)
+	require.NoError(t, schema.AddAttributes(value, label, day))
+
+	return schema
+}
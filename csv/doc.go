@@ -0,0 +1,27 @@
+/*
+Package csv imports CSV data into TileDB arrays and exports arrays to CSV.
+
+The first record of a CSV file is a header with the names of the dimensions and attributes of the array.
+Each following record is a cell of the array. Cell values are formatted as follows:
+  - integers, floats and booleans as parsed and formatted by the strconv package
+  - TILEDB_STRING_ASCII, TILEDB_STRING_UTF8 and TILEDB_CHAR as text, of any length when var-sized
+    and of exactly cell_val_num bytes otherwise
+  - datetimes as RFC 3339 timestamps, integer timestamps are also accepted on import
+  - times as integers
+
+Null cells of nullable attributes are written as ExportOptions.NullValue and read
+from ImportOptions.NullValue, the empty string by default. Other var-sized attributes
+and attributes with cell_val_num > 1 are not supported.
+
+InferSchema builds an ArraySchema from the header and the first records of a CSV file.
+Import writes the records to an existing array, and ImportFile creates the array first:
+
+	err := csv.ImportFile(tdbCtx, "data/cities", "cities.csv", nil, csv.ImportOptions{
+		Infer: csv.InferOptions{Dimensions: []string{"name"}},
+	})
+
+Export writes a subarray of an array to CSV:
+
+	err := csv.Export(tdbCtx, os.Stdout, array, subarray, csv.ExportOptions{})
+*/
+package csv
@@ -0,0 +1,221 @@
+package csv
+
+import (
+	encsv "encoding/csv"
+	"fmt"
+	"io"
+	"reflect"
+
+	tiledb "github.com/TileDB-Inc/TileDB-Go"
+)
+
+// ExportOptions configures Export.
+type ExportOptions struct {
+	// Comma is the field delimiter, ',' if zero.
+	Comma rune
+	// Fields are the dimensions and attributes to export, in column order. If empty, all of them are exported.
+	Fields []string
+	// NullValue is written for the null cells of nullable attributes.
+	NullValue string
+	// NoHeader disables writing the header.
+	NoHeader bool
+	// Layout is the order of the records: TILEDB_ROW_MAJOR, the zero value, TILEDB_COL_MAJOR,
+	// TILEDB_GLOBAL_ORDER or, for sparse arrays, TILEDB_UNORDERED.
+	Layout tiledb.Layout
+	// MemoryCap is the maximum total size in bytes of the query buffers, see tiledb.BatchOptions.
+	MemoryCap uint64
+}
+
+/*
+Export writes the cells of the subarray of the array, which must be open for reading, to w as CSV records
+with a column per dimension and attribute. If subarray is nil the whole array is exported: the non-empty
+domain for dense arrays. The array is read in batches with Query.Batches.
+
+Example:
+
+	subarray, err := array.NewSubarray()
+	...
+	err = subarray.SetSubArray([]int32{1, 100})
+	...
+	err = csv.Export(tdbCtx, os.Stdout, array, subarray, csv.ExportOptions{Fields: []string{"id", "name"}})
+*/
+func Export(tdbCtx *tiledb.Context, w io.Writer, array *tiledb.Array, subarray *tiledb.Subarray, opts ExportOptions) error {
+	columns, err := arrayColumns(array, opts.Fields)
+	if err != nil {
+		return fmt.Errorf("could not map array to CSV for Export: %w", err)
+	}
+
+	writer := newWriter(w, opts.Comma)
+	if !opts.NoHeader {
+		header := make([]string, len(columns))
+		for i, c := range columns {
+			header[i] = c.name
+		}
+		if err := writer.Write(header); err != nil {
+			return err
+		}
+	}
+
+	if subarray == nil {
+		var empty bool
+		subarray, empty, err = nonEmptySubarray(array)
+		if err != nil {
+			return err
+		}
+		if empty {
+			writer.Flush()
+			return writer.Error()
+		}
+		if subarray != nil {
+			defer subarray.Free()
+		}
+	}
+
+	query, err := tiledb.NewQuery(tdbCtx, array)
+	if err != nil {
+		return err
+	}
+	defer query.Free()
+
+	if err := query.SetLayout(opts.Layout); err != nil {
+		return err
+	}
+	if subarray != nil {
+		if err := query.SetSubarray(subarray); err != nil {
+			return err
+		}
+	}
+
+	names := make([]string, len(columns))
+	for i, c := range columns {
+		names[i] = c.name
+	}
+
+	record := make([]string, len(columns))
+	query.Batches(tiledb.BatchOptions{Fields: names, MemoryCap: opts.MemoryCap})(func(batch *tiledb.QueryBatch, batchErr error) bool {
+		if batchErr != nil {
+			err = batchErr
+			return false
+		}
+		err = writeRecords(writer, columns, batch, record, opts.NullValue)
+		return err == nil
+	})
+	if err != nil {
+		return err
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// newWriter returns a CSV writer to w with the delimiter comma, if not zero.
+func newWriter(w io.Writer, comma rune) *encsv.Writer {
+	writer := encsv.NewWriter(w)
+	if comma != 0 {
+		writer.Comma = comma
+	}
+	return writer
+}
+
+// nonEmptySubarray returns the subarray of the non-empty domain of a dense array, or nil
+// for a sparse array. empty is true if the dense array has no data.
+func nonEmptySubarray(array *tiledb.Array) (subarray *tiledb.Subarray, empty bool, err error) {
+	schema, err := array.Schema()
+	if err != nil {
+		return nil, false, err
+	}
+	arrayType, err := schema.Type()
+	schema.Free()
+	if err != nil || arrayType == tiledb.TILEDB_SPARSE {
+		return nil, false, err
+	}
+
+	domains, empty, err := array.NonEmptyDomain()
+	if err != nil || empty {
+		return nil, empty, err
+	}
+
+	// The dimensions of dense arrays all have the same datatype.
+	bounds := reflect.ValueOf(domains[0].Bounds)
+	for _, domain := range domains[1:] {
+		bounds = reflect.AppendSlice(bounds, reflect.ValueOf(domain.Bounds))
+	}
+
+	subarray, err = array.NewSubarray()
+	if err != nil {
+		return nil, false, err
+	}
+	if err := subarray.SetSubArray(bounds.Interface()); err != nil {
+		subarray.Free()
+		return nil, false, err
+	}
+	return subarray, false, nil
+}
+
+// batchColumn holds the buffers of a column in a batch.
+type batchColumn struct {
+	*column
+	data     reflect.Value
+	text     []byte
+	offsets  []uint64
+	validity []uint8
+}
+
+// writeRecords writes the cells of batch as CSV records, using record as buffer.
+func writeRecords(writer *encsv.Writer, columns []*column, batch *tiledb.QueryBatch, record []string, nullValue string) error {
+	batchColumns := make([]batchColumn, len(columns))
+	for i, c := range columns {
+		b := batchColumn{column: c}
+		data, err := batch.Data(c.name)
+		if err != nil {
+			return err
+		}
+		if isText(c.datatype) {
+			b.text = data.([]byte)
+		} else {
+			b.data = reflect.ValueOf(data)
+		}
+		if c.isVar() {
+			if b.offsets, err = batch.Offsets(c.name); err != nil {
+				return err
+			}
+		}
+		if c.nullable {
+			if b.validity, err = batch.Validity(c.name); err != nil {
+				return err
+			}
+		}
+		batchColumns[i] = b
+	}
+
+	for cell := 0; cell < int(batch.NumCells); cell++ {
+		for i := range batchColumns {
+			record[i] = batchColumns[i].format(cell, nullValue)
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// format formats the cell of b.
+func (b *batchColumn) format(cell int, nullValue string) string {
+	if b.nullable && b.validity[cell] == 0 {
+		return nullValue
+	}
+
+	switch {
+	case b.isVar():
+		end := len(b.text)
+		if cell+1 < len(b.offsets) {
+			end = int(b.offsets[cell+1])
+		}
+		return string(b.text[b.offsets[cell]:end])
+	case isText(b.datatype):
+		n := int(b.cellValNum)
+		return string(b.text[cell*n : (cell+1)*n])
+	default:
+		return formatValue(b.datatype, b.data.Index(cell))
+	}
+}
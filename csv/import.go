@@ -0,0 +1,442 @@
+package csv
+
+import (
+	encsv "encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"slices"
+
+	tiledb "github.com/TileDB-Inc/TileDB-Go"
+)
+
+// DefaultBatchSize is the number of records written per query when ImportOptions.BatchSize is zero.
+const DefaultBatchSize = 10000
+
+// ImportOptions configures Import and ImportFile.
+type ImportOptions struct {
+	// Comma is the field delimiter, ',' if zero.
+	Comma rune
+	// NullValue is the value of the null cells of nullable attributes.
+	NullValue string
+	// BatchSize is the maximum number of records written per query. If zero, DefaultBatchSize is used.
+	BatchSize int
+	// Layout is the order of the records of dense arrays: TILEDB_ROW_MAJOR, the zero value, or TILEDB_COL_MAJOR.
+	// The records must fill the bounding box of their coordinates in that order.
+	// Sparse arrays are written with TILEDB_UNORDERED layout.
+	Layout tiledb.Layout
+	// Infer configures the schema inference of ImportFile when no schema is given.
+	// Its Comma and NullValue are ignored in favor of those above.
+	Infer InferOptions
+}
+
+// importColumn is a column of the array and its buffers for the current batch.
+type importColumn struct {
+	*column
+	index int // index of the column in the records
+
+	data     reflect.Value // slice of datatype.ReflectType() for non-text columns
+	text     []byte        // data of text columns
+	offsets  []uint64
+	validity []uint8
+}
+
+/*
+Import writes the CSV records of r to the array, which must be open for writing.
+
+The header of r must name every dimension and attribute of the array, in any order. The records
+are written in batches of at most ImportOptions.BatchSize records, one query per batch, so an error can
+happen after some batches were written. For dense arrays a batch ends on a boundary of the rows of the
+records (of their planes, etc. in more dimensions), so that each batch fills a subarray. A record with the wrong number of fields is an error.
+
+Example:
+
+	err := array.Open(tiledb.TILEDB_WRITE)
+	...
+	err = csv.Import(tdbCtx, array, file, csv.ImportOptions{NullValue: "NA"})
+*/
+func Import(tdbCtx *tiledb.Context, array *tiledb.Array, r io.Reader, opts ImportOptions) error {
+	reader := newReader(r, opts.Comma)
+	header, err := reader.Read()
+	if err != nil {
+		return fmt.Errorf("could not read CSV header: %w", err)
+	}
+
+	schema, err := array.Schema()
+	if err != nil {
+		return fmt.Errorf("could not get array schema for Import: %w", err)
+	}
+	arrayType, err := schema.Type()
+	schema.Free()
+	if err != nil {
+		return fmt.Errorf("could not get array type for Import: %w", err)
+	}
+
+	arrayColumns, err := arrayColumns(array, nil)
+	if err != nil {
+		return fmt.Errorf("could not map array to CSV for Import: %w", err)
+	}
+	columns, err := headerColumns(header, arrayColumns)
+	if err != nil {
+		return err
+	}
+
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+
+	var numRows int
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		line, _ := reader.FieldPos(0)
+		for _, c := range columns {
+			if err := c.append(record[c.index], opts.NullValue); err != nil {
+				return fmt.Errorf("line %d: %s: %w", line, c.name, err)
+			}
+		}
+
+		numRows++
+		if numRows == batchSize {
+			batchRows := numRows
+			if arrayType == tiledb.TILEDB_DENSE {
+				batchRows = denseBatchEnd(columns, numRows, opts.Layout)
+			}
+			if err := writeBatch(tdbCtx, array, arrayType, columns, batchRows, opts.Layout); err != nil {
+				return err
+			}
+			numRows -= batchRows
+		}
+	}
+
+	if numRows == 0 {
+		return nil
+	}
+	return writeBatch(tdbCtx, array, arrayType, columns, numRows, opts.Layout)
+}
+
+/*
+denseBatchEnd returns the number of the numRows records buffered in columns to write in a batch of a
+dense import, the rest being kept for the next batch. The batch ends where the coordinate of the slowest
+varying dimension that changes in the batch last changes: in row-major order in 2-D, at the last row boundary.
+The batches then start and end on boundaries of the rows, planes, etc. of the records, so their records fill
+the bounding boxes of their coordinates whenever all the imported records fill theirs. If only the fastest
+varying coordinate changes, the batch is part of a single row and is written whole.
+*/
+func denseBatchEnd(columns []*importColumn, numRows int, layout tiledb.Layout) int {
+	var dims []*importColumn
+	for _, c := range columns {
+		if c.dimension {
+			dims = append(dims, c)
+		}
+	}
+	if layout == tiledb.TILEDB_COL_MAJOR {
+		slices.Reverse(dims)
+	}
+
+	for _, c := range dims[:len(dims)-1] {
+		for i := numRows - 1; i > 0; i-- {
+			if !c.data.Index(i).Equal(c.data.Index(i - 1)) {
+				return i
+			}
+		}
+	}
+	return numRows
+}
+
+/*
+ImportFile creates the array at uri and imports the CSV file at path into it with Import.
+If schema is nil it is inferred from the file with InferSchema and opts.Infer.
+
+Example:
+
+	err := csv.ImportFile(tdbCtx, "data/trips", "trips.csv", nil, csv.ImportOptions{
+		Infer: csv.InferOptions{ArrayType: tiledb.TILEDB_SPARSE, Dimensions: []string{"vendor", "pickup"}},
+	})
+*/
+func ImportFile(tdbCtx *tiledb.Context, uri, path string, schema *tiledb.ArraySchema, opts ImportOptions) error {
+	if schema == nil {
+		inferOpts := opts.Infer
+		inferOpts.Comma, inferOpts.NullValue = opts.Comma, opts.NullValue
+
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		schema, err = InferSchema(tdbCtx, f, inferOpts)
+		f.Close()
+		if err != nil {
+			return err
+		}
+		defer schema.Free()
+	}
+
+	if err := tiledb.CreateArray(tdbCtx, uri, schema); err != nil {
+		return err
+	}
+
+	array, err := tiledb.NewArray(tdbCtx, uri)
+	if err != nil {
+		return err
+	}
+	defer array.Free()
+	if err := array.Open(tiledb.TILEDB_WRITE); err != nil {
+		return err
+	}
+	defer array.Close()
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return Import(tdbCtx, array, f, opts)
+}
+
+// newReader returns a CSV reader of r with the delimiter comma, if not zero.
+func newReader(r io.Reader, comma rune) *encsv.Reader {
+	reader := encsv.NewReader(r)
+	if comma != 0 {
+		reader.Comma = comma
+	}
+	reader.ReuseRecord = true
+	return reader
+}
+
+// headerColumns maps the columns of the array to the fields of the header.
+func headerColumns(header []string, columns []*column) ([]*importColumn, error) {
+	indexes := make(map[string]int, len(header))
+	for i, name := range header {
+		if _, ok := indexes[name]; ok {
+			return nil, fmt.Errorf("CSV header has duplicate column %s", name)
+		}
+		if !hasColumn(columns, name) {
+			return nil, fmt.Errorf("CSV column %s is not a dimension or attribute of the array", name)
+		}
+		indexes[name] = i
+	}
+
+	imported := make([]*importColumn, len(columns))
+	for i, c := range columns {
+		index, ok := indexes[c.name]
+		if !ok {
+			return nil, fmt.Errorf("CSV header has no column %s", c.name)
+		}
+		imported[i] = &importColumn{column: c, index: index}
+		if !isText(c.datatype) {
+			imported[i].data = reflect.MakeSlice(reflect.SliceOf(c.datatype.ReflectType()), 0, 0)
+		}
+	}
+
+	return imported, nil
+}
+
+func hasColumn(columns []*column, name string) bool {
+	for _, c := range columns {
+		if c.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// append appends the cell of the field s to the buffers of c.
+func (c *importColumn) append(s string, nullValue string) error {
+	valid := !c.nullable || s != nullValue
+	if c.nullable {
+		var v uint8
+		if valid {
+			v = 1
+		}
+		c.validity = append(c.validity, v)
+	}
+
+	switch {
+	case c.isVar():
+		c.offsets = append(c.offsets, uint64(len(c.text)))
+		if valid {
+			c.text = append(c.text, s...)
+		}
+	case isText(c.datatype):
+		if !valid {
+			c.text = append(c.text, make([]byte, c.cellValNum)...)
+		} else if len(s) != int(c.cellValNum) {
+			return fmt.Errorf("%q does not have %d bytes", s, c.cellValNum)
+		} else {
+			c.text = append(c.text, s...)
+		}
+	case !valid:
+		c.data = reflect.Append(c.data, reflect.Zero(c.data.Type().Elem()))
+	default:
+		v, err := parseValue(c.datatype, s)
+		if err != nil {
+			return err
+		}
+		c.data = reflect.Append(c.data, v)
+	}
+
+	return nil
+}
+
+// textEnd returns the end of the first n records of c in c.text.
+func (c *importColumn) textEnd(n int) int {
+	switch {
+	case !c.isVar():
+		return n * int(c.cellValNum)
+	case n < len(c.offsets):
+		return int(c.offsets[n])
+	default:
+		return len(c.text)
+	}
+}
+
+// head returns a column with the buffers of the first n records of c.
+func (c *importColumn) head(n int) *importColumn {
+	h := *c
+	if c.data.IsValid() {
+		h.data = c.data.Slice(0, n)
+	}
+	if isText(c.datatype) {
+		h.text = c.text[:c.textEnd(n)]
+	}
+	if c.isVar() {
+		h.offsets = c.offsets[:n]
+	}
+	if c.nullable {
+		h.validity = c.validity[:n]
+	}
+	return &h
+}
+
+// drop removes the first n records from the buffers of c, keeping their memory.
+func (c *importColumn) drop(n int) {
+	if c.data.IsValid() {
+		rest := c.data.Len() - n
+		reflect.Copy(c.data, c.data.Slice(n, c.data.Len()))
+		c.data = c.data.Slice(0, rest)
+	}
+	textEnd := 0
+	if isText(c.datatype) {
+		textEnd = c.textEnd(n)
+		c.text = c.text[:copy(c.text, c.text[textEnd:])]
+	}
+	if c.isVar() {
+		c.offsets = c.offsets[:copy(c.offsets, c.offsets[n:])]
+		for i := range c.offsets {
+			c.offsets[i] -= uint64(textEnd)
+		}
+	}
+	if c.nullable {
+		c.validity = c.validity[:copy(c.validity, c.validity[n:])]
+	}
+}
+
+// set sets the buffers of c on the query.
+func (c *importColumn) set(query *tiledb.Query) error {
+	if c.isVar() {
+		if _, err := query.SetOffsetsBuffer(c.name, c.offsets); err != nil {
+			return err
+		}
+	}
+	if c.nullable {
+		if _, err := query.SetValidityBuffer(c.name, c.validity); err != nil {
+			return err
+		}
+	}
+
+	if !isText(c.datatype) {
+		_, err := query.SetDataBuffer(c.name, c.data.Interface())
+		return err
+	}
+	if len(c.text) == 0 {
+		// The var-sized cells can all be empty, in which case the core still needs a non-nil buffer.
+		_, ptr, err := c.datatype.MakeSlice(1)
+		if err != nil {
+			return err
+		}
+		_, err = query.SetDataBufferUnsafe(c.name, ptr, 0)
+		return err
+	}
+	_, err := query.SetDataBuffer(c.name, c.text)
+	return err
+}
+
+// writeBatch writes the first numRows records buffered in columns with one query, and removes them from the buffers.
+func writeBatch(tdbCtx *tiledb.Context, array *tiledb.Array, arrayType tiledb.ArrayType, columns []*importColumn, numRows int, layout tiledb.Layout) error {
+	query, err := tiledb.NewQuery(tdbCtx, array)
+	if err != nil {
+		return err
+	}
+	defer query.Free()
+
+	if arrayType == tiledb.TILEDB_SPARSE {
+		layout = tiledb.TILEDB_UNORDERED
+	}
+	if err := query.SetLayout(layout); err != nil {
+		return err
+	}
+
+	if arrayType == tiledb.TILEDB_DENSE {
+		subarray, err := denseSubarray(array, columns, numRows, layout)
+		if err != nil {
+			return err
+		}
+		defer subarray.Free()
+		if err := query.SetSubarray(subarray); err != nil {
+			return err
+		}
+	}
+
+	for _, c := range columns {
+		if arrayType == tiledb.TILEDB_DENSE && c.dimension {
+			continue
+		}
+		if err := c.head(numRows).set(query); err != nil {
+			return fmt.Errorf("could not set buffers of %s for Import: %w", c.name, err)
+		}
+	}
+
+	if err := query.Submit(); err != nil {
+		return err
+	}
+	if err := query.Finalize(); err != nil {
+		return err
+	}
+
+	for _, c := range columns {
+		c.drop(numRows)
+	}
+	return nil
+}
+
+// denseSubarray returns the subarray of a dense write of the numRows records buffered in columns,
+// computed by tiledb.NewDenseWriteSubarray from the coordinates of the dimensions.
+func denseSubarray(array *tiledb.Array, columns []*importColumn, numRows int, layout tiledb.Layout) (*tiledb.Subarray, error) {
+	var coords [][]int64
+	for _, c := range columns {
+		if !c.dimension {
+			continue
+		}
+		dimCoords := make([]int64, numRows)
+		for i := range dimCoords {
+			coord, err := tiledb.DenseCoordinate(c.data.Index(i).Interface())
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", c.name, err)
+			}
+			dimCoords[i] = coord
+		}
+		coords = append(coords, dimCoords)
+	}
+
+	return tiledb.NewDenseWriteSubarray(array, coords, layout)
+}
@@ -0,0 +1,277 @@
+package csv
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+
+	tiledb "github.com/TileDB-Inc/TileDB-Go"
+)
+
+// DefaultSampleRows is the number of records InferSchema reads when InferOptions.SampleRows is zero.
+const DefaultSampleRows = 1000
+
+// DefaultTileExtent is the tile extent of the inferred dimensions when InferOptions.TileExtent is zero.
+const DefaultTileExtent = 10000
+
+// InferOptions configures InferSchema.
+type InferOptions struct {
+	// Comma is the field delimiter, ',' if zero.
+	Comma rune
+	// NullValue is the value of null cells. Columns with null cells are inferred as nullable attributes.
+	NullValue string
+	// ArrayType is the type of the array, TILEDB_DENSE by default.
+	ArrayType tiledb.ArrayType
+	// Dimensions are the names of the columns that are dimensions, the first column if empty.
+	// The other columns are attributes.
+	Dimensions []string
+	// SampleRows is the number of records read to infer the datatypes. If zero, DefaultSampleRows is used;
+	// if negative, all the records are read.
+	SampleRows int
+	// TileExtent is the tile extent of the integer dimensions. If zero, DefaultTileExtent is used,
+	// capped to the size of the domain of dense dimensions.
+	TileExtent int64
+	// AllowsDups sets whether the sparse array allows cells with the same coordinates.
+	AllowsDups bool
+}
+
+// columnSample accumulates what the sample records tell about the datatype of a column.
+type columnSample struct {
+	name     string
+	values   int  // number of non-null values
+	nulls    bool // whether the column has null values
+	notInt   bool
+	notFloat bool
+	notBool  bool
+	min, max int64 // bounds of the values if they are integers
+}
+
+func (s *columnSample) add(value string) {
+	s.values++
+	if !s.notInt {
+		i, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			s.notInt = true
+		} else {
+			if s.values == 1 || i < s.min {
+				s.min = i
+			}
+			if s.values == 1 || i > s.max {
+				s.max = i
+			}
+		}
+	}
+	if !s.notFloat {
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			s.notFloat = true
+		}
+	}
+	if !s.notBool {
+		if _, err := strconv.ParseBool(value); err != nil {
+			s.notBool = true
+		}
+	}
+}
+
+// datatype returns the datatype of the column: TILEDB_INT64, TILEDB_FLOAT64 or TILEDB_BOOL if all
+// its values parse as such, in that order, and TILEDB_STRING_UTF8 otherwise.
+func (s *columnSample) datatype() tiledb.Datatype {
+	switch {
+	case s.values == 0:
+		return tiledb.TILEDB_STRING_UTF8
+	case !s.notInt:
+		return tiledb.TILEDB_INT64
+	case !s.notFloat:
+		return tiledb.TILEDB_FLOAT64
+	case !s.notBool:
+		return tiledb.TILEDB_BOOL
+	default:
+		return tiledb.TILEDB_STRING_UTF8
+	}
+}
+
+/*
+InferSchema builds an array schema from the header and the first InferOptions.SampleRows records of r.
+
+The columns of InferOptions.Dimensions are the dimensions of the array, in that order, and the other
+columns its attributes. The datatype of each column is the first of TILEDB_INT64, TILEDB_FLOAT64
+and TILEDB_BOOL its sample values parse as, or var-sized TILEDB_STRING_UTF8.
+Attributes with null values in the sample are nullable.
+
+Dimensions cannot have null values. Integer dimensions of dense arrays have the domain of the sample
+values, so the sample must cover all the records, and integer dimensions of sparse arrays the whole
+int64 domain. String dimensions are only supported by sparse arrays.
+The schema has row-major cell and tile orders.
+*/
+func InferSchema(tdbCtx *tiledb.Context, r io.Reader, opts InferOptions) (*tiledb.ArraySchema, error) {
+	reader := newReader(r, opts.Comma)
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("could not read CSV header: %w", err)
+	}
+
+	samples := make([]columnSample, len(header))
+	for i, name := range header {
+		samples[i].name = name
+	}
+	sampleRows := opts.SampleRows
+	if sampleRows == 0 {
+		sampleRows = DefaultSampleRows
+	}
+	for n := 0; sampleRows < 0 || n < sampleRows; n++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		for i, value := range record {
+			if value == opts.NullValue {
+				samples[i].nulls = true
+			} else {
+				samples[i].add(value)
+			}
+		}
+	}
+
+	dimNames := opts.Dimensions
+	if len(dimNames) == 0 {
+		dimNames = header[:1]
+	}
+	dimSamples := make([]*columnSample, len(dimNames))
+	for d, name := range dimNames {
+		for i := range samples {
+			if samples[i].name == name {
+				dimSamples[d] = &samples[i]
+			}
+		}
+		if dimSamples[d] == nil {
+			return nil, fmt.Errorf("CSV header has no dimension column %s", name)
+		}
+	}
+
+	schema, err := tiledb.NewArraySchema(tdbCtx, opts.ArrayType)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := setInferredSchema(tdbCtx, schema, samples, dimSamples, opts); err != nil {
+		schema.Free()
+		return nil, err
+	}
+	return schema, nil
+}
+
+// setInferredSchema sets the domain, attributes and orders of schema from the samples of the columns.
+func setInferredSchema(tdbCtx *tiledb.Context, schema *tiledb.ArraySchema, samples []columnSample, dimSamples []*columnSample, opts InferOptions) error {
+	domain, err := tiledb.NewDomain(tdbCtx)
+	if err != nil {
+		return err
+	}
+	defer domain.Free()
+
+	for _, s := range dimSamples {
+		dimension, err := inferDimension(tdbCtx, opts.ArrayType, s, opts.TileExtent)
+		if err != nil {
+			return err
+		}
+		err = domain.AddDimensions(dimension)
+		dimension.Free()
+		if err != nil {
+			return err
+		}
+	}
+	if err := schema.SetDomain(domain); err != nil {
+		return err
+	}
+
+	for i := range samples {
+		s := &samples[i]
+		if isDimensionSample(dimSamples, s) {
+			continue
+		}
+		attribute, err := inferAttribute(tdbCtx, s)
+		if err != nil {
+			return err
+		}
+		err = schema.AddAttributes(attribute)
+		attribute.Free()
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := schema.SetCellOrder(tiledb.TILEDB_ROW_MAJOR); err != nil {
+		return err
+	}
+	if err := schema.SetTileOrder(tiledb.TILEDB_ROW_MAJOR); err != nil {
+		return err
+	}
+	if opts.ArrayType == tiledb.TILEDB_SPARSE && opts.AllowsDups {
+		if err := schema.SetAllowsDups(true); err != nil {
+			return err
+		}
+	}
+
+	return schema.Check()
+}
+
+func isDimensionSample(dimSamples []*columnSample, s *columnSample) bool {
+	for _, d := range dimSamples {
+		if d == s {
+			return true
+		}
+	}
+	return false
+}
+
+func inferDimension(tdbCtx *tiledb.Context, arrayType tiledb.ArrayType, s *columnSample, tileExtent int64) (*tiledb.Dimension, error) {
+	if s.nulls {
+		return nil, fmt.Errorf("dimension %s has null values", s.name)
+	}
+	if tileExtent <= 0 {
+		tileExtent = DefaultTileExtent
+	}
+
+	switch datatype := s.datatype(); {
+	case datatype == tiledb.TILEDB_INT64 && arrayType == tiledb.TILEDB_DENSE:
+		if s.values == 0 {
+			return nil, fmt.Errorf("dimension %s has no values to infer its domain from", s.name)
+		}
+		if size := s.max - s.min + 1; size > 0 && tileExtent > size {
+			tileExtent = size
+		}
+		return tiledb.NewDimension(tdbCtx, s.name, datatype, []int64{s.min, s.max}, tileExtent)
+	case datatype == tiledb.TILEDB_INT64:
+		return tiledb.NewDimension(tdbCtx, s.name, datatype, []int64{math.MinInt64, math.MaxInt64 - tileExtent}, tileExtent)
+	case datatype == tiledb.TILEDB_STRING_UTF8 && arrayType == tiledb.TILEDB_SPARSE:
+		return tiledb.NewStringDimension(tdbCtx, s.name)
+	default:
+		return nil, fmt.Errorf("cannot infer %s dimension %s of %s array, only integer and string dimensions are supported",
+			datatype, s.name, arrayType)
+	}
+}
+
+func inferAttribute(tdbCtx *tiledb.Context, s *columnSample) (*tiledb.Attribute, error) {
+	datatype := s.datatype()
+	attribute, err := tiledb.NewAttribute(tdbCtx, s.name, datatype)
+	if err != nil {
+		return nil, err
+	}
+	if datatype == tiledb.TILEDB_STRING_UTF8 {
+		if err := attribute.SetCellValNum(tiledb.TILEDB_VAR_NUM); err != nil {
+			attribute.Free()
+			return nil, err
+		}
+	}
+	if s.nulls {
+		if err := attribute.SetNullable(true); err != nil {
+			attribute.Free()
+			return nil, err
+		}
+	}
+	return attribute, nil
+}
@@ -0,0 +1,308 @@
+package tiledb
+
+/*
+#include <tiledb/tiledb.h>
+#include <tiledb/tiledb_experimental.h>
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+	"runtime"
+	"unsafe"
+)
+
+// CurrentDomainType is the kind of shape a CurrentDomain has.
+type CurrentDomainType uint8
+
+const (
+	// TILEDB_NDRECTANGLE is a current domain defined by an NDRectangle.
+	TILEDB_NDRECTANGLE CurrentDomainType = C.TILEDB_NDRECTANGLE
+)
+
+type currentDomainHandle struct{ *capiHandle }
+
+func freeCapiCurrentDomain(c unsafe.Pointer) {
+	C.tiledb_current_domain_free((**C.tiledb_current_domain_t)(unsafe.Pointer(&c)))
+}
+
+func newCurrentDomainHandle(ptr *C.tiledb_current_domain_t) currentDomainHandle {
+	return currentDomainHandle{newCapiHandle(unsafe.Pointer(ptr), freeCapiCurrentDomain)}
+}
+
+func (x currentDomainHandle) Get() *C.tiledb_current_domain_t {
+	return (*C.tiledb_current_domain_t)(x.capiHandle.Get())
+}
+
+// CurrentDomain is the part of the domain of an array that can currently be written and read.
+// It can be set at array creation to a subset of the domain and expanded later
+// with ArraySchemaEvolution.ExpandCurrentDomain, up to the full domain.
+type CurrentDomain struct {
+	tiledbCurrentDomain currentDomainHandle
+	context             *Context
+}
+
+func newCurrentDomainFromHandle(context *Context, handle currentDomainHandle) *CurrentDomain {
+	return &CurrentDomain{tiledbCurrentDomain: handle, context: context}
+}
+
+// NewCurrentDomain allocates an empty current domain.
+func NewCurrentDomain(tdbCtx *Context) (*CurrentDomain, error) {
+	var cdPtr *C.tiledb_current_domain_t
+	ret := C.tiledb_current_domain_create(tdbCtx.tiledbContext.Get(), &cdPtr)
+	runtime.KeepAlive(tdbCtx)
+	if ret != C.TILEDB_OK {
+		return nil, fmt.Errorf("error creating tiledb current domain: %w", tdbCtx.LastError())
+	}
+
+	return newCurrentDomainFromHandle(tdbCtx, newCurrentDomainHandle(cdPtr)), nil
+}
+
+// Free releases the internal TileDB core data that was allocated on the C heap.
+// It is automatically called when this object is garbage collected, but can be
+// called earlier to manually release memory if needed. Free is idempotent and
+// can safely be called many times on the same object; if it has already
+// been freed, it will not be freed again.
+func (cd *CurrentDomain) Free() {
+	cd.tiledbCurrentDomain.Free()
+}
+
+// Context exposes the internal TileDB context used to initialize the current domain.
+func (cd *CurrentDomain) Context() *Context {
+	return cd.context
+}
+
+// SetNDRectangle sets the shape of the current domain to the NDRectangle.
+func (cd *CurrentDomain) SetNDRectangle(ndr *NDRectangle) error {
+	ret := C.tiledb_current_domain_set_ndrectangle(cd.context.tiledbContext.Get(), cd.tiledbCurrentDomain.Get(), ndr.tiledbNDRectangle.Get())
+	runtime.KeepAlive(cd)
+	runtime.KeepAlive(ndr)
+	if ret != C.TILEDB_OK {
+		return fmt.Errorf("error setting ndrectangle to current domain: %w", cd.context.LastError())
+	}
+
+	return nil
+}
+
+// NDRectangle returns the NDRectangle of the current domain.
+func (cd *CurrentDomain) NDRectangle() (*NDRectangle, error) {
+	var ndrPtr *C.tiledb_ndrectangle_t
+	ret := C.tiledb_current_domain_get_ndrectangle(cd.context.tiledbContext.Get(), cd.tiledbCurrentDomain.Get(), &ndrPtr)
+	runtime.KeepAlive(cd)
+	if ret != C.TILEDB_OK {
+		return nil, fmt.Errorf("error getting ndrectangle from current domain: %w", cd.context.LastError())
+	}
+
+	return newNDRectangleFromHandle(cd.context, newNDRectangleHandle(ndrPtr)), nil
+}
+
+// IsEmpty returns whether the current domain is empty, i.e. no shape has been set.
+func (cd *CurrentDomain) IsEmpty() (bool, error) {
+	var isEmpty C.uint32_t
+	ret := C.tiledb_current_domain_get_is_empty(cd.context.tiledbContext.Get(), cd.tiledbCurrentDomain.Get(), &isEmpty)
+	runtime.KeepAlive(cd)
+	if ret != C.TILEDB_OK {
+		return false, fmt.Errorf("error checking if current domain is empty: %w", cd.context.LastError())
+	}
+
+	return isEmpty == 1, nil
+}
+
+// Type returns the type of the current domain.
+func (cd *CurrentDomain) Type() (CurrentDomainType, error) {
+	var cdType C.tiledb_current_domain_type_t
+	ret := C.tiledb_current_domain_get_type(cd.context.tiledbContext.Get(), cd.tiledbCurrentDomain.Get(), &cdType)
+	runtime.KeepAlive(cd)
+	if ret != C.TILEDB_OK {
+		return 0, fmt.Errorf("error getting current domain type: %w", cd.context.LastError())
+	}
+
+	return CurrentDomainType(cdType), nil
+}
+
+// SetCurrentDomain sets the current domain of the array schema.
+// It must be within the domain of the schema.
+func (a *ArraySchema) SetCurrentDomain(cd *CurrentDomain) error {
+	ret := C.tiledb_array_schema_set_current_domain(a.context.tiledbContext.Get(), a.tiledbArraySchema.Get(), cd.tiledbCurrentDomain.Get())
+	runtime.KeepAlive(a)
+	runtime.KeepAlive(cd)
+	if ret != C.TILEDB_OK {
+		return fmt.Errorf("error setting current domain for tiledb arraySchema: %w", a.context.LastError())
+	}
+
+	return nil
+}
+
+// CurrentDomain returns the current domain of the array schema.
+// If no current domain has been set, the returned CurrentDomain is empty.
+func (a *ArraySchema) CurrentDomain() (*CurrentDomain, error) {
+	var cdPtr *C.tiledb_current_domain_t
+	ret := C.tiledb_array_schema_get_current_domain(a.context.tiledbContext.Get(), a.tiledbArraySchema.Get(), &cdPtr)
+	runtime.KeepAlive(a)
+	if ret != C.TILEDB_OK {
+		return nil, fmt.Errorf("error getting current domain for tiledb arraySchema: %w", a.context.LastError())
+	}
+
+	return newCurrentDomainFromHandle(a.context, newCurrentDomainHandle(cdPtr)), nil
+}
+
+// CurrentDomain returns the current domain of the open array.
+func (a *Array) CurrentDomain() (*CurrentDomain, error) {
+	schema, err := a.Schema()
+	if err != nil {
+		return nil, err
+	}
+	defer schema.Free()
+
+	return schema.CurrentDomain()
+}
+
+// ExpandCurrentDomain expands the current domain of the array to cd.
+// The new current domain must contain the existing one and be within the domain.
+func (ase *ArraySchemaEvolution) ExpandCurrentDomain(cd *CurrentDomain) error {
+	ret := C.tiledb_array_schema_evolution_expand_current_domain(ase.context.tiledbContext.Get(), ase.tiledbArraySchemaEvolution.Get(), cd.tiledbCurrentDomain.Get())
+	runtime.KeepAlive(ase)
+	runtime.KeepAlive(cd)
+	if ret != C.TILEDB_OK {
+		return fmt.Errorf("error expanding current domain of tiledb arraySchemaEvolution: %w", ase.context.LastError())
+	}
+
+	return nil
+}
+
+// CurrentDomainError is returned when a write query has cells outside the current domain of the array.
+type CurrentDomainError struct {
+	// Dimension is the name of the dimension.
+	Dimension string
+	// Coordinate is the coordinate outside the current domain: the coordinate of a cell of a sparse write,
+	// or a bound of the subarray of a dense write.
+	Coordinate any
+	// Range is the range of the current domain on the dimension.
+	Range Range
+}
+
+func (e *CurrentDomainError) Error() string {
+	return fmt.Sprintf("coordinate %v of dimension %s is outside the current domain [%v, %v]", e.Coordinate, e.Dimension, e.Range.start, e.Range.end)
+}
+
+// checkCurrentDomainCells returns a *CurrentDomainError if the buffers b of a sparse write, built by
+// Write or BufferedWriter, have coordinates outside ranges, the current domain by dimension name.
+// Buffers of attributes are not checked.
+func checkCurrentDomainCells(ranges map[string]Range, b *fieldBuffers) error {
+	r, ok := ranges[b.name]
+	if !ok {
+		return nil
+	}
+	if !b.isVar() {
+		for i := 0; i < b.data.Len(); i++ {
+			if err := checkCoordinate(b.name, r, b.data.Index(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	bytes := b.data.Bytes()
+	for i, start := range b.offsets {
+		end := uint64(len(bytes))
+		if i+1 < len(b.offsets) {
+			end = b.offsets[i+1]
+		}
+		if err := checkCoordinate(b.name, r, reflect.ValueOf(string(bytes[start:end]))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkCurrentDomainSubarray returns a *CurrentDomainError if the subarray of a dense write
+// has bounds outside ranges, the current domain by dimension name.
+func checkCurrentDomainSubarray(ranges map[string]Range, subarray *Subarray) error {
+	subarrayRanges, err := subarray.GetRanges()
+	if err != nil {
+		return err
+	}
+	for name, rs := range subarrayRanges {
+		for _, r := range rs {
+			for _, bound := range []any{r.start, r.end} {
+				if err := checkCoordinate(name, ranges[name], reflect.ValueOf(bound)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// checkCoordinate returns a *CurrentDomainError if v, a coordinate of dimension name, is outside r.
+func checkCoordinate(name string, r Range, v reflect.Value) error {
+	if r.start == nil {
+		return nil
+	}
+	if compareCoordinates(v, reflect.ValueOf(r.start)) < 0 || compareCoordinates(v, reflect.ValueOf(r.end)) > 0 {
+		return &CurrentDomainError{Dimension: name, Coordinate: v.Interface(), Range: r}
+	}
+	return nil
+}
+
+// compareCoordinates compares two coordinates of a dimension, of integer, float or string kinds.
+func compareCoordinates(a, b reflect.Value) int {
+	switch {
+	case a.CanInt():
+		return cmp.Compare(a.Int(), b.Int())
+	case a.CanUint():
+		return cmp.Compare(a.Uint(), b.Uint())
+	case a.CanFloat():
+		return cmp.Compare(a.Float(), b.Float())
+	default:
+		return cmp.Compare(a.String(), b.String())
+	}
+}
+
+// currentDomainRanges returns the ranges of the current domain of the schema by dimension name,
+// or nil if the current domain is empty.
+func currentDomainRanges(schema *ArraySchema) (map[string]Range, error) {
+	cd, err := schema.CurrentDomain()
+	if err != nil {
+		return nil, err
+	}
+	defer cd.Free()
+	isEmpty, err := cd.IsEmpty()
+	if err != nil || isEmpty {
+		return nil, err
+	}
+	ndr, err := cd.NDRectangle()
+	if err != nil {
+		return nil, err
+	}
+	defer ndr.Free()
+
+	domain, err := schema.Domain()
+	if err != nil {
+		return nil, err
+	}
+	defer domain.Free()
+	nDim, err := domain.NDim()
+	if err != nil {
+		return nil, err
+	}
+	ranges := make(map[string]Range, nDim)
+	for i := uint(0); i < nDim; i++ {
+		dimension, err := domain.DimensionFromIndex(i)
+		if err != nil {
+			return nil, err
+		}
+		name, err := dimension.Name()
+		dimension.Free()
+		if err != nil {
+			return nil, err
+		}
+		if ranges[name], err = ndr.Range(uint32(i)); err != nil {
+			return nil, err
+		}
+	}
+	return ranges, nil
+}
@@ -0,0 +1,211 @@
+package tiledb
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNDRectangle(t *testing.T) {
+	tdbCtx, err := NewContext(nil)
+	require.NoError(t, err)
+
+	dimX, err := NewDimension(tdbCtx, "x", TILEDB_INT32, []int32{1, 1000}, int32(10))
+	require.NoError(t, err)
+	dimS, err := NewStringDimension(tdbCtx, "s")
+	require.NoError(t, err)
+	domain, err := NewDomain(tdbCtx)
+	require.NoError(t, err)
+	require.NoError(t, domain.AddDimensions(dimX, dimS))
+
+	ndr, err := NewNDRectangle(tdbCtx, domain)
+	require.NoError(t, err)
+	defer ndr.Free()
+
+	ndim, err := ndr.NDim()
+	require.NoError(t, err)
+	assert.Equal(t, uint32(2), ndim)
+
+	dt, err := ndr.Type(0)
+	require.NoError(t, err)
+	assert.Equal(t, TILEDB_INT32, dt)
+	dt, err = ndr.TypeFromName("s")
+	require.NoError(t, err)
+	assert.Equal(t, TILEDB_STRING_ASCII, dt)
+
+	require.NoError(t, ndr.SetRange(0, MakeRange[int32](1, 10)))
+	require.NoError(t, ndr.SetRangeForName("s", MakeRange("a", "mm")))
+
+	// the range type must match the dimension type
+	require.Error(t, ndr.SetRange(0, MakeRange[int64](1, 10)))
+	require.Error(t, ndr.SetRangeForName("s", MakeRange[int32](1, 10)))
+
+	r, err := ndr.Range(0)
+	require.NoError(t, err)
+	checkRange(t, r, int32(1), int32(10))
+
+	r, err = ndr.RangeFromName("x")
+	require.NoError(t, err)
+	checkRange(t, r, int32(1), int32(10))
+
+	r, err = ndr.Range(1)
+	require.NoError(t, err)
+	checkRange(t, r, "a", "mm")
+}
+
+func TestCurrentDomain(t *testing.T) {
+	tdbCtx, err := NewContext(nil)
+	require.NoError(t, err)
+
+	dimX, err := NewDimension(tdbCtx, "x", TILEDB_INT32, []int32{1, 1000}, int32(10))
+	require.NoError(t, err)
+	domain, err := NewDomain(tdbCtx)
+	require.NoError(t, err)
+	require.NoError(t, domain.AddDimensions(dimX))
+
+	schema, err := NewArraySchema(tdbCtx, TILEDB_SPARSE)
+	require.NoError(t, err)
+	require.NoError(t, schema.SetDomain(domain))
+	attr, err := NewAttribute(tdbCtx, "a", TILEDB_INT32)
+	require.NoError(t, err)
+	require.NoError(t, schema.AddAttributes(attr))
+
+	// a schema starts with an empty current domain
+	cd, err := schema.CurrentDomain()
+	require.NoError(t, err)
+	isEmpty, err := cd.IsEmpty()
+	require.NoError(t, err)
+	assert.True(t, isEmpty)
+
+	cd = newTestCurrentDomain(t, tdbCtx, domain, 10)
+	require.NoError(t, schema.SetCurrentDomain(cd))
+	require.NoError(t, schema.Check())
+
+	arrayPath := t.TempDir()
+	require.NoError(t, CreateArray(tdbCtx, arrayPath, schema))
+
+	array, err := NewArray(tdbCtx, arrayPath)
+	require.NoError(t, err)
+
+	// read back the current domain
+	require.NoError(t, array.Open(TILEDB_READ))
+	cd, err = array.CurrentDomain()
+	require.NoError(t, err)
+	isEmpty, err = cd.IsEmpty()
+	require.NoError(t, err)
+	assert.False(t, isEmpty)
+	cdType, err := cd.Type()
+	require.NoError(t, err)
+	assert.Equal(t, TILEDB_NDRECTANGLE, cdType)
+	ndr, err := cd.NDRectangle()
+	require.NoError(t, err)
+	r, err := ndr.RangeFromName("x")
+	require.NoError(t, err)
+	checkRange(t, r, int32(1), int32(10))
+	require.NoError(t, array.Close())
+
+	// writes within the current domain succeed
+	require.NoError(t, writeCurrentDomainTestCells(array, []int32{1, 5, 10}))
+
+	// the core rejects writes outside the current domain
+	require.Error(t, writeCurrentDomainTestCells(array, []int32{5, 20}))
+
+	// Write and BufferedWriter check them first and return the dimension and the coordinate
+	type cell struct {
+		X int32 `tiledb:"x"`
+		A int32 `tiledb:"a"`
+	}
+	require.NoError(t, array.Open(TILEDB_WRITE))
+	err = Write(tdbCtx, array, []cell{{X: 3, A: 3}, {X: 11, A: 11}}, TILEDB_UNORDERED)
+	var cdErr *CurrentDomainError
+	require.True(t, errors.As(err, &cdErr), err)
+	assert.Equal(t, "x", cdErr.Dimension)
+	assert.Equal(t, int32(11), cdErr.Coordinate)
+	checkRange(t, cdErr.Range, int32(1), int32(10))
+
+	w, err := NewBufferedWriter[cell](tdbCtx, array, BufferedWriterOptions{})
+	require.NoError(t, err)
+	require.NoError(t, w.Add(cell{X: 500, A: 500}))
+	err = w.Close()
+	require.True(t, errors.As(err, &cdErr), err)
+	assert.Equal(t, int32(500), cdErr.Coordinate)
+	require.NoError(t, array.Close())
+
+	require.NoError(t, array.Open(TILEDB_READ))
+	nonEmptyDomain, isEmpty, err := array.NonEmptyDomain()
+	require.NoError(t, err)
+	require.False(t, isEmpty)
+	assert.Equal(t, []int32{1, 10}, nonEmptyDomain[0].Bounds)
+	require.NoError(t, array.Close())
+
+	// expand the current domain and write in the new part
+	ase, err := NewArraySchemaEvolution(tdbCtx)
+	require.NoError(t, err)
+	require.NoError(t, ase.ExpandCurrentDomain(newTestCurrentDomain(t, tdbCtx, domain, 100)))
+	require.NoError(t, ase.Evolve(arrayPath))
+
+	require.NoError(t, writeCurrentDomainTestCells(array, []int32{5, 20}))
+
+	require.NoError(t, array.Open(TILEDB_READ))
+	nonEmptyDomain, isEmpty, err = array.NonEmptyDomain()
+	require.NoError(t, err)
+	require.False(t, isEmpty)
+	assert.Equal(t, []int32{1, 20}, nonEmptyDomain[0].Bounds)
+	// the non-empty domain is within the current domain
+	cd, err = array.CurrentDomain()
+	require.NoError(t, err)
+	ndr, err = cd.NDRectangle()
+	require.NoError(t, err)
+	r, err = ndr.RangeFromName("x")
+	require.NoError(t, err)
+	bounds := nonEmptyDomain[0].Bounds.([]int32)
+	current, err := ExtractRange[int32](r)
+	require.NoError(t, err)
+	assert.True(t, bounds[0] >= current[0] && bounds[1] <= current[1])
+	require.NoError(t, array.Close())
+
+	// the current domain cannot shrink
+	ase, err = NewArraySchemaEvolution(tdbCtx)
+	require.NoError(t, err)
+	require.NoError(t, ase.ExpandCurrentDomain(newTestCurrentDomain(t, tdbCtx, domain, 50)))
+	require.Error(t, ase.Evolve(arrayPath))
+}
+
+func newTestCurrentDomain(t *testing.T, tdbCtx *Context, domain *Domain, upper int32) *CurrentDomain {
+	ndr, err := NewNDRectangle(tdbCtx, domain)
+	require.NoError(t, err)
+	require.NoError(t, ndr.SetRangeForName("x", MakeRange[int32](1, upper)))
+
+	cd, err := NewCurrentDomain(tdbCtx)
+	require.NoError(t, err)
+	require.NoError(t, cd.SetNDRectangle(ndr))
+
+	return cd
+}
+
+func writeCurrentDomainTestCells(array *Array, coords []int32) error {
+	if err := array.Open(TILEDB_WRITE); err != nil {
+		return err
+	}
+	defer array.Close()
+
+	query, err := NewQuery(array.context, array)
+	if err != nil {
+		return err
+	}
+	defer query.Free()
+
+	if err := query.SetLayout(TILEDB_UNORDERED); err != nil {
+		return err
+	}
+	if _, err := query.SetDataBuffer("x", coords); err != nil {
+		return err
+	}
+	if _, err := query.SetDataBuffer("a", coords); err != nil {
+		return err
+	}
+
+	return query.Submit()
+}
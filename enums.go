@@ -27,6 +27,25 @@ const (
 	TILEDB_SPARSE ArrayType = C.TILEDB_SPARSE
 )
 
+// String returns a string representation.
+func (arrayType ArrayType) String() string {
+	var ctype *C.char
+	C.tiledb_array_type_to_str(C.tiledb_array_type_t(arrayType), &ctype)
+	return C.GoString(ctype)
+}
+
+// ArrayTypeFromString converts from an array type string, such as "dense", to enum.
+func ArrayTypeFromString(s string) (ArrayType, error) {
+	cname := C.CString(s)
+	defer C.free(unsafe.Pointer(cname))
+	var cArrayType C.tiledb_array_type_t
+	ret := C.tiledb_array_type_from_str(cname, &cArrayType)
+	if ret != C.TILEDB_OK {
+		return 0, fmt.Errorf("%q is not a recognized tiledb_array_type_t", s)
+	}
+	return ArrayType(cArrayType), nil
+}
+
 // Datatype
 type Datatype int8
 
@@ -437,8 +456,37 @@ const (
 	TILEDB_FILTER_SCALE_FLOAT FilterType = C.TILEDB_FILTER_SCALE_FLOAT
 	// TILEDB_FILTER_DELTA Delta encoding filter.
 	TILEDB_FILTER_DELTA FilterType = C.TILEDB_FILTER_DELTA
+	// TILEDB_FILTER_CHECKSUM_MD5 MD5 checksum filter.
+	TILEDB_FILTER_CHECKSUM_MD5 FilterType = C.TILEDB_FILTER_CHECKSUM_MD5
+	// TILEDB_FILTER_CHECKSUM_SHA256 SHA256 checksum filter.
+	TILEDB_FILTER_CHECKSUM_SHA256 FilterType = C.TILEDB_FILTER_CHECKSUM_SHA256
+	// TILEDB_FILTER_DICTIONARY Dictionary encoding filter for strings.
+	TILEDB_FILTER_DICTIONARY FilterType = C.TILEDB_FILTER_DICTIONARY
+	// TILEDB_FILTER_XOR XOR filter.
+	TILEDB_FILTER_XOR FilterType = C.TILEDB_FILTER_XOR
+	// TILEDB_FILTER_WEBP WebP image compressor. The library must be built with WebP support.
+	TILEDB_FILTER_WEBP FilterType = C.TILEDB_FILTER_WEBP
 )
 
+// String returns a string representation.
+func (filterType FilterType) String() string {
+	var ctype *C.char
+	C.tiledb_filter_type_to_str(C.tiledb_filter_type_t(filterType), &ctype)
+	return C.GoString(ctype)
+}
+
+// FilterTypeFromString converts from a filter type string, such as "ZSTD", to enum.
+func FilterTypeFromString(s string) (FilterType, error) {
+	cname := C.CString(s)
+	defer C.free(unsafe.Pointer(cname))
+	var cFilterType C.tiledb_filter_type_t
+	ret := C.tiledb_filter_type_from_str(cname, &cFilterType)
+	if ret != C.TILEDB_OK {
+		return 0, fmt.Errorf("%q is not a recognized tiledb_filter_type_t", s)
+	}
+	return FilterType(cFilterType), nil
+}
+
 // FilterOption for a given filter
 type FilterOption uint8
 
@@ -449,6 +497,56 @@ const (
 	TILEDB_BIT_WIDTH_MAX_WINDOW FilterOption = C.TILEDB_BIT_WIDTH_MAX_WINDOW
 	// TILEDB_POSITIVE_DELTA_MAX_WINDOW Max window length for positive-delta encoding. Type: `uint32_t`.
 	TILEDB_POSITIVE_DELTA_MAX_WINDOW FilterOption = C.TILEDB_POSITIVE_DELTA_MAX_WINDOW
+	// TILEDB_SCALE_FLOAT_BYTEWIDTH Byte width of the integers stored by float scaling. Type: `uint64_t`.
+	TILEDB_SCALE_FLOAT_BYTEWIDTH FilterOption = C.TILEDB_SCALE_FLOAT_BYTEWIDTH
+	// TILEDB_SCALE_FLOAT_FACTOR Scale factor of float scaling. Type: `double`.
+	TILEDB_SCALE_FLOAT_FACTOR FilterOption = C.TILEDB_SCALE_FLOAT_FACTOR
+	// TILEDB_SCALE_FLOAT_OFFSET Offset of float scaling. Type: `double`.
+	TILEDB_SCALE_FLOAT_OFFSET FilterOption = C.TILEDB_SCALE_FLOAT_OFFSET
+	// TILEDB_WEBP_QUALITY Quality of WebP lossy compression, from 0.0 to 100.0. Type: `float`.
+	TILEDB_WEBP_QUALITY FilterOption = C.TILEDB_WEBP_QUALITY
+	// TILEDB_WEBP_INPUT_FORMAT Colorspace format of WebP input. Type: `WebPFormat`.
+	TILEDB_WEBP_INPUT_FORMAT FilterOption = C.TILEDB_WEBP_INPUT_FORMAT
+	// TILEDB_WEBP_LOSSLESS Whether WebP compression is lossless. Type: `bool`.
+	TILEDB_WEBP_LOSSLESS FilterOption = C.TILEDB_WEBP_LOSSLESS
+	// TILEDB_COMPRESSION_REINTERPRET_DATATYPE Datatype the data is reinterpreted as
+	// before compression. Type: `Datatype`.
+	TILEDB_COMPRESSION_REINTERPRET_DATATYPE FilterOption = C.TILEDB_COMPRESSION_REINTERPRET_DATATYPE
+)
+
+// String returns a string representation.
+func (filterOption FilterOption) String() string {
+	var coption *C.char
+	C.tiledb_filter_option_to_str(C.tiledb_filter_option_t(filterOption), &coption)
+	return C.GoString(coption)
+}
+
+// FilterOptionFromString converts from a filter option string, such as "COMPRESSION_LEVEL", to enum.
+func FilterOptionFromString(s string) (FilterOption, error) {
+	cname := C.CString(s)
+	defer C.free(unsafe.Pointer(cname))
+	var cFilterOption C.tiledb_filter_option_t
+	ret := C.tiledb_filter_option_from_str(cname, &cFilterOption)
+	if ret != C.TILEDB_OK {
+		return 0, fmt.Errorf("%q is not a recognized tiledb_filter_option_t", s)
+	}
+	return FilterOption(cFilterOption), nil
+}
+
+// WebPFormat is the colorspace format of the input of the WebP filter
+type WebPFormat uint8
+
+const (
+	// TILEDB_WEBP_NONE Unspecified format
+	TILEDB_WEBP_NONE WebPFormat = C.TILEDB_WEBP_NONE
+	// TILEDB_WEBP_RGB RGB format
+	TILEDB_WEBP_RGB WebPFormat = C.TILEDB_WEBP_RGB
+	// TILEDB_WEBP_BGR BGR format
+	TILEDB_WEBP_BGR WebPFormat = C.TILEDB_WEBP_BGR
+	// TILEDB_WEBP_RGBA RGBA format
+	TILEDB_WEBP_RGBA WebPFormat = C.TILEDB_WEBP_RGBA
+	// TILEDB_WEBP_BGRA BGRA format
+	TILEDB_WEBP_BGRA WebPFormat = C.TILEDB_WEBP_BGRA
 )
 
 // FS represents support fs types
@@ -478,6 +576,25 @@ const (
 	TILEDB_HILBERT Layout = C.TILEDB_HILBERT
 )
 
+// String returns a string representation.
+func (layout Layout) String() string {
+	var clayout *C.char
+	C.tiledb_layout_to_str(C.tiledb_layout_t(layout), &clayout)
+	return C.GoString(clayout)
+}
+
+// LayoutFromString converts from a layout string, such as "row-major", to enum.
+func LayoutFromString(s string) (Layout, error) {
+	cname := C.CString(s)
+	defer C.free(unsafe.Pointer(cname))
+	var cLayout C.tiledb_layout_t
+	ret := C.tiledb_layout_from_str(cname, &cLayout)
+	if ret != C.TILEDB_OK {
+		return 0, fmt.Errorf("%q is not a recognized tiledb_layout_t", s)
+	}
+	return Layout(cLayout), nil
+}
+
 // QueryStatus status of a query
 type QueryStatus int8
 
@@ -573,6 +690,12 @@ const (
 	TILEDB_QUERY_CONDITION_EQ QueryConditionOp = C.TILEDB_EQ
 	// TILEDB_QUERY_CONDITION_NE defines the query condition for a not equal to comparison
 	TILEDB_QUERY_CONDITION_NE QueryConditionOp = C.TILEDB_NE
+	// TILEDB_QUERY_CONDITION_IN defines the query condition for a set membership test.
+	// It can only be used with NewQueryConditionSetMembership.
+	TILEDB_QUERY_CONDITION_IN QueryConditionOp = C.TILEDB_IN
+	// TILEDB_QUERY_CONDITION_NOT_IN defines the query condition for a set non-membership test.
+	// It can only be used with NewQueryConditionSetMembership.
+	TILEDB_QUERY_CONDITION_NOT_IN QueryConditionOp = C.TILEDB_NOT_IN
 )
 
 // QueryConditionCombinationOp operation type for a query condition combination
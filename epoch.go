@@ -1,6 +1,11 @@
 package tiledb
 
-import "time"
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
 
 const secondsInCommonYear = 31536000
 const secondsInLeapYear = 31622400
@@ -163,9 +168,9 @@ func GetTimeFromTimestamp(datatype Datatype, timestamp int64) time.Time {
 	case TILEDB_DATETIME_SEC, TILEDB_TIME_SEC:
 		then = time.Unix(timestamp, 0)
 	case TILEDB_DATETIME_MS, TILEDB_TIME_MS:
-		then = time.Unix(0, int64(timestamp*1000*1000))
+		then = time.UnixMilli(timestamp)
 	case TILEDB_DATETIME_US, TILEDB_TIME_US:
-		then = time.Unix(0, int64(timestamp*1000))
+		then = time.UnixMicro(timestamp)
 	case TILEDB_DATETIME_NS, TILEDB_TIME_NS:
 		then = time.Unix(0, timestamp)
 	case TILEDB_DATETIME_PS, TILEDB_TIME_PS:
@@ -173,8 +178,89 @@ func GetTimeFromTimestamp(datatype Datatype, timestamp int64) time.Time {
 	case TILEDB_DATETIME_FS, TILEDB_TIME_FS:
 		then = time.Unix(0, int64(timestamp/(1000*1000)))
 	case TILEDB_DATETIME_AS, TILEDB_TIME_AS:
-		then = time.Unix(0, int64(timestamp/(1000*1000)))
+		then = time.Unix(0, int64(timestamp/(1000*1000*1000)))
 	}
 
 	return then.UTC()
 }
+
+// floorDiv returns x/y rounded towards negative infinity, for y > 0
+func floorDiv(x, y int64) int64 {
+	q := x / y
+	if x%y < 0 {
+		q--
+	}
+	return q
+}
+
+// scaledTimestamp returns sec*perSec + nsec*perNsec, or an error if it overflows an int64.
+// nsec is in [0, 999999999] and perNsec is at most 1e9.
+func scaledTimestamp(sec, nsec, perSec, perNsec int64) (int64, error) {
+	if sec < 0 && nsec > 0 {
+		// Give the fraction the sign of the seconds, so that the timestamps close to math.MinInt64 are not rejected.
+		sec++
+		nsec -= 1000 * 1000 * 1000
+	}
+	if sec > math.MaxInt64/perSec || sec < math.MinInt64/perSec {
+		return 0, errTimestampOverflow
+	}
+	scaled := sec * perSec
+	fraction := nsec * perNsec
+	if (fraction > 0 && scaled > math.MaxInt64-fraction) || (fraction < 0 && scaled < math.MinInt64-fraction) {
+		return 0, errTimestampOverflow
+	}
+	return scaled + fraction, nil
+}
+
+// errTimestampOverflow is returned when a time cannot be represented at the resolution of a datatype.
+var errTimestampOverflow = errors.New("timestamp overflows int64")
+
+/*
+GetTimestampFromTime returns the timestamp of t for a time related TileDB datatype.
+It is the inverse of GetTimeFromTimestamp: t is truncated to the resolution of the datatype.
+
+An error is returned if the timestamp does not fit in an int64. At the finest resolutions
+this limits t to about 292 years around the epoch for TILEDB_DATETIME_NS, 106 days for
+TILEDB_DATETIME_PS, 2.5 hours for TILEDB_DATETIME_FS and 9.2 seconds for TILEDB_DATETIME_AS,
+and likewise for the TILEDB_TIME datatypes.
+*/
+func GetTimestampFromTime(datatype Datatype, t time.Time) (int64, error) {
+	t = t.UTC()
+	var timestamp int64
+	var err error
+	switch datatype {
+	case TILEDB_DATETIME_YEAR:
+		timestamp = int64(t.Year() - epochYear)
+	case TILEDB_DATETIME_MONTH:
+		timestamp = int64(t.Year()-epochYear)*12 + int64(t.Month()-time.January)
+	case TILEDB_DATETIME_WEEK:
+		timestamp = floorDiv(t.Unix(), 7*secondsInDay)
+	case TILEDB_DATETIME_DAY:
+		timestamp = floorDiv(t.Unix(), secondsInDay)
+	case TILEDB_DATETIME_HR, TILEDB_TIME_HR:
+		timestamp = floorDiv(t.Unix(), secondsInHour)
+	case TILEDB_DATETIME_MIN, TILEDB_TIME_MIN:
+		timestamp = floorDiv(t.Unix(), secondsInMin)
+	case TILEDB_DATETIME_SEC, TILEDB_TIME_SEC:
+		timestamp = t.Unix()
+	case TILEDB_DATETIME_MS, TILEDB_TIME_MS:
+		timestamp = t.UnixMilli()
+	case TILEDB_DATETIME_US, TILEDB_TIME_US:
+		timestamp = t.UnixMicro()
+	case TILEDB_DATETIME_NS, TILEDB_TIME_NS:
+		timestamp, err = scaledTimestamp(t.Unix(), int64(t.Nanosecond()), 1000*1000*1000, 1)
+	case TILEDB_DATETIME_PS, TILEDB_TIME_PS:
+		timestamp, err = scaledTimestamp(t.Unix(), int64(t.Nanosecond()), 1000*1000*1000*1000, 1000)
+	case TILEDB_DATETIME_FS, TILEDB_TIME_FS:
+		timestamp, err = scaledTimestamp(t.Unix(), int64(t.Nanosecond()), 1000*1000*1000*1000*1000, 1000*1000)
+	case TILEDB_DATETIME_AS, TILEDB_TIME_AS:
+		timestamp, err = scaledTimestamp(t.Unix(), int64(t.Nanosecond()), 1000*1000*1000*1000*1000*1000, 1000*1000*1000)
+	default:
+		return 0, fmt.Errorf("%s is not a time datatype", datatype)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("time %s at resolution %s: %w", t.Format(time.RFC3339Nano), datatype, err)
+	}
+
+	return timestamp, nil
+}
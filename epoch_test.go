@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestEpoch(t *testing.T) {
@@ -40,3 +41,117 @@ func TestEpoch(t *testing.T) {
 	then = time.Date(1969, 12, 31, 23, 59, 59, 0, time.UTC)
 	assert.Equal(t, then, timeObject)
 }
+
+func TestTimestampFromTime(t *testing.T) {
+	timestamp := func(datatype Datatype, then time.Time) int64 {
+		t.Helper()
+		ts, err := GetTimestampFromTime(datatype, then)
+		require.NoError(t, err)
+		return ts
+	}
+
+	then := time.Date(1970, 4, 16, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, int64(15), timestamp(TILEDB_DATETIME_WEEK, then))
+
+	then = time.Date(1969, 9, 18, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, int64(-15), timestamp(TILEDB_DATETIME_WEEK, then))
+
+	then = time.Date(1976, 12, 1, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, int64(83), timestamp(TILEDB_DATETIME_MONTH, then))
+
+	then = time.Date(1985, 1, 1, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, int64(15), timestamp(TILEDB_DATETIME_YEAR, then))
+
+	then = time.Date(1955, 6, 1, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, int64(-15), timestamp(TILEDB_DATETIME_YEAR, then))
+
+	// timestamps are truncated towards negative infinity
+	then = time.Date(1969, 12, 31, 23, 59, 59, 0, time.UTC)
+	assert.Equal(t, int64(-1), timestamp(TILEDB_DATETIME_DAY, then))
+	assert.Equal(t, int64(-1000000000), timestamp(TILEDB_DATETIME_NS, then))
+
+	then = time.Date(1970, 1, 1, 0, 0, 1, 500000000, time.UTC)
+	assert.Equal(t, int64(1500), timestamp(TILEDB_DATETIME_MS, then))
+
+	then = time.Date(1970, 1, 1, 0, 0, 1, 500, time.UTC)
+	assert.Equal(t, int64(1000000500000), timestamp(TILEDB_DATETIME_PS, then))
+	assert.Equal(t, int64(1000000500000000), timestamp(TILEDB_DATETIME_FS, then))
+	assert.Equal(t, int64(1000000500000000000), timestamp(TILEDB_DATETIME_AS, then))
+
+	_, err := GetTimestampFromTime(TILEDB_INT64, then)
+	assert.Error(t, err)
+}
+
+func TestTimestampRoundTrip(t *testing.T) {
+	for _, tc := range []struct {
+		datatypes []Datatype
+		times     []time.Time
+	}{
+		{
+			[]Datatype{TILEDB_DATETIME_YEAR},
+			[]time.Time{time.Date(1955, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		},
+		{
+			[]Datatype{TILEDB_DATETIME_MONTH},
+			[]time.Time{time.Date(1967, 12, 1, 0, 0, 0, 0, time.UTC), time.Date(1970, 12, 1, 0, 0, 0, 0, time.UTC)},
+		},
+		{
+			[]Datatype{TILEDB_DATETIME_WEEK},
+			[]time.Time{time.Date(1969, 9, 18, 0, 0, 0, 0, time.UTC), time.Date(2024, 3, 7, 0, 0, 0, 0, time.UTC)},
+		},
+		{
+			[]Datatype{TILEDB_DATETIME_DAY},
+			[]time.Time{time.Date(1955, 6, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		},
+		{
+			[]Datatype{TILEDB_DATETIME_HR, TILEDB_TIME_HR, TILEDB_DATETIME_MIN, TILEDB_TIME_MIN, TILEDB_DATETIME_SEC, TILEDB_TIME_SEC},
+			[]time.Time{time.Date(1955, 6, 1, 13, 0, 0, 0, time.UTC), time.Date(2024, 3, 1, 13, 0, 0, 0, time.UTC)},
+		},
+		{
+			[]Datatype{TILEDB_DATETIME_MS, TILEDB_TIME_MS, TILEDB_DATETIME_US, TILEDB_TIME_US, TILEDB_DATETIME_NS, TILEDB_TIME_NS},
+			[]time.Time{time.Date(1955, 6, 1, 13, 0, 0, 125000000, time.UTC), time.Date(2024, 3, 1, 13, 0, 0, 125000000, time.UTC)},
+		},
+		{
+			// Beyond the 292 years around the epoch of nanosecond times
+			[]Datatype{TILEDB_DATETIME_MS, TILEDB_TIME_MS, TILEDB_DATETIME_US, TILEDB_TIME_US},
+			[]time.Time{time.Date(1000, 1, 1, 13, 0, 0, 125000000, time.UTC), time.Date(3000, 3, 1, 13, 0, 0, 125000000, time.UTC)},
+		},
+		{
+			[]Datatype{TILEDB_DATETIME_PS, TILEDB_TIME_PS},
+			[]time.Time{time.Date(1969, 10, 1, 13, 0, 0, 125000001, time.UTC), time.Date(1970, 3, 1, 13, 0, 0, 125000001, time.UTC)},
+		},
+		{
+			[]Datatype{TILEDB_DATETIME_FS, TILEDB_TIME_FS},
+			[]time.Time{time.Date(1969, 12, 31, 23, 0, 0, 125000001, time.UTC), time.Date(1970, 1, 1, 1, 0, 0, 125000001, time.UTC)},
+		},
+		{
+			[]Datatype{TILEDB_DATETIME_AS, TILEDB_TIME_AS},
+			[]time.Time{time.Date(1969, 12, 31, 23, 59, 51, 125000001, time.UTC), time.Date(1970, 1, 1, 0, 0, 9, 125000001, time.UTC)},
+		},
+	} {
+		for _, datatype := range tc.datatypes {
+			for _, then := range tc.times {
+				ts, err := GetTimestampFromTime(datatype, then)
+				require.NoError(t, err, "%s %s", datatype, then)
+				assert.Equal(t, then, GetTimeFromTimestamp(datatype, ts), "%s %s", datatype, then)
+			}
+		}
+	}
+
+	// The timestamps of times too far from the epoch do not fit in an int64
+	for _, tc := range []struct {
+		datatype Datatype
+		then     time.Time
+	}{
+		{TILEDB_DATETIME_NS, time.Date(2300, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{TILEDB_TIME_NS, time.Date(1600, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{TILEDB_DATETIME_PS, time.Date(1970, 4, 18, 0, 0, 0, 0, time.UTC)},
+		{TILEDB_TIME_PS, time.Date(1969, 9, 15, 0, 0, 0, 0, time.UTC)},
+		{TILEDB_DATETIME_FS, time.Date(1970, 1, 1, 3, 0, 0, 0, time.UTC)},
+		{TILEDB_DATETIME_AS, time.Date(1970, 1, 1, 0, 0, 10, 0, time.UTC)},
+		{TILEDB_TIME_AS, time.Date(1969, 12, 31, 23, 59, 50, 0, time.UTC)},
+	} {
+		_, err := GetTimestampFromTime(tc.datatype, tc.then)
+		assert.Error(t, err, "%s %s", tc.datatype, tc.then)
+	}
+}
@@ -156,7 +156,7 @@ func readArrayMetadata(dir string) {
 	checkError(err)
 	defer config.Free()
 
-	err = config.Set("sm.consolidation.mode", "array_meta")
+	err = config.SetConsolidationMode(tiledb.TILEDB_CONSOLIDATION_ARRAY_META)
 	checkError(err)
 
 	err = tiledb.ConsolidateArray(ctx, dir, config)
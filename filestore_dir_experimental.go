@@ -0,0 +1,365 @@
+package tiledb
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// DirImportOptions configures ImportDir.
+type DirImportOptions struct {
+	// Workers is the number of files imported at once. If zero, runtime.GOMAXPROCS(0) is used.
+	Workers int
+}
+
+// DirExportOptions configures ExportDir.
+type DirExportOptions struct {
+	// Workers is the number of files exported at once. If zero, runtime.GOMAXPROCS(0) is used.
+	Workers int
+	// Manifest is the name of a file of the exported directory the manifest is written to, as JSON.
+	// If empty, it is only returned.
+	Manifest string
+}
+
+// DirManifest lists the files exported by ExportDir.
+type DirManifest struct {
+	Files []ManifestFile `json:"files"`
+}
+
+// ManifestFile describes a file exported by ExportDir.
+type ManifestFile struct {
+	// Path is the slash-separated path of the file, relative to the exported directory.
+	Path string `json:"path"`
+	// ArrayURI is the URI of the filestore array of the file.
+	ArrayURI string `json:"array_uri"`
+	// Size is the size of the file.
+	Size int64 `json:"size"`
+	// SHA256 is the hex-encoded SHA-256 checksum of the file.
+	SHA256 string `json:"sha256"`
+}
+
+// dirMember is a file or a directory of a directory tree imported to or exported from a group.
+type dirMember struct {
+	rel   string // the slash-separated path relative to the root of the tree, "." for the root
+	uri   string // the URI of the group or the array
+	path  string // the local path
+	size  int64
+	isDir bool
+}
+
+/*
+ImportDir imports the local directory dir recursively into a new group at groupURI. Each subdirectory
+is a group and each file a filestore array, written with a FileWriter, at the same relative path
+under groupURI. The MIME types of the files are detected like CreateFileWriter does. The members of the groups are named after their slash-separated path relative to dir,
+e.g. "docs/report.pdf", and have relative URIs. The files are imported on opts.Workers goroutines.
+
+Symbolic links and other non-regular files are skipped.
+*/
+func ImportDir(tdbCtx *Context, groupURI, dir string, opts DirImportOptions) error {
+	groupURI = strings.TrimSuffix(groupURI, "/")
+	groups := []dirMember{{rel: ".", uri: groupURI, path: dir, isDir: true}}
+	var files []dirMember
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+		member := dirMember{rel: rel, uri: groupURI + "/" + rel, path: p, isDir: d.IsDir()}
+		switch {
+		case member.isDir:
+			groups = append(groups, member)
+		case d.Type().IsRegular():
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			member.size = info.Size()
+			files = append(files, member)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("error listing directory to import: %w", err)
+	}
+
+	// WalkDir visits the directories before their contents.
+	for _, g := range groups {
+		if err := CreateGroup(tdbCtx, g.uri); err != nil {
+			return err
+		}
+	}
+
+	err = runParallel(files, opts.Workers, func(f dirMember) error {
+		if err := importDirFile(tdbCtx, f); err != nil {
+			return fmt.Errorf("error importing %s: %w", f.rel, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	members := make(map[string][]dirMember, len(groups))
+	for _, g := range groups[1:] {
+		members[path.Dir(g.rel)] = append(members[path.Dir(g.rel)], g)
+	}
+	for _, f := range files {
+		members[path.Dir(f.rel)] = append(members[path.Dir(f.rel)], f)
+	}
+	for _, g := range groups {
+		if err := addDirMembers(tdbCtx, g.uri, members[g.rel]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// importDirFile imports the file f to a new filestore array.
+// All the files go through a FileWriter, which also accepts empty files, so that their MIME type
+// is always detected the same way.
+func importDirFile(tdbCtx *Context, f dirMember) error {
+	file, err := os.Open(f.path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	w, err := CreateFileWriter(tdbCtx, f.uri, TILEDB_MIME_AUTODETECT, FileWriterOptions{
+		FileName:    f.path,
+		Consolidate: f.size > DefaultFileChunkSize,
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(w, file); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+// addDirMembers adds the members to the group at groupURI, with URIs relative to it.
+func addDirMembers(tdbCtx *Context, groupURI string, members []dirMember) error {
+	if len(members) == 0 {
+		return nil
+	}
+
+	group, err := NewGroup(tdbCtx, groupURI)
+	if err != nil {
+		return err
+	}
+	defer group.Free()
+	if err := group.Open(TILEDB_WRITE); err != nil {
+		return err
+	}
+
+	for _, m := range members {
+		objectType := TILEDB_ARRAY
+		if m.isDir {
+			objectType = TILEDB_GROUP
+		}
+		if err := group.AddMemberWithType(path.Base(m.rel), m.rel, true, objectType); err != nil {
+			group.Close()
+			return err
+		}
+	}
+
+	return group.Close()
+}
+
+/*
+ExportDir exports the group at groupURI recursively to the local directory dir, which is created if needed.
+Each member group is a subdirectory and each member array, which must have a filestore schema, a file.
+The file names are the last element of the member names, or of their URIs if they have no name,
+so the groups created by ImportDir are exported to the same tree. The files are exported with ExportFile
+on opts.Workers goroutines.
+
+It returns the manifest of the exported files, sorted by path, with their sizes and SHA-256 checksums.
+*/
+func ExportDir(tdbCtx *Context, dir, groupURI string, opts DirExportOptions) (*DirManifest, error) {
+	var dirs []string
+	var files []dirMember
+	if err := walkDirGroup(tdbCtx, groupURI, ".", &dirs, &files); err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	for _, d := range dirs {
+		if err := os.MkdirAll(filepath.Join(dir, filepath.FromSlash(d)), 0o755); err != nil {
+			return nil, err
+		}
+	}
+
+	manifest := &DirManifest{Files: make([]ManifestFile, len(files))}
+	indexes := make([]int, len(files))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	err := runParallel(indexes, opts.Workers, func(i int) error {
+		f := files[i]
+		f.path = filepath.Join(dir, filepath.FromSlash(f.rel))
+		entry, err := exportDirFile(tdbCtx, f)
+		if err != nil {
+			return fmt.Errorf("error exporting %s: %w", f.rel, err)
+		}
+		manifest.Files[i] = *entry
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(manifest.Files, func(a, b ManifestFile) int { return strings.Compare(a.Path, b.Path) })
+
+	if opts.Manifest != "" {
+		data, err := json.MarshalIndent(manifest, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(filepath.Join(dir, opts.Manifest), data, 0o644); err != nil {
+			return nil, err
+		}
+	}
+
+	return manifest, nil
+}
+
+// walkDirGroup lists the subgroups and the arrays of the group at groupURI, at the path rel of the tree.
+func walkDirGroup(tdbCtx *Context, groupURI, rel string, dirs *[]string, files *[]dirMember) error {
+	group, err := NewGroup(tdbCtx, groupURI)
+	if err != nil {
+		return err
+	}
+	defer group.Free()
+	if err := group.Open(TILEDB_READ); err != nil {
+		return err
+	}
+
+	count, err := group.GetMemberCount()
+	if err != nil {
+		group.Close()
+		return err
+	}
+	members := make([]dirMember, 0, count)
+	types := make([]ObjectTypeEnum, 0, count)
+	for i := uint64(0); i < count; i++ {
+		uri, name, objectType, err := group.GetMemberFromIndex(i)
+		if err != nil {
+			group.Close()
+			return err
+		}
+		if name == "" {
+			name = strings.TrimSuffix(uri, "/")
+		}
+		base := path.Base(name)
+		if base == "." || base == ".." || base == "/" {
+			group.Close()
+			return fmt.Errorf("cannot export member %s of group %s to a file", name, groupURI)
+		}
+		members = append(members, dirMember{rel: path.Join(rel, base), uri: uri})
+		types = append(types, objectType)
+	}
+	if err := group.Close(); err != nil {
+		return err
+	}
+
+	for i, m := range members {
+		switch types[i] {
+		case TILEDB_GROUP:
+			*dirs = append(*dirs, m.rel)
+			if err := walkDirGroup(tdbCtx, m.uri, m.rel, dirs, files); err != nil {
+				return err
+			}
+		case TILEDB_ARRAY:
+			*files = append(*files, m)
+		default:
+			return fmt.Errorf("member %s of group %s is not a group nor an array", m.rel, groupURI)
+		}
+	}
+	return nil
+}
+
+// exportDirFile exports the filestore array of f to f.path and returns its manifest entry.
+func exportDirFile(tdbCtx *Context, f dirMember) (*ManifestFile, error) {
+	size, err := FileSize(tdbCtx, f.uri)
+	if err != nil {
+		return nil, err
+	}
+	if size == 0 {
+		err = os.WriteFile(f.path, nil, 0o644)
+	} else {
+		err = ExportFile(tdbCtx, f.path, f.uri)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(f.path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	hash := sha256.New()
+	written, err := io.Copy(hash, file)
+	if err != nil {
+		return nil, err
+	}
+	if written != size {
+		return nil, fmt.Errorf("exported %d bytes of a file of size %d", written, size)
+	}
+
+	return &ManifestFile{Path: f.rel, ArrayURI: f.uri, Size: size, SHA256: hex.EncodeToString(hash.Sum(nil))}, nil
+}
+
+// runParallel calls fn with each item on up to workers goroutines, runtime.GOMAXPROCS(0) if zero.
+// It returns the errors of the calls joined.
+func runParallel[T any](items []T, workers int, fn func(T) error) error {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	jobs := make(chan T)
+	var mu sync.Mutex
+	var errs []error
+	var wg sync.WaitGroup
+	for range min(workers, len(items)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for item := range jobs {
+				if err := fn(item); err != nil {
+					mu.Lock()
+					errs = append(errs, err)
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	for _, item := range items {
+		jobs <- item
+	}
+	close(jobs)
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
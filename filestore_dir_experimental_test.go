@@ -0,0 +1,103 @@
+package tiledb
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportExportDir(t *testing.T) {
+	tdbCtx, err := NewContext(nil)
+	require.NoError(t, err)
+
+	files := map[string]string{
+		"readme.txt":            "hello filestore\n",
+		"docs/notes.md":         "# Notes\n",
+		"docs/empty.txt":        "",
+		"docs/nested/data.csv":  "a,b\n1,2\n",
+		"images/placeholder.js": "console.log(1)\n",
+	}
+	srcDir := t.TempDir()
+	for name, contents := range files {
+		p := filepath.Join(srcDir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0o755))
+		require.NoError(t, os.WriteFile(p, []byte(contents), 0o644))
+	}
+	require.NoError(t, os.MkdirAll(filepath.Join(srcDir, "emptydir"), 0o755))
+
+	groupURI := "file://" + filepath.Join(t.TempDir(), "group")
+	require.NoError(t, ImportDir(tdbCtx, groupURI, srcDir, DirImportOptions{Workers: 2}))
+
+	t.Run("Members", func(t *testing.T) {
+		group, err := NewGroup(tdbCtx, groupURI+"/docs")
+		require.NoError(t, err)
+		defer group.Free()
+		require.NoError(t, group.Open(TILEDB_READ))
+		defer group.Close()
+
+		count, err := group.GetMemberCount()
+		require.NoError(t, err)
+		assert.EqualValues(t, 3, count)
+		names := make(map[string]ObjectTypeEnum)
+		for i := uint64(0); i < count; i++ {
+			_, name, objectType, err := group.GetMemberFromIndex(i)
+			require.NoError(t, err)
+			names[name] = objectType
+		}
+		assert.Equal(t, map[string]ObjectTypeEnum{
+			"docs/notes.md":  TILEDB_ARRAY,
+			"docs/empty.txt": TILEDB_ARRAY,
+			"docs/nested":    TILEDB_GROUP,
+		}, names)
+	})
+
+	t.Run("Metadata", func(t *testing.T) {
+		// Empty and non-empty files are detected the same way
+		for name, want := range map[string]FileMetadata{
+			"readme.txt":     {Size: 16, MimeType: "text/plain", MimeEncoding: "utf-8", OriginalFileName: "readme.txt", FileExtension: ".txt"},
+			"docs/empty.txt": {MimeType: "application/octet-stream", MimeEncoding: "binary", OriginalFileName: "empty.txt", FileExtension: ".txt"},
+		} {
+			metadata, err := ReadFileMetadata(tdbCtx, groupURI+"/"+name)
+			require.NoError(t, err)
+			assert.Equal(t, want, *metadata, name)
+		}
+	})
+
+	t.Run("Export", func(t *testing.T) {
+		dstDir := filepath.Join(t.TempDir(), "out")
+		manifest, err := ExportDir(tdbCtx, dstDir, groupURI, DirExportOptions{Manifest: "manifest.json"})
+		require.NoError(t, err)
+
+		require.Len(t, manifest.Files, len(files))
+		for i, f := range manifest.Files {
+			if i > 0 {
+				assert.Less(t, manifest.Files[i-1].Path, f.Path)
+			}
+			contents, ok := files[f.Path]
+			require.True(t, ok, f.Path)
+			sum := sha256.Sum256([]byte(contents))
+			assert.Equal(t, int64(len(contents)), f.Size, f.Path)
+			assert.Equal(t, hex.EncodeToString(sum[:]), f.SHA256, f.Path)
+
+			data, err := os.ReadFile(filepath.Join(dstDir, filepath.FromSlash(f.Path)))
+			require.NoError(t, err)
+			assert.Equal(t, contents, string(data), f.Path)
+		}
+
+		info, err := os.Stat(filepath.Join(dstDir, "emptydir"))
+		require.NoError(t, err)
+		assert.True(t, info.IsDir())
+
+		data, err := os.ReadFile(filepath.Join(dstDir, "manifest.json"))
+		require.NoError(t, err)
+		var written DirManifest
+		require.NoError(t, json.Unmarshal(data, &written))
+		assert.Equal(t, *manifest, written)
+	})
+}
@@ -10,6 +10,9 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
 	"runtime"
 	"unsafe"
 )
@@ -107,12 +110,13 @@ type File struct {
 	arrayURI  string   // the uri of the array
 	arraySize int64    // the size of the array as returned by FileSize
 	bytesRead int64    // the total bytes read so far. Used as an offset for read operations
+	timestamp uint64   // the timestamp the array is read at, 0 for the latest version
 }
 
 // Read satisfies io.Reader.
 func (f *File) Read(p []byte) (n int, err error) {
 	bytesRemaining := f.arraySize - f.bytesRead
-	if bytesRemaining == 0 {
+	if bytesRemaining <= 0 {
 		return 0, io.EOF
 	}
 	if len(p) == 0 {
@@ -122,9 +126,7 @@ func (f *File) Read(p []byte) (n int, err error) {
 		p = p[0:bytesRemaining]
 	}
 
-	cArrayURI := C.CString(f.arrayURI)
-	defer C.free(unsafe.Pointer(cArrayURI))
-	if err := bufferExport(f.tdbCtx, cArrayURI, f.bytesRead, p); err != nil {
+	if err := f.export(f.bytesRead, p); err != nil {
 		return 0, err
 	}
 	f.bytesRead += int64(len(p))
@@ -132,6 +134,132 @@ func (f *File) Read(p []byte) (n int, err error) {
 	return len(p), nil
 }
 
+// ReadAt satisfies io.ReaderAt. It does not change the offset of Read.
+func (f *File) ReadAt(p []byte, off int64) (n int, err error) {
+	if off < 0 {
+		return 0, errors.New("offset cannot be negative")
+	}
+	if off >= f.arraySize {
+		return 0, io.EOF
+	}
+	if int64(len(p)) > f.arraySize-off {
+		p = p[0 : f.arraySize-off]
+		err = io.EOF
+	}
+
+	if err := f.export(off, p); err != nil {
+		return 0, err
+	}
+
+	return len(p), err
+}
+
+// export reads len(p) bytes of the file into p starting at offset off.
+func (f *File) export(off int64, p []byte) error {
+	if f.timestamp == 0 {
+		cArrayURI := C.CString(f.arrayURI)
+		defer C.free(unsafe.Pointer(cArrayURI))
+		return bufferExport(f.tdbCtx, cArrayURI, off, p)
+	}
+
+	// The filestore API only reads the latest version, older ones are read with a query.
+	if len(p) == 0 {
+		return nil
+	}
+	array, err := NewArray(f.tdbCtx, f.arrayURI)
+	if err != nil {
+		return err
+	}
+	defer array.Free()
+	if err := array.OpenWithOptions(TILEDB_READ, WithEndTimestamp(f.timestamp)); err != nil {
+		return err
+	}
+	defer array.Close()
+
+	return exportArray(array, off, p)
+}
+
+// exportArray reads len(p) bytes of the filestore array, open for reading, into p starting at offset off.
+func exportArray(array *Array, off int64, p []byte) error {
+	if len(p) == 0 {
+		return nil
+	}
+
+	query, err := NewQuery(array.context, array)
+	if err != nil {
+		return err
+	}
+	defer query.Free()
+	if err := query.SetLayout(TILEDB_ROW_MAJOR); err != nil {
+		return err
+	}
+	subarray, err := array.NewSubarray()
+	if err != nil {
+		return err
+	}
+	defer subarray.Free()
+	if err := subarray.AddRange(0, MakeRange(uint64(off), uint64(off)+uint64(len(p))-1)); err != nil {
+		return err
+	}
+	if err := query.SetSubarray(subarray); err != nil {
+		return err
+	}
+	if _, err := query.SetDataBuffer(fileAttribute, p); err != nil {
+		return err
+	}
+	if err := query.Submit(); err != nil {
+		return fmt.Errorf("error exporting buffer data: %w", err)
+	}
+	status, err := query.Status()
+	if err != nil {
+		return err
+	}
+	if status != TILEDB_COMPLETED {
+		return fmt.Errorf("error exporting buffer data: query status %s", status)
+	}
+
+	return nil
+}
+
+// Seek satisfies io.Seeker. Seeking beyond the end of the file is allowed, Read then returns io.EOF.
+func (f *File) Seek(offset int64, whence int) (int64, error) {
+	var origin int64
+	switch whence {
+	case io.SeekStart:
+		origin = 0
+	case io.SeekCurrent:
+		origin = f.bytesRead
+	case io.SeekEnd:
+		origin = f.arraySize
+	default:
+		return 0, errors.New("unknown seek whence")
+	}
+	if origin+offset < 0 {
+		return 0, errors.New("invalid offset, attempt to move before start of file")
+	}
+
+	f.bytesRead = origin + offset
+	return f.bytesRead, nil
+}
+
+// Size returns the size of the file.
+func (f *File) Size() int64 {
+	return f.arraySize
+}
+
+// Timestamp returns the timestamp the file is read at, 0 for the latest version.
+func (f *File) Timestamp() uint64 {
+	return f.timestamp
+}
+
+// Metadata returns the metadata of the file.
+func (f *File) Metadata() (*FileMetadata, error) {
+	if f.timestamp == 0 {
+		return ReadFileMetadata(f.tdbCtx, f.arrayURI)
+	}
+	return readFileMetadata(f.tdbCtx, f.arrayURI, WithEndTimestamp(f.timestamp))
+}
+
 // OpenFile opens for reading the array at arrayURI, which should have a filestore schema.
 func OpenFile(tdbCtx *Context, arrayURI string) (*File, error) {
 	siz, err := FileSize(tdbCtx, arrayURI)
@@ -145,6 +273,26 @@ func OpenFile(tdbCtx *Context, arrayURI string) (*File, error) {
 	}, nil
 }
 
+// OpenFileAt opens for reading the version at timestamp of the array at arrayURI, which should have a
+// filestore schema: the file as it was after the writes up to timestamp, in milliseconds since the Unix epoch.
+// A zero timestamp opens the latest version like OpenFile.
+func OpenFileAt(tdbCtx *Context, arrayURI string, timestamp uint64) (*File, error) {
+	if timestamp == 0 {
+		return OpenFile(tdbCtx, arrayURI)
+	}
+
+	metadata, err := readFileMetadata(tdbCtx, arrayURI, WithEndTimestamp(timestamp))
+	if err != nil {
+		return nil, err
+	}
+	return &File{
+		tdbCtx:    tdbCtx,
+		arrayURI:  arrayURI,
+		arraySize: metadata.Size,
+		timestamp: timestamp,
+	}, nil
+}
+
 // NewArraySchemaForFile allocates a new ArraySchema optimized for the storage of file.
 // An empty path returns a general schema suitable for any file.
 func NewArraySchemaForFile(tdbCtx *Context, filePath string) (*ArraySchema, error) {
@@ -196,3 +344,300 @@ func bufferImport(tdbCtx *Context, uri *C.char, data []byte, mimeType FileStoreM
 
 	return nil
 }
+
+// String returns the MIME type, e.g. "application/pdf".
+func (m FileStoreMimeType) String() string {
+	var cname *C.char
+	C.tiledb_mime_type_to_str(C.tiledb_mime_type_t(m), &cname)
+	return C.GoString(cname)
+}
+
+// Metadata keys of filestore arrays, as written by the core.
+const (
+	fileMetadataSize         = "file_size"
+	fileMetadataMimeType     = "mime_type"
+	fileMetadataMimeEncoding = "mime_encoding"
+	fileMetadataFileName     = "original_file_name"
+	fileMetadataExtension    = "file_extension"
+)
+
+// FileMetadata is the metadata of a filestore array. Values that were not recorded are empty.
+type FileMetadata struct {
+	// Size is the size of the file.
+	Size int64
+	// MimeType is the MIME type of the file, e.g. "application/pdf".
+	MimeType string
+	// MimeEncoding is the encoding of the file, e.g. "binary" or "utf-8".
+	MimeEncoding string
+	// OriginalFileName is the name of the imported file, e.g. "report.pdf".
+	OriginalFileName string
+	// FileExtension is the extension of the imported file, e.g. ".pdf".
+	FileExtension string
+}
+
+// ReadFileMetadata reads the metadata of the array at arrayURI, which should have a filestore schema.
+func ReadFileMetadata(tdbCtx *Context, arrayURI string) (*FileMetadata, error) {
+	return readFileMetadata(tdbCtx, arrayURI)
+}
+
+// readFileMetadata reads the metadata of the filestore array opened with opts.
+func readFileMetadata(tdbCtx *Context, arrayURI string, opts ...ArrayOpenOption) (*FileMetadata, error) {
+	array, err := NewArray(tdbCtx, arrayURI)
+	if err != nil {
+		return nil, err
+	}
+	defer array.Free()
+	if err := array.OpenWithOptions(TILEDB_READ, opts...); err != nil {
+		return nil, err
+	}
+	defer array.Close()
+
+	return fileMetadata(array)
+}
+
+// fileMetadata reads the metadata of the filestore array, open for reading.
+func fileMetadata(array *Array) (*FileMetadata, error) {
+	metadata, err := array.GetMetadataMap()
+	if err != nil {
+		return nil, fmt.Errorf("error reading file metadata: %w", err)
+	}
+
+	var fm FileMetadata
+	if size, ok := metadata[fileMetadataSize]; ok {
+		if s, ok := size.Value.(uint64); ok {
+			fm.Size = int64(s)
+		}
+	}
+	for key, value := range map[string]*string{
+		fileMetadataMimeType:     &fm.MimeType,
+		fileMetadataMimeEncoding: &fm.MimeEncoding,
+		fileMetadataFileName:     &fm.OriginalFileName,
+		fileMetadataExtension:    &fm.FileExtension,
+	} {
+		if m, ok := metadata[key]; ok {
+			*value, _ = m.Value.(string)
+		}
+	}
+
+	return &fm, nil
+}
+
+// DefaultFileChunkSize is the size of the chunks written by FileWriter
+// when FileWriterOptions.ChunkSize is zero: 64 MiB.
+const DefaultFileChunkSize = 64 << 20
+
+// fileAttribute is the attribute of filestore arrays that holds the contents of the file.
+const fileAttribute = "contents"
+
+// FileWriterOptions configures CreateFileWriter.
+type FileWriterOptions struct {
+	// FileName is recorded as the original file name of the file, and its extension as the file extension.
+	FileName string
+	// ChunkSize is the size of the writes. Each chunk is written in its own fragment.
+	// If zero, DefaultFileChunkSize is used.
+	ChunkSize int
+	// Consolidate consolidates the fragments of the chunks when the writer is closed.
+	Consolidate bool
+}
+
+/*
+FileWriter streams the contents of a file to a filestore array, so that files larger than the memory
+can be imported. It satisfies io.WriteCloser: the data is buffered and written in chunks of
+FileWriterOptions.ChunkSize bytes, and Close writes the last chunk and the metadata of the file.
+The file cannot be read until the writer is closed.
+
+Example:
+
+	w, err := tiledb.CreateFileWriter(tdbCtx, arrayURI, tiledb.TILEDB_MIME_AUTODETECT, tiledb.FileWriterOptions{FileName: "data.csv"})
+	...
+	if _, err := io.Copy(w, r); err != nil {
+		...
+	}
+	err = w.Close()
+*/
+type FileWriter struct {
+	tdbCtx   *Context
+	array    *Array
+	mimeType FileStoreMimeType
+	opts     FileWriterOptions
+
+	buf     []byte
+	written int64 // the bytes written to the array so far
+	sniff   []byte
+	err     error
+	closed  bool
+}
+
+// CreateFileWriter creates at arrayURI a TileDB array with the filestore schema and returns a writer of its contents.
+// With TILEDB_MIME_AUTODETECT, the MIME type is detected from the first 512 bytes with http.DetectContentType,
+// and the charset it reports is the MIME encoding, "binary" otherwise. An empty file is "application/octet-stream".
+// The imports of the core, such as ImportFile and CreateAndImportFile, detect it with libmagic instead,
+// so the same file can get a different MIME type and encoding, e.g. "us-ascii" instead of "utf-8".
+func CreateFileWriter(tdbCtx *Context, arrayURI string, mimeType FileStoreMimeType, opts FileWriterOptions) (*FileWriter, error) {
+	schema, err := NewArraySchemaForFile(tdbCtx, "")
+	if err != nil {
+		return nil, err
+	}
+	defer schema.Free()
+
+	if err := CreateArray(tdbCtx, arrayURI, schema); err != nil {
+		return nil, err
+	}
+
+	array, err := NewArray(tdbCtx, arrayURI)
+	if err != nil {
+		return nil, err
+	}
+	if err := array.Open(TILEDB_WRITE); err != nil {
+		array.Free()
+		return nil, err
+	}
+
+	if opts.ChunkSize <= 0 {
+		opts.ChunkSize = DefaultFileChunkSize
+	}
+	return &FileWriter{
+		tdbCtx:   tdbCtx,
+		array:    array,
+		mimeType: mimeType,
+		opts:     opts,
+		buf:      make([]byte, 0, opts.ChunkSize),
+	}, nil
+}
+
+// Write satisfies io.Writer.
+func (w *FileWriter) Write(p []byte) (int, error) {
+	if w.closed {
+		return 0, errors.New("error writing file: writer is closed")
+	}
+	if w.err != nil {
+		return 0, w.err
+	}
+
+	if len(w.sniff) < sniffLen {
+		w.sniff = append(w.sniff, p[:min(len(p), sniffLen-len(w.sniff))]...)
+	}
+
+	n := 0
+	for n < len(p) {
+		chunk := min(len(p)-n, cap(w.buf)-len(w.buf))
+		w.buf = append(w.buf, p[n:n+chunk]...)
+		n += chunk
+		if len(w.buf) == cap(w.buf) {
+			if w.err = w.flush(); w.err != nil {
+				return n, w.err
+			}
+		}
+	}
+
+	return n, nil
+}
+
+// sniffLen is the number of bytes used by http.DetectContentType.
+const sniffLen = 512
+
+// flush writes the buffered data after the data written so far.
+func (w *FileWriter) flush() error {
+	if len(w.buf) == 0 {
+		return nil
+	}
+
+	query, err := NewQuery(w.tdbCtx, w.array)
+	if err != nil {
+		return err
+	}
+	defer query.Free()
+
+	if err := query.SetLayout(TILEDB_ROW_MAJOR); err != nil {
+		return err
+	}
+	subarray, err := w.array.NewSubarray()
+	if err != nil {
+		return err
+	}
+	defer subarray.Free()
+	if err := subarray.AddRange(0, MakeRange(uint64(w.written), uint64(w.written)+uint64(len(w.buf))-1)); err != nil {
+		return err
+	}
+	if err := query.SetSubarray(subarray); err != nil {
+		return err
+	}
+	if _, err := query.SetDataBuffer(fileAttribute, w.buf); err != nil {
+		return err
+	}
+	if err := query.Submit(); err != nil {
+		return fmt.Errorf("error writing file chunk: %w", err)
+	}
+	if err := query.Finalize(); err != nil {
+		return fmt.Errorf("error writing file chunk: %w", err)
+	}
+
+	w.written += int64(len(w.buf))
+	w.buf = w.buf[:0]
+	return nil
+}
+
+// Close writes the buffered data and the metadata of the file, and closes the array.
+// If FileWriterOptions.Consolidate is set, the array is consolidated.
+func (w *FileWriter) Close() error {
+	if w.closed {
+		return w.err
+	}
+	w.closed = true
+	defer w.array.Free()
+
+	if w.err == nil {
+		w.err = w.flush()
+	}
+	if w.err == nil {
+		w.err = w.putMetadata()
+	}
+	if err := w.array.Close(); err != nil && w.err == nil {
+		w.err = err
+	}
+	if w.err == nil && w.opts.Consolidate {
+		config, err := w.tdbCtx.Config()
+		if err != nil {
+			w.err = err
+		} else {
+			w.err = ConsolidateArray(w.tdbCtx, w.array.uri, config)
+		}
+	}
+
+	return w.err
+}
+
+// putMetadata writes the metadata of the file like the imports of the core.
+func (w *FileWriter) putMetadata() error {
+	mimeType, encoding := w.mimeType.String(), "binary"
+	if w.mimeType == TILEDB_MIME_AUTODETECT {
+		mimeType, encoding = "application/octet-stream", "binary"
+		if len(w.sniff) > 0 {
+			mimeType = http.DetectContentType(w.sniff)
+		}
+		if typ, params, err := mime.ParseMediaType(mimeType); err == nil {
+			mimeType = typ
+			if charset, ok := params["charset"]; ok {
+				encoding = charset
+			}
+		}
+	}
+
+	metadata := map[string]any{
+		fileMetadataSize:         uint64(w.written),
+		fileMetadataMimeType:     mimeType,
+		fileMetadataMimeEncoding: encoding,
+	}
+	if w.opts.FileName != "" {
+		metadata[fileMetadataFileName] = filepath.Base(w.opts.FileName)
+		if ext := filepath.Ext(w.opts.FileName); ext != "" {
+			metadata[fileMetadataExtension] = ext
+		}
+	}
+	for key, value := range metadata {
+		if err := w.array.PutMetadata(key, value); err != nil {
+			return fmt.Errorf("error writing file metadata: %w", err)
+		}
+	}
+	return nil
+}
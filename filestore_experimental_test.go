@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"testing/iotest"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -202,6 +203,84 @@ func TestFileReader(t *testing.T) {
 	require.Equal(t, sha1.New().Sum(fileData), sha1.New().Sum(sink.Bytes()))
 }
 
+func TestFileReaderAtSeeker(t *testing.T) {
+	tempDir := t.TempDir()
+
+	importedFile := "testdata/VLDB17_TileDB_Page1.pdf"
+	fileArrayURI := "file://" + filepath.Join(tempDir, "array")
+	createFilestoreArrayWithContents(t, fileArrayURI, importedFile, TILEDB_MIME_PDF)
+	fileData, err := os.ReadFile(importedFile)
+	require.NoError(t, err)
+
+	tdbCtx, err := NewContext(nil)
+	require.NoError(t, err)
+	r, err := OpenFile(tdbCtx, fileArrayURI)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(fileData)), r.Size())
+	require.NoError(t, iotest.TestReader(r, fileData))
+
+	metadata, err := r.Metadata()
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(fileData)), metadata.Size)
+	assert.Equal(t, "application/pdf", metadata.MimeType)
+	assert.Equal(t, "VLDB17_TileDB_Page1.pdf", metadata.OriginalFileName)
+}
+
+func TestFileWriter(t *testing.T) {
+	tempDir := t.TempDir()
+	tdbCtx, err := NewContext(nil)
+	require.NoError(t, err)
+
+	fileData, err := os.ReadFile("testdata/VLDB17_TileDB_Page1.pdf")
+	require.NoError(t, err)
+
+	// The file is written in several chunks
+	arrayURI := "file://" + filepath.Join(tempDir, "array")
+	w, err := CreateFileWriter(tdbCtx, arrayURI, TILEDB_MIME_AUTODETECT, FileWriterOptions{FileName: "dir/page.pdf", ChunkSize: len(fileData) / 3, Consolidate: true})
+	require.NoError(t, err)
+	_, err = io.Copy(w, iotest.OneByteReader(bytes.NewReader(fileData[:100])))
+	require.NoError(t, err)
+	_, err = w.Write(fileData[100:])
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	require.NoError(t, w.Close())
+	_, err = w.Write([]byte("more"))
+	assert.Error(t, err)
+
+	n, err := FileSize(tdbCtx, arrayURI)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(fileData)), n)
+
+	exportedFile := filepath.Join(tempDir, "exported")
+	require.NoError(t, ExportFile(tdbCtx, exportedFile, arrayURI))
+	exportedData, err := os.ReadFile(exportedFile)
+	require.NoError(t, err)
+	assert.Equal(t, fileData, exportedData)
+
+	metadata, err := ReadFileMetadata(tdbCtx, arrayURI)
+	require.NoError(t, err)
+	assert.Equal(t, &FileMetadata{
+		Size:             int64(len(fileData)),
+		MimeType:         "application/pdf",
+		MimeEncoding:     "binary",
+		OriginalFileName: "page.pdf",
+		FileExtension:    ".pdf",
+	}, metadata)
+
+	t.Run("Text", func(t *testing.T) {
+		arrayURI := "file://" + filepath.Join(tempDir, "text")
+		w, err := CreateFileWriter(tdbCtx, arrayURI, TILEDB_MIME_AUTODETECT, FileWriterOptions{})
+		require.NoError(t, err)
+		_, err = w.Write([]byte("some text"))
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+
+		metadata, err := ReadFileMetadata(tdbCtx, arrayURI)
+		require.NoError(t, err)
+		assert.Equal(t, &FileMetadata{Size: 9, MimeType: "text/plain", MimeEncoding: "utf-8"}, metadata)
+	})
+}
+
 func assertEqualArraySizeAndFileSize(t *testing.T, arrayURI, filePath string) {
 	tdbCtx, err := NewContext(nil)
 	require.NoError(t, err)
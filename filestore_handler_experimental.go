@@ -0,0 +1,225 @@
+package tiledb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+FileHandler is an http.Handler that serves filestore arrays. The array of a request is given by ArrayURI,
+and its content is served with http.ServeContent, which handles byte-range and conditional requests.
+
+The ETag and the Last-Modified time of a response are those of the latest fragment of the array,
+and the file is read from the array opened once at the timestamp of that fragment, 4 MiB at a time.
+Reading stops when the request context is done.
+The Content-Type is the MIME type stored in the metadata of the file. The at query parameter,
+a timestamp in milliseconds since the Unix epoch, serves the version of the file at that time:
+
+	GET /reports/q3.pdf?at=1700000000000
+*/
+type FileHandler struct {
+	// Context is the context used to read the arrays.
+	Context *Context
+	// ArrayURI returns the URI of the array of the request. If it returns an error, the request fails with 404 Not Found.
+	ArrayURI func(r *http.Request) (string, error)
+}
+
+// FileServer returns a handler that serves the filestore arrays under root, e.g. "s3://bucket/files":
+// the array of a request is the cleaned URL path joined to root.
+// To serve them under a path prefix, use http.StripPrefix.
+func FileServer(tdbCtx *Context, root string) *FileHandler {
+	root = strings.TrimSuffix(root, "/")
+	return &FileHandler{
+		Context: tdbCtx,
+		ArrayURI: func(r *http.Request) (string, error) {
+			name := strings.TrimPrefix(path.Clean("/"+r.URL.Path), "/")
+			if name == "" {
+				return "", errors.New("no array in request path")
+			}
+			return root + "/" + name, nil
+		},
+	}
+}
+
+// ServeHTTP serves the filestore array of the request.
+func (h *FileHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	var at uint64
+	if s := r.URL.Query().Get("at"); s != "" {
+		var err error
+		if at, err = strconv.ParseUint(s, 10, 64); err != nil || at == 0 {
+			http.Error(w, "invalid at timestamp", http.StatusBadRequest)
+			return
+		}
+	}
+
+	uri, err := h.ArrayURI(r)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	objectType, err := ObjectType(h.Context, uri)
+	if err != nil || objectType != TILEDB_ARRAY {
+		http.NotFound(w, r)
+		return
+	}
+
+	timestamp, err := latestFragmentTimestamp(h.Context, uri, at)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	if at != 0 && timestamp == 0 {
+		// The file did not exist yet
+		http.NotFound(w, r)
+		return
+	}
+
+	if r.Context().Err() != nil {
+		// The client is gone
+		return
+	}
+	// Read the version the ETag is computed from, even if the array was written to meanwhile.
+	array, err := NewArray(h.Context, uri)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	defer array.Free()
+	var opts []ArrayOpenOption
+	if timestamp != 0 {
+		opts = append(opts, WithEndTimestamp(timestamp))
+	}
+	if err := array.OpenWithOptions(TILEDB_READ, opts...); err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	defer array.Close()
+	metadata, err := fileMetadata(array)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	if metadata.MimeType != "" {
+		contentType := metadata.MimeType
+		if strings.HasPrefix(contentType, "text/") && metadata.MimeEncoding != "" && metadata.MimeEncoding != "binary" {
+			contentType += "; charset=" + metadata.MimeEncoding
+		}
+		w.Header().Set("Content-Type", contentType)
+	}
+	w.Header().Set("ETag", fmt.Sprintf(`"%x-%x"`, timestamp, metadata.Size))
+
+	var modtime time.Time
+	if timestamp != 0 {
+		modtime = time.UnixMilli(int64(timestamp))
+	}
+	// The name is only used to guess the Content-Type if the metadata has none.
+	http.ServeContent(w, r, metadata.OriginalFileName, modtime, &fileReader{ctx: r.Context(), array: array, size: metadata.Size})
+}
+
+// fileReadSize is the size of the reads of the files served by FileHandler.
+const fileReadSize = 4 << 20
+
+/*
+fileReader reads the file of a filestore array open for reading. It reads fileReadSize bytes
+at a time, so that the small reads of http.ServeContent do not each run a query, and its reads
+fail once ctx is done, so that a response stops being read when its client goes away.
+*/
+type fileReader struct {
+	ctx   context.Context
+	array *Array
+	size  int64
+	off   int64 // the offset of the next read
+
+	buf    []byte // the data read from bufOff
+	bufOff int64
+}
+
+// Read satisfies io.Reader.
+func (f *fileReader) Read(p []byte) (int, error) {
+	if err := f.ctx.Err(); err != nil {
+		return 0, err
+	}
+	if f.off >= f.size {
+		return 0, io.EOF
+	}
+
+	if f.off < f.bufOff || f.off >= f.bufOff+int64(len(f.buf)) {
+		if f.buf == nil {
+			f.buf = make([]byte, min(f.size, fileReadSize))
+		}
+		f.buf = f.buf[:min(f.size-f.off, int64(cap(f.buf)))]
+		f.bufOff = f.off
+		if err := exportArray(f.array, f.off, f.buf); err != nil {
+			f.buf = f.buf[:0]
+			return 0, err
+		}
+	}
+
+	n := copy(p, f.buf[f.off-f.bufOff:])
+	f.off += int64(n)
+	return n, nil
+}
+
+// Seek satisfies io.Seeker.
+func (f *fileReader) Seek(offset int64, whence int) (int64, error) {
+	var origin int64
+	switch whence {
+	case io.SeekStart:
+		origin = 0
+	case io.SeekCurrent:
+		origin = f.off
+	case io.SeekEnd:
+		origin = f.size
+	default:
+		return 0, errors.New("unknown seek whence")
+	}
+	if origin+offset < 0 {
+		return 0, errors.New("invalid offset, attempt to move before start of file")
+	}
+
+	f.off = origin + offset
+	return f.off, nil
+}
+
+// latestFragmentTimestamp returns the latest end timestamp of the fragments of the array at uri,
+// up to at if it is not zero. It returns 0 if there are no such fragments.
+func latestFragmentTimestamp(tdbCtx *Context, uri string, at uint64) (uint64, error) {
+	fragmentInfo, err := NewFragmentInfo(tdbCtx, uri)
+	if err != nil {
+		return 0, err
+	}
+	defer fragmentInfo.Free()
+	if err := fragmentInfo.Load(); err != nil {
+		return 0, err
+	}
+
+	num, err := fragmentInfo.GetFragmentNum()
+	if err != nil {
+		return 0, err
+	}
+	var latest uint64
+	for fid := uint32(0); fid < num; fid++ {
+		_, end, err := fragmentInfo.GetTimestampRange(fid)
+		if err != nil {
+			return 0, err
+		}
+		if (at == 0 || end <= at) && end > latest {
+			latest = end
+		}
+	}
+	return latest, nil
+}
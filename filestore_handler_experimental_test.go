@@ -0,0 +1,143 @@
+package tiledb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileServer(t *testing.T) {
+	tempDir := t.TempDir()
+	tdbCtx, err := NewContext(nil)
+	require.NoError(t, err)
+
+	root := "file://" + tempDir
+	arrayURI := root + "/doc"
+	v1 := "the first version of the document"
+	w, err := CreateFileWriter(tdbCtx, arrayURI, TILEDB_MIME_AUTODETECT, FileWriterOptions{FileName: "doc.txt"})
+	require.NoError(t, err)
+	_, err = w.Write([]byte(v1))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	t1, err := latestFragmentTimestamp(tdbCtx, arrayURI, 0)
+	require.NoError(t, err)
+
+	time.Sleep(5 * time.Millisecond)
+	v2 := "the second version"
+	v2Path := filepath.Join(tempDir, "v2.txt")
+	require.NoError(t, os.WriteFile(v2Path, []byte(v2), 0o644))
+	require.NoError(t, ImportFile(tdbCtx, arrayURI, v2Path, TILEDB_MIME_PDF))
+
+	server := httptest.NewServer(http.StripPrefix("/files", FileServer(tdbCtx, root)))
+	defer server.Close()
+
+	get := func(t *testing.T, url string, header http.Header) (*http.Response, string) {
+		req, err := http.NewRequest(http.MethodGet, server.URL+url, nil)
+		require.NoError(t, err)
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp, string(body)
+	}
+
+	resp, body := get(t, "/files/doc", nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, v2, body)
+	assert.Equal(t, "application/pdf", resp.Header.Get("Content-Type"))
+	etag := resp.Header.Get("ETag")
+	assert.NotEmpty(t, etag)
+	assert.Equal(t, "bytes", resp.Header.Get("Accept-Ranges"))
+
+	resp, body = get(t, "/files/doc", http.Header{"Range": {"bytes=4-9"}})
+	assert.Equal(t, http.StatusPartialContent, resp.StatusCode)
+	assert.Equal(t, v2[4:10], body)
+
+	resp, _ = get(t, "/files/doc", http.Header{"If-None-Match": {etag}})
+	assert.Equal(t, http.StatusNotModified, resp.StatusCode)
+
+	resp, body = get(t, fmt.Sprintf("/files/doc?at=%d", t1), nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, v1, body)
+	assert.Equal(t, "text/plain; charset=utf-8", resp.Header.Get("Content-Type"))
+	assert.NotEqual(t, etag, resp.Header.Get("ETag"))
+
+	resp, body = get(t, fmt.Sprintf("/files/doc?at=%d", t1), http.Header{"Range": {"bytes=-7"}})
+	assert.Equal(t, http.StatusPartialContent, resp.StatusCode)
+	assert.Equal(t, v1[len(v1)-7:], body)
+
+	for url, status := range map[string]int{
+		"/files/missing":   http.StatusNotFound,
+		"/files/":          http.StatusNotFound,
+		"/files/doc?at=1":  http.StatusNotFound,
+		"/files/doc?at=x":  http.StatusBadRequest,
+		"/files/doc/extra": http.StatusNotFound,
+	} {
+		resp, _ := get(t, url, nil)
+		assert.Equal(t, status, resp.StatusCode, url)
+	}
+
+	resp, err = http.Post(server.URL+"/files/doc", "text/plain", nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+
+	t.Run("Canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		req := httptest.NewRequest(http.MethodGet, "/doc", nil).WithContext(ctx)
+		rec := httptest.NewRecorder()
+		FileServer(tdbCtx, root).ServeHTTP(rec, req)
+		assert.Empty(t, rec.Body.String())
+
+		array, err := NewArray(tdbCtx, arrayURI)
+		require.NoError(t, err)
+		defer array.Free()
+		require.NoError(t, array.Open(TILEDB_READ))
+		defer array.Close()
+		_, err = (&fileReader{ctx: ctx, array: array, size: int64(len(v2))}).Read(make([]byte, 4))
+		assert.True(t, errors.Is(err, context.Canceled))
+	})
+
+	t.Run("Reader", func(t *testing.T) {
+		array, err := NewArray(tdbCtx, arrayURI)
+		require.NoError(t, err)
+		defer array.Free()
+		require.NoError(t, array.OpenWithOptions(TILEDB_READ, WithEndTimestamp(t1)))
+		defer array.Close()
+
+		// Small reads are served from the data read by the first one
+		r := &fileReader{ctx: context.Background(), array: array, size: int64(len(v1))}
+		data, err := io.ReadAll(iotest.OneByteReader(r))
+		require.NoError(t, err)
+		assert.Equal(t, v1, string(data))
+		assert.Equal(t, int64(0), r.bufOff)
+
+		_, err = r.Seek(-8, io.SeekEnd)
+		require.NoError(t, err)
+		data, err = io.ReadAll(r)
+		require.NoError(t, err)
+		assert.Equal(t, v1[len(v1)-8:], string(data))
+		_, err = r.Seek(4, io.SeekStart)
+		require.NoError(t, err)
+		data = make([]byte, 5)
+		_, err = io.ReadFull(r, data)
+		require.NoError(t, err)
+		assert.Equal(t, v1[4:9], string(data))
+	})
+}
@@ -78,82 +78,146 @@ func (f *Filter) Type() (FilterType, error) {
 
 // SetOption sets an option on a filter. Options are filter dependent;
 // this function returns an error if the given option is not valid for the
-// given filter.
+// given filter. The value must have the Go type of the option:
+//   - int32 for TILEDB_COMPRESSION_LEVEL
+//   - uint32 for TILEDB_BIT_WIDTH_MAX_WINDOW and TILEDB_POSITIVE_DELTA_MAX_WINDOW
+//   - uint64 for TILEDB_SCALE_FLOAT_BYTEWIDTH
+//   - float64 for TILEDB_SCALE_FLOAT_FACTOR and TILEDB_SCALE_FLOAT_OFFSET
+//   - float32 for TILEDB_WEBP_QUALITY
+//   - WebPFormat for TILEDB_WEBP_INPUT_FORMAT
+//   - bool for TILEDB_WEBP_LOSSLESS
+//   - Datatype for TILEDB_COMPRESSION_REINTERPRET_DATATYPE
+//
+// Other options are ignored.
 func (f *Filter) SetOption(filterOption FilterOption, valueInterface interface{}) error {
-	var cvalue unsafe.Pointer
-
 	switch filterOption {
 	case TILEDB_COMPRESSION_LEVEL:
-		value, ok := valueInterface.(int32)
-		if !ok {
-			return errors.New("error setting tiledb filter option TILEDB_COMPRESSION_LEVEL, passed data is not int32")
-		}
-		cvalue = unsafe.Pointer(&value)
-		ret := C.tiledb_filter_set_option(f.context.tiledbContext.Get(), f.tiledbFilter.Get(), C.tiledb_filter_option_t(filterOption), cvalue)
-		if ret != C.TILEDB_OK {
-			return fmt.Errorf("error setting tiledb filter option: %w", f.context.LastError())
-		}
+		return setFilterOption[int32](f, filterOption, "TILEDB_COMPRESSION_LEVEL", valueInterface)
 	case TILEDB_BIT_WIDTH_MAX_WINDOW:
-		value, ok := valueInterface.(uint32)
+		return setFilterOption[uint32](f, filterOption, "TILEDB_BIT_WIDTH_MAX_WINDOW", valueInterface)
+	case TILEDB_POSITIVE_DELTA_MAX_WINDOW:
+		return setFilterOption[uint32](f, filterOption, "TILEDB_POSITIVE_DELTA_MAX_WINDOW", valueInterface)
+	case TILEDB_SCALE_FLOAT_BYTEWIDTH:
+		return setFilterOption[uint64](f, filterOption, "TILEDB_SCALE_FLOAT_BYTEWIDTH", valueInterface)
+	case TILEDB_SCALE_FLOAT_FACTOR:
+		return setFilterOption[float64](f, filterOption, "TILEDB_SCALE_FLOAT_FACTOR", valueInterface)
+	case TILEDB_SCALE_FLOAT_OFFSET:
+		return setFilterOption[float64](f, filterOption, "TILEDB_SCALE_FLOAT_OFFSET", valueInterface)
+	case TILEDB_WEBP_QUALITY:
+		return setFilterOption[float32](f, filterOption, "TILEDB_WEBP_QUALITY", valueInterface)
+	case TILEDB_WEBP_INPUT_FORMAT:
+		return setFilterOption[WebPFormat](f, filterOption, "TILEDB_WEBP_INPUT_FORMAT", valueInterface)
+	case TILEDB_WEBP_LOSSLESS:
+		value, ok := valueInterface.(bool)
 		if !ok {
-			return errors.New("error setting tiledb filter option TILEDB_BIT_WIDTH_MAX_WINDOW, passed data is not uint32")
+			return errors.New("error setting tiledb filter option TILEDB_WEBP_LOSSLESS, passed data is not bool")
 		}
-		cvalue = unsafe.Pointer(&value)
-		ret := C.tiledb_filter_set_option(f.context.tiledbContext.Get(), f.tiledbFilter.Get(), C.tiledb_filter_option_t(filterOption), cvalue)
-		if ret != C.TILEDB_OK {
-			return fmt.Errorf("error setting tiledb filter option: %w", f.context.LastError())
+		var lossless uint8
+		if value {
+			lossless = 1
 		}
-	case TILEDB_POSITIVE_DELTA_MAX_WINDOW:
-		value, ok := valueInterface.(uint32)
+		return setFilterOption[uint8](f, filterOption, "TILEDB_WEBP_LOSSLESS", lossless)
+	case TILEDB_COMPRESSION_REINTERPRET_DATATYPE:
+		value, ok := valueInterface.(Datatype)
 		if !ok {
-			return errors.New("error setting tiledb filter option TILEDB_POSITIVE_DELTA_MAX_WINDOW, passed data is not uint32")
-		}
-		cvalue = unsafe.Pointer(&value)
-		ret := C.tiledb_filter_set_option(f.context.tiledbContext.Get(), f.tiledbFilter.Get(), C.tiledb_filter_option_t(filterOption), cvalue)
-		if ret != C.TILEDB_OK {
-			return fmt.Errorf("error setting tiledb filter option: %w", f.context.LastError())
+			return errors.New("error setting tiledb filter option TILEDB_COMPRESSION_REINTERPRET_DATATYPE, passed data is not Datatype")
 		}
+		return setFilterOption[uint8](f, filterOption, "TILEDB_COMPRESSION_REINTERPRET_DATATYPE", uint8(value))
+	default:
+		// Options unknown to this package are ignored, as they always were.
+		return nil
 	}
+}
+
+// setFilterOption sets the option of type T on f. name is the name of the option for errors.
+func setFilterOption[T any](f *Filter, filterOption FilterOption, name string, valueInterface interface{}) error {
+	value, ok := valueInterface.(T)
+	if !ok {
+		return fmt.Errorf("error setting tiledb filter option %s, passed data is not %T", name, value)
+	}
+
+	ret := C.tiledb_filter_set_option(f.context.tiledbContext.Get(), f.tiledbFilter.Get(), C.tiledb_filter_option_t(filterOption), unsafe.Pointer(&value))
 	runtime.KeepAlive(f)
+	if ret != C.TILEDB_OK {
+		return fmt.Errorf("error setting tiledb filter option: %w", f.context.LastError())
+	}
 
 	return nil
 }
 
 // Option fetches the specified option set on a filter. Returns an interface{}
-// dependent on the option being fetched
+// dependent on the option being fetched, with the Go type listed in SetOption.
+// The value is nil on errors and for the options not listed in SetOption.
 // var optionValue int32
-// optionValueInterface, err := filter.Option(TILEDB_FILTER_GZIP)
+// optionValueInterface, err := filter.Option(TILEDB_COMPRESSION_LEVEL)
 // optionValue = optionValueInterface.(int32)
 func (f *Filter) Option(filterOption FilterOption) (interface{}, error) {
-
-	var cvalue unsafe.Pointer
-
 	switch filterOption {
 	case TILEDB_COMPRESSION_LEVEL:
-		var val int32
-		cvalue = unsafe.Pointer(&val)
-		ret := C.tiledb_filter_get_option(f.context.tiledbContext.Get(), f.tiledbFilter.Get(), C.tiledb_filter_option_t(filterOption), cvalue)
-		if ret != C.TILEDB_OK {
-			return nil, fmt.Errorf("error getting tiledb filter option: %w", f.context.LastError())
-		}
-		return val, nil
-	case TILEDB_BIT_WIDTH_MAX_WINDOW:
-		var val uint32
-		cvalue = unsafe.Pointer(&val)
-		ret := C.tiledb_filter_get_option(f.context.tiledbContext.Get(), f.tiledbFilter.Get(), C.tiledb_filter_option_t(filterOption), cvalue)
-		if ret != C.TILEDB_OK {
-			return nil, fmt.Errorf("error getting tiledb filter option: %w", f.context.LastError())
+		return optionValue(getFilterOption[int32](f, filterOption))
+	case TILEDB_BIT_WIDTH_MAX_WINDOW, TILEDB_POSITIVE_DELTA_MAX_WINDOW:
+		return optionValue(getFilterOption[uint32](f, filterOption))
+	case TILEDB_SCALE_FLOAT_BYTEWIDTH:
+		return optionValue(getFilterOption[uint64](f, filterOption))
+	case TILEDB_SCALE_FLOAT_FACTOR, TILEDB_SCALE_FLOAT_OFFSET:
+		return optionValue(getFilterOption[float64](f, filterOption))
+	case TILEDB_WEBP_QUALITY:
+		return optionValue(getFilterOption[float32](f, filterOption))
+	case TILEDB_WEBP_INPUT_FORMAT:
+		return optionValue(getFilterOption[WebPFormat](f, filterOption))
+	case TILEDB_WEBP_LOSSLESS:
+		value, err := getFilterOption[uint8](f, filterOption)
+		if err != nil {
+			return nil, err
 		}
-		return val, nil
-	case TILEDB_POSITIVE_DELTA_MAX_WINDOW:
-		var val uint32
-		cvalue = unsafe.Pointer(&val)
-		ret := C.tiledb_filter_get_option(f.context.tiledbContext.Get(), f.tiledbFilter.Get(), C.tiledb_filter_option_t(filterOption), cvalue)
-		if ret != C.TILEDB_OK {
-			return nil, fmt.Errorf("error getting tiledb filter option: %w", f.context.LastError())
+		return value != 0, nil
+	case TILEDB_COMPRESSION_REINTERPRET_DATATYPE:
+		value, err := getFilterOption[uint8](f, filterOption)
+		if err != nil {
+			return nil, err
 		}
-		return val, nil
+		return Datatype(value), nil
+	default:
+		// Options unknown to this package have no value, as they always had.
+		return nil, nil
+	}
+}
+
+// optionValue returns value, or nil if err is not nil, so that Option returns a nil value on errors.
+func optionValue[T any](value T, err error) (interface{}, error) {
+	if err != nil {
+		return nil, err
 	}
+	return value, nil
+}
+
+// getFilterOption gets the option of type T of f.
+func getFilterOption[T any](f *Filter, filterOption FilterOption) (T, error) {
+	var value T
+	ret := C.tiledb_filter_get_option(f.context.tiledbContext.Get(), f.tiledbFilter.Get(), C.tiledb_filter_option_t(filterOption), unsafe.Pointer(&value))
 	runtime.KeepAlive(f)
-	return nil, nil
+	if ret != C.TILEDB_OK {
+		return value, fmt.Errorf("error getting tiledb filter option: %w", f.context.LastError())
+	}
+	return value, nil
+}
+
+// filterTypeOptions returns the options that apply to filters of filterType.
+func filterTypeOptions(filterType FilterType) []FilterOption {
+	switch filterType {
+	case TILEDB_FILTER_GZIP, TILEDB_FILTER_ZSTD, TILEDB_FILTER_LZ4, TILEDB_FILTER_RLE, TILEDB_FILTER_BZIP2, TILEDB_FILTER_DICTIONARY:
+		return []FilterOption{TILEDB_COMPRESSION_LEVEL}
+	case TILEDB_FILTER_DOUBLE_DELTA, TILEDB_FILTER_DELTA:
+		return []FilterOption{TILEDB_COMPRESSION_LEVEL, TILEDB_COMPRESSION_REINTERPRET_DATATYPE}
+	case TILEDB_FILTER_BIT_WIDTH_REDUCTION:
+		return []FilterOption{TILEDB_BIT_WIDTH_MAX_WINDOW}
+	case TILEDB_FILTER_POSITIVE_DELTA:
+		return []FilterOption{TILEDB_POSITIVE_DELTA_MAX_WINDOW}
+	case TILEDB_FILTER_SCALE_FLOAT:
+		return []FilterOption{TILEDB_SCALE_FLOAT_BYTEWIDTH, TILEDB_SCALE_FLOAT_FACTOR, TILEDB_SCALE_FLOAT_OFFSET}
+	case TILEDB_FILTER_WEBP:
+		return []FilterOption{TILEDB_WEBP_QUALITY, TILEDB_WEBP_INPUT_FORMAT, TILEDB_WEBP_LOSSLESS}
+	default:
+		return nil
+	}
 }
@@ -0,0 +1,108 @@
+package tiledb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterTypes(t *testing.T) {
+	context, err := NewContext(nil)
+	require.NoError(t, err)
+
+	for _, filterType := range []FilterType{
+		TILEDB_FILTER_CHECKSUM_MD5,
+		TILEDB_FILTER_CHECKSUM_SHA256,
+		TILEDB_FILTER_DICTIONARY,
+		TILEDB_FILTER_XOR,
+	} {
+		filter, err := NewFilter(context, filterType)
+		require.NoError(t, err, filterType.String())
+		gotType, err := filter.Type()
+		require.NoError(t, err)
+		assert.Equal(t, filterType, gotType)
+	}
+
+	assert.Equal(t, "CHECKSUM_SHA256", TILEDB_FILTER_CHECKSUM_SHA256.String())
+}
+
+func TestFilterOptions(t *testing.T) {
+	context, err := NewContext(nil)
+	require.NoError(t, err)
+
+	t.Run("ScaleFloat", func(t *testing.T) {
+		filter, err := NewFilter(context, TILEDB_FILTER_SCALE_FLOAT)
+		require.NoError(t, err)
+
+		require.NoError(t, filter.SetOption(TILEDB_SCALE_FLOAT_BYTEWIDTH, uint64(4)))
+		require.NoError(t, filter.SetOption(TILEDB_SCALE_FLOAT_FACTOR, 0.5))
+		require.NoError(t, filter.SetOption(TILEDB_SCALE_FLOAT_OFFSET, 10.0))
+
+		byteWidth, err := filter.Option(TILEDB_SCALE_FLOAT_BYTEWIDTH)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(4), byteWidth)
+		factor, err := filter.Option(TILEDB_SCALE_FLOAT_FACTOR)
+		require.NoError(t, err)
+		assert.Equal(t, 0.5, factor)
+		offset, err := filter.Option(TILEDB_SCALE_FLOAT_OFFSET)
+		require.NoError(t, err)
+		assert.Equal(t, 10.0, offset)
+
+		require.Error(t, filter.SetOption(TILEDB_SCALE_FLOAT_FACTOR, float32(0.5)))
+	})
+
+	t.Run("ReinterpretDatatype", func(t *testing.T) {
+		filter, err := NewFilter(context, TILEDB_FILTER_ZSTD)
+		require.NoError(t, err)
+
+		require.NoError(t, filter.SetOption(TILEDB_COMPRESSION_REINTERPRET_DATATYPE, TILEDB_UINT16))
+		datatype, err := filter.Option(TILEDB_COMPRESSION_REINTERPRET_DATATYPE)
+		require.NoError(t, err)
+		assert.Equal(t, TILEDB_UINT16, datatype)
+
+		require.Error(t, filter.SetOption(TILEDB_COMPRESSION_REINTERPRET_DATATYPE, uint8(TILEDB_UINT16)))
+	})
+
+	t.Run("WebP", func(t *testing.T) {
+		filter, err := NewFilter(context, TILEDB_FILTER_WEBP)
+		if err != nil {
+			t.Skip("TileDB is built without WebP support")
+		}
+
+		require.NoError(t, filter.SetOption(TILEDB_WEBP_QUALITY, float32(75)))
+		require.NoError(t, filter.SetOption(TILEDB_WEBP_INPUT_FORMAT, TILEDB_WEBP_RGBA))
+		require.NoError(t, filter.SetOption(TILEDB_WEBP_LOSSLESS, true))
+
+		quality, err := filter.Option(TILEDB_WEBP_QUALITY)
+		require.NoError(t, err)
+		assert.Equal(t, float32(75), quality)
+		format, err := filter.Option(TILEDB_WEBP_INPUT_FORMAT)
+		require.NoError(t, err)
+		assert.Equal(t, TILEDB_WEBP_RGBA, format)
+		lossless, err := filter.Option(TILEDB_WEBP_LOSSLESS)
+		require.NoError(t, err)
+		assert.Equal(t, true, lossless)
+	})
+
+	t.Run("InvalidOption", func(t *testing.T) {
+		filter, err := NewFilter(context, TILEDB_FILTER_GZIP)
+		require.NoError(t, err)
+
+		require.Error(t, filter.SetOption(TILEDB_SCALE_FLOAT_FACTOR, 0.5))
+		value, err := filter.Option(TILEDB_SCALE_FLOAT_FACTOR)
+		require.Error(t, err)
+		assert.Nil(t, value)
+	})
+
+	t.Run("UnknownOption", func(t *testing.T) {
+		filter, err := NewFilter(context, TILEDB_FILTER_GZIP)
+		require.NoError(t, err)
+
+		// Unknown options are ignored and have no value
+		require.NoError(t, filter.SetOption(FilterOption(255), int32(1)))
+		value, err := filter.Option(FilterOption(255))
+		require.NoError(t, err)
+		assert.Nil(t, value)
+	})
+}
@@ -8,6 +8,7 @@ import "C"
 
 import (
 	"fmt"
+	"reflect"
 	"runtime"
 	"unsafe"
 )
@@ -645,3 +646,236 @@ func (fI *FragmentInfo) Config() (*Config, error) {
 
 	return newConfigFromHandle(newConfigHandle(configPtr)), nil
 }
+
+// GetTotalCellNum retrieves the total number of cells written to all the fragments.
+// Dense fragments count the fill values like GetCellNum.
+func (fI *FragmentInfo) GetTotalCellNum() (uint64, error) {
+	var cCellNum C.uint64_t
+
+	ret := C.tiledb_fragment_info_get_total_cell_num(fI.context.tiledbContext.Get(),
+		fI.tiledbFragmentInfo.Get(), &cCellNum)
+	runtime.KeepAlive(fI)
+	if ret != C.TILEDB_OK {
+		return 0, fmt.Errorf("error retrieving total number of cells of the fragments: %w", fI.context.LastError())
+	}
+
+	return uint64(cCellNum), nil
+}
+
+// GetArraySchemaName retrieves the name of the array schema the fragment was written with.
+func (fI *FragmentInfo) GetArraySchemaName(fid uint32) (string, error) {
+	var cName *C.char // fI must be kept alive while cName is being accessed.
+
+	ret := C.tiledb_fragment_info_get_array_schema_name(fI.context.tiledbContext.Get(),
+		fI.tiledbFragmentInfo.Get(), C.uint32_t(fid), &cName)
+	if ret != C.TILEDB_OK {
+		return "", fmt.Errorf("error retrieving array schema name of fragment %d: %w", fid, fI.context.LastError())
+	}
+
+	name := C.GoString(cName)
+	runtime.KeepAlive(fI)
+	return name, nil
+}
+
+// GetArraySchema retrieves the array schema the fragment was written with.
+func (fI *FragmentInfo) GetArraySchema(fid uint32) (*ArraySchema, error) {
+	var arraySchemaPtr *C.tiledb_array_schema_t
+
+	ret := C.tiledb_fragment_info_get_array_schema(fI.context.tiledbContext.Get(),
+		fI.tiledbFragmentInfo.Get(), C.uint32_t(fid), &arraySchemaPtr)
+	runtime.KeepAlive(fI)
+	if ret != C.TILEDB_OK {
+		return nil, fmt.Errorf("error retrieving array schema of fragment %d: %w", fid, fI.context.LastError())
+	}
+
+	return newArraySchemaFromHandle(fI.context, newArraySchemaHandle(arraySchemaPtr)), nil
+}
+
+// GetMBRNum retrieves the number of minimum bounding rectangles of a sparse fragment,
+// one per data tile.
+func (fI *FragmentInfo) GetMBRNum(fid uint32) (uint64, error) {
+	var cMBRNum C.uint64_t
+
+	ret := C.tiledb_fragment_info_get_mbr_num(fI.context.tiledbContext.Get(),
+		fI.tiledbFragmentInfo.Get(), C.uint32_t(fid), &cMBRNum)
+	runtime.KeepAlive(fI)
+	if ret != C.TILEDB_OK {
+		return 0, fmt.Errorf("error retrieving number of MBRs of fragment %d: %w", fid, fI.context.LastError())
+	}
+
+	return uint64(cMBRNum), nil
+}
+
+// fragmentDimension returns the datatype and whether it is var-sized of a dimension
+// of the schema of fragment fid. The dimension is found by index if name is empty.
+func (fI *FragmentInfo) fragmentDimension(fid uint32, did uint32, name string) (Datatype, bool, error) {
+	schema, err := fI.GetArraySchema(fid)
+	if err != nil {
+		return 0, false, err
+	}
+	defer schema.Free()
+
+	domain, err := schema.Domain()
+	if err != nil {
+		return 0, false, err
+	}
+	defer domain.Free()
+
+	var dimension *Dimension
+	if name == "" {
+		dimension, err = domain.DimensionFromIndex(uint(did))
+	} else {
+		dimension, err = domain.DimensionFromName(name)
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	defer dimension.Free()
+
+	datatype, err := dimension.Type()
+	if err != nil {
+		return 0, false, err
+	}
+	cellValNum, err := dimension.CellValNum()
+	if err != nil {
+		return 0, false, err
+	}
+	return datatype, cellValNum == TILEDB_VAR_NUM, nil
+}
+
+// GetMBRFromIndex retrieves the minimum bounding rectangle mid of the sparse fragment fid
+// along the dimension with index did. mid is less than GetMBRNum(fid), and a uint32 like in the C API.
+// Ranges of var-sized dimensions have string endpoints. Use ExtractRange to get the typed endpoints.
+func (fI *FragmentInfo) GetMBRFromIndex(fid uint32, mid uint32, did uint32) (Range, error) {
+	datatype, isVar, err := fI.fragmentDimension(fid, did, "")
+	if err != nil {
+		return Range{}, err
+	}
+	return fI.mbrFromIndex(fid, mid, did, datatype, isVar)
+}
+
+// mbrFromIndex retrieves the minimum bounding rectangle mid of the sparse fragment fid
+// along the dimension with index did, of datatype and var-sized if isVar.
+func (fI *FragmentInfo) mbrFromIndex(fid uint32, mid uint32, did uint32, datatype Datatype, isVar bool) (Range, error) {
+	var r Range
+	var ret C.int32_t
+	if isVar {
+		var startSize, endSize C.uint64_t
+		ret = C.tiledb_fragment_info_get_mbr_var_size_from_index(fI.context.tiledbContext.Get(), fI.tiledbFragmentInfo.Get(),
+			C.uint32_t(fid), C.uint32_t(mid), C.uint32_t(did), &startSize, &endSize)
+		if ret == C.TILEDB_OK {
+			// One extra byte so empty endpoints have a data pointer.
+			startData := make([]byte, int(startSize)+1)
+			endData := make([]byte, int(endSize)+1)
+			ret = C.tiledb_fragment_info_get_mbr_var_from_index(fI.context.tiledbContext.Get(), fI.tiledbFragmentInfo.Get(),
+				C.uint32_t(fid), C.uint32_t(mid), C.uint32_t(did), slicePtr(startData), slicePtr(endData))
+			r = Range{start: string(startData[:startSize]), end: string(endData[:endSize])}
+		}
+	} else {
+		mbr := reflect.New(reflect.ArrayOf(2, datatype.ReflectType()))
+		ret = C.tiledb_fragment_info_get_mbr_from_index(fI.context.tiledbContext.Get(), fI.tiledbFragmentInfo.Get(),
+			C.uint32_t(fid), C.uint32_t(mid), C.uint32_t(did), mbr.UnsafePointer())
+		r = Range{start: mbr.Elem().Index(0).Interface(), end: mbr.Elem().Index(1).Interface()}
+	}
+	runtime.KeepAlive(fI)
+	if ret != C.TILEDB_OK {
+		return Range{}, fmt.Errorf("error retrieving MBR %d of fragment %d for dimension index %d: %w", mid, fid, did, fI.context.LastError())
+	}
+
+	return r, nil
+}
+
+// GetMBRFromName retrieves the minimum bounding rectangle mid of the sparse fragment fid
+// along the dimension with name did. Ranges of var-sized dimensions have string endpoints.
+// Use ExtractRange to get the typed endpoints.
+func (fI *FragmentInfo) GetMBRFromName(fid uint32, mid uint32, did string) (Range, error) {
+	datatype, isVar, err := fI.fragmentDimension(fid, 0, did)
+	if err != nil {
+		return Range{}, err
+	}
+
+	cDid := C.CString(did)
+	defer C.free(unsafe.Pointer(cDid))
+
+	var r Range
+	var ret C.int32_t
+	if isVar {
+		var startSize, endSize C.uint64_t
+		ret = C.tiledb_fragment_info_get_mbr_var_size_from_name(fI.context.tiledbContext.Get(), fI.tiledbFragmentInfo.Get(),
+			C.uint32_t(fid), C.uint32_t(mid), cDid, &startSize, &endSize)
+		if ret == C.TILEDB_OK {
+			// One extra byte so empty endpoints have a data pointer.
+			startData := make([]byte, int(startSize)+1)
+			endData := make([]byte, int(endSize)+1)
+			ret = C.tiledb_fragment_info_get_mbr_var_from_name(fI.context.tiledbContext.Get(), fI.tiledbFragmentInfo.Get(),
+				C.uint32_t(fid), C.uint32_t(mid), cDid, slicePtr(startData), slicePtr(endData))
+			r = Range{start: string(startData[:startSize]), end: string(endData[:endSize])}
+		}
+	} else {
+		mbr := reflect.New(reflect.ArrayOf(2, datatype.ReflectType()))
+		ret = C.tiledb_fragment_info_get_mbr_from_name(fI.context.tiledbContext.Get(), fI.tiledbFragmentInfo.Get(),
+			C.uint32_t(fid), C.uint32_t(mid), cDid, mbr.UnsafePointer())
+		r = Range{start: mbr.Elem().Index(0).Interface(), end: mbr.Elem().Index(1).Interface()}
+	}
+	runtime.KeepAlive(fI)
+	if ret != C.TILEDB_OK {
+		return Range{}, fmt.Errorf("error retrieving MBR %d of fragment %d for dimension name %s: %w", mid, fid, did, fI.context.LastError())
+	}
+
+	return r, nil
+}
+
+// GetMBR retrieves the minimum bounding rectangle mid of the sparse fragment fid along all the dimensions.
+// The bounds are typed like in Array.NonEmptyDomain: []T{start, end}, []string for var-sized dimensions.
+func (fI *FragmentInfo) GetMBR(fid uint32, mid uint32) ([]NonEmptyDomain, error) {
+	schema, err := fI.GetArraySchema(fid)
+	if err != nil {
+		return nil, err
+	}
+	defer schema.Free()
+
+	domain, err := schema.Domain()
+	if err != nil {
+		return nil, err
+	}
+	defer domain.Free()
+
+	nDim, err := domain.NDim()
+	if err != nil {
+		return nil, err
+	}
+
+	mbr := make([]NonEmptyDomain, 0, nDim)
+	for did := uint32(0); did < uint32(nDim); did++ {
+		dimension, err := domain.DimensionFromIndex(uint(did))
+		if err != nil {
+			return nil, err
+		}
+		name, err := dimension.Name()
+		if err != nil {
+			dimension.Free()
+			return nil, err
+		}
+		datatype, err := dimension.Type()
+		if err != nil {
+			dimension.Free()
+			return nil, err
+		}
+		cellValNum, err := dimension.CellValNum()
+		dimension.Free()
+		if err != nil {
+			return nil, err
+		}
+		r, err := fI.mbrFromIndex(fid, mid, did, datatype, cellValNum == TILEDB_VAR_NUM)
+		if err != nil {
+			return nil, err
+		}
+
+		bounds := reflect.MakeSlice(reflect.SliceOf(reflect.TypeOf(r.start)), 2, 2)
+		bounds.Index(0).Set(reflect.ValueOf(r.start))
+		bounds.Index(1).Set(reflect.ValueOf(r.end))
+		mbr = append(mbr, NonEmptyDomain{DimensionName: name, Bounds: bounds.Interface()})
+	}
+
+	return mbr, nil
+}
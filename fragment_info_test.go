@@ -166,3 +166,89 @@ func writeToArray(t testing.TB, context *Context, tmpArrayPath string) {
 	require.NoError(t, query.Submit())
 	require.NoError(t, array.Close())
 }
+
+func TestFragmentInfoMBR(t *testing.T) {
+	context, err := NewContext(nil)
+	require.NoError(t, err)
+
+	id, err := NewDimension(context, "id", TILEDB_INT32, []int32{1, 100}, int32(10))
+	require.NoError(t, err)
+	key, err := NewStringDimension(context, "key")
+	require.NoError(t, err)
+	domain, err := NewDomain(context)
+	require.NoError(t, err)
+	require.NoError(t, domain.AddDimensions(id, key))
+
+	schema, err := NewArraySchema(context, TILEDB_SPARSE)
+	require.NoError(t, err)
+	require.NoError(t, schema.SetDomain(domain))
+	require.NoError(t, schema.SetCapacity(2))
+	v, err := NewAttribute(context, "v", TILEDB_INT32)
+	require.NoError(t, err)
+	require.NoError(t, schema.AddAttributes(v))
+
+	arrayPath := t.TempDir()
+	require.NoError(t, CreateArray(context, arrayPath, schema))
+	array, err := NewArray(context, arrayPath)
+	require.NoError(t, err)
+
+	type row struct {
+		ID  int32  `tiledb:"id"`
+		Key string `tiledb:"key"`
+		V   int32  `tiledb:"v"`
+	}
+	rows := []row{{1, "a", 1}, {2, "b", 2}, {3, "c", 3}, {4, "d", 4}, {5, "e", 5}}
+	require.NoError(t, array.Open(TILEDB_WRITE))
+	require.NoError(t, Write(context, array, rows, TILEDB_UNORDERED))
+	require.NoError(t, array.Close())
+
+	fI, err := NewFragmentInfo(context, arrayPath)
+	require.NoError(t, err)
+	require.NoError(t, fI.Load())
+
+	totalCellNum, err := fI.GetTotalCellNum()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(len(rows)), totalCellNum)
+
+	schemaName, err := fI.GetArraySchemaName(0)
+	require.NoError(t, err)
+	assert.NotEmpty(t, schemaName)
+
+	fragmentSchema, err := fI.GetArraySchema(0)
+	require.NoError(t, err)
+	capacity, err := fragmentSchema.Capacity()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), capacity)
+
+	mbrNum, err := fI.GetMBRNum(0)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), mbrNum)
+
+	r, err := fI.GetMBRFromIndex(0, 1, 0)
+	require.NoError(t, err)
+	bounds, err := ExtractRange[int32](r)
+	require.NoError(t, err)
+	assert.Equal(t, []int32{3, 4}, bounds[:2])
+
+	r, err = fI.GetMBRFromName(0, 2, "key")
+	require.NoError(t, err)
+	start, end := r.Endpoints()
+	assert.Equal(t, "e", start)
+	assert.Equal(t, "e", end)
+
+	r, err = fI.GetMBRFromIndex(0, 0, 1)
+	require.NoError(t, err)
+	start, end = r.Endpoints()
+	assert.Equal(t, "a", start)
+	assert.Equal(t, "b", end)
+
+	mbr, err := fI.GetMBR(0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []NonEmptyDomain{
+		{DimensionName: "id", Bounds: []int32{1, 2}},
+		{DimensionName: "key", Bounds: []string{"a", "b"}},
+	}, mbr)
+
+	_, err = fI.GetMBRFromName(0, 0, "other")
+	require.Error(t, err)
+}
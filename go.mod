@@ -4,12 +4,12 @@ require (
 	github.com/mattn/go-pointer v0.0.1
 	github.com/stretchr/testify v1.6.1
 	go4.org/unsafe/assume-no-moving-gc v0.0.0-20230525183740-e7c30c78aeb2
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
 	github.com/davecgh/go-spew v1.1.0 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
 
 // Local triggered panic when referencing enums
@@ -569,3 +569,42 @@ func (g *Group) QueryType() (QueryType, error) {
 
 	return QueryType(queryType), nil
 }
+
+// ConsolidateMetadata consolidates the metadata of the group into a single file, which makes opening
+// groups with many metadata writes faster. config can be nil. The consolidated files are removed by VacuumMetadata.
+func (g *Group) ConsolidateMetadata(config *Config) error {
+	curi := C.CString(g.uri)
+	defer C.free(unsafe.Pointer(curi))
+
+	var cfg *C.tiledb_config_t
+	if config != nil {
+		cfg = config.tiledbConfig.Get()
+	}
+
+	ret := C.tiledb_group_consolidate_metadata(g.context.tiledbContext.Get(), curi, cfg)
+	runtime.KeepAlive(g)
+	runtime.KeepAlive(config)
+	if ret != C.TILEDB_OK {
+		return fmt.Errorf("error consolidating group metadata: %w", g.context.LastError())
+	}
+	return nil
+}
+
+// VacuumMetadata removes the group metadata files that were consolidated by ConsolidateMetadata. config can be nil.
+func (g *Group) VacuumMetadata(config *Config) error {
+	curi := C.CString(g.uri)
+	defer C.free(unsafe.Pointer(curi))
+
+	var cfg *C.tiledb_config_t
+	if config != nil {
+		cfg = config.tiledbConfig.Get()
+	}
+
+	ret := C.tiledb_group_vacuum_metadata(g.context.tiledbContext.Get(), curi, cfg)
+	runtime.KeepAlive(g)
+	runtime.KeepAlive(config)
+	if ret != C.TILEDB_OK {
+		return fmt.Errorf("error vacuuming group metadata: %w", g.context.LastError())
+	}
+	return nil
+}
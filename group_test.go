@@ -1,6 +1,7 @@
 package tiledb
 
 import (
+	"os"
 	"path/filepath"
 	"strconv"
 	"testing"
@@ -505,3 +506,41 @@ func setConfigForWrite(group *Group, i int) error {
 	}
 	return nil
 }
+
+func TestGroupConsolidateMetadata(t *testing.T) {
+	tdbCtx, err := NewContext(nil)
+	require.NoError(t, err)
+
+	groupURI := t.TempDir()
+	group, err := createTestGroup(tdbCtx, groupURI)
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, setConfigForWrite(group, i))
+		require.NoError(t, group.Open(TILEDB_WRITE))
+		require.NoError(t, group.PutMetadata("key"+strconv.Itoa(i), int32(i)))
+		require.NoError(t, group.Close())
+	}
+
+	metaFiles := func() int {
+		entries, err := os.ReadDir(filepath.Join(groupURI, "__meta"))
+		require.NoError(t, err)
+		return len(entries)
+	}
+	require.Equal(t, 3, metaFiles())
+
+	require.NoError(t, group.ConsolidateMetadata(nil))
+	consolidated := metaFiles()
+	assert.Greater(t, consolidated, 3)
+
+	config, err := NewConfig()
+	require.NoError(t, err)
+	require.NoError(t, group.VacuumMetadata(config))
+	assert.Less(t, metaFiles(), consolidated)
+
+	require.NoError(t, group.Open(TILEDB_READ))
+	num, err := group.GetMetadataNum()
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, num)
+	require.NoError(t, group.Close())
+}
@@ -0,0 +1,250 @@
+package tiledb
+
+/*
+#include <tiledb/tiledb.h>
+#include <tiledb/tiledb_experimental.h>
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"unsafe"
+)
+
+type ndRectangleHandle struct{ *capiHandle }
+
+func freeCapiNDRectangle(c unsafe.Pointer) {
+	C.tiledb_ndrectangle_free((**C.tiledb_ndrectangle_t)(unsafe.Pointer(&c)))
+}
+
+func newNDRectangleHandle(ptr *C.tiledb_ndrectangle_t) ndRectangleHandle {
+	return ndRectangleHandle{newCapiHandle(unsafe.Pointer(ptr), freeCapiNDRectangle)}
+}
+
+func (x ndRectangleHandle) Get() *C.tiledb_ndrectangle_t {
+	return (*C.tiledb_ndrectangle_t)(x.capiHandle.Get())
+}
+
+// NDRectangle is a hyperrectangle defined by one range per dimension of a domain.
+// It is used to define the current domain of an array.
+type NDRectangle struct {
+	tiledbNDRectangle ndRectangleHandle
+	context           *Context
+}
+
+func newNDRectangleFromHandle(context *Context, handle ndRectangleHandle) *NDRectangle {
+	return &NDRectangle{tiledbNDRectangle: handle, context: context}
+}
+
+// NewNDRectangle allocates an NDRectangle for the dimensions of the domain.
+func NewNDRectangle(tdbCtx *Context, domain *Domain) (*NDRectangle, error) {
+	var ndrPtr *C.tiledb_ndrectangle_t
+	ret := C.tiledb_ndrectangle_alloc(tdbCtx.tiledbContext.Get(), domain.tiledbDomain.Get(), &ndrPtr)
+	runtime.KeepAlive(tdbCtx)
+	runtime.KeepAlive(domain)
+	if ret != C.TILEDB_OK {
+		return nil, fmt.Errorf("error creating tiledb ndrectangle: %w", tdbCtx.LastError())
+	}
+
+	return newNDRectangleFromHandle(tdbCtx, newNDRectangleHandle(ndrPtr)), nil
+}
+
+// Free releases the internal TileDB core data that was allocated on the C heap.
+// It is automatically called when this object is garbage collected, but can be
+// called earlier to manually release memory if needed. Free is idempotent and
+// can safely be called many times on the same object; if it has already
+// been freed, it will not be freed again.
+func (ndr *NDRectangle) Free() {
+	ndr.tiledbNDRectangle.Free()
+}
+
+// Context exposes the internal TileDB context used to initialize the NDRectangle.
+func (ndr *NDRectangle) Context() *Context {
+	return ndr.context
+}
+
+// NDim returns the number of dimensions of the NDRectangle.
+func (ndr *NDRectangle) NDim() (uint32, error) {
+	var ndim C.uint32_t
+	ret := C.tiledb_ndrectangle_get_dim_num(ndr.context.tiledbContext.Get(), ndr.tiledbNDRectangle.Get(), &ndim)
+	runtime.KeepAlive(ndr)
+	if ret != C.TILEDB_OK {
+		return 0, fmt.Errorf("error getting number of dimensions of ndrectangle: %w", ndr.context.LastError())
+	}
+
+	return uint32(ndim), nil
+}
+
+// Type returns the datatype of the dimension with index dimIdx.
+func (ndr *NDRectangle) Type(dimIdx uint32) (Datatype, error) {
+	var dt C.tiledb_datatype_t
+	ret := C.tiledb_ndrectangle_get_dtype(ndr.context.tiledbContext.Get(), ndr.tiledbNDRectangle.Get(), C.uint32_t(dimIdx), &dt)
+	runtime.KeepAlive(ndr)
+	if ret != C.TILEDB_OK {
+		return 0, fmt.Errorf("error getting datatype of ndrectangle dimension %d: %w", dimIdx, ndr.context.LastError())
+	}
+
+	return Datatype(dt), nil
+}
+
+// TypeFromName returns the datatype of the dimension with name dimName.
+func (ndr *NDRectangle) TypeFromName(dimName string) (Datatype, error) {
+	cDimName := C.CString(dimName)
+	defer C.free(unsafe.Pointer(cDimName))
+
+	var dt C.tiledb_datatype_t
+	ret := C.tiledb_ndrectangle_get_dtype_from_name(ndr.context.tiledbContext.Get(), ndr.tiledbNDRectangle.Get(), cDimName, &dt)
+	runtime.KeepAlive(ndr)
+	if ret != C.TILEDB_OK {
+		return 0, fmt.Errorf("error getting datatype of ndrectangle dimension %s: %w", dimName, ndr.context.LastError())
+	}
+
+	return Datatype(dt), nil
+}
+
+// SetRange sets the range of the dimension with index dimIdx. The type of the range must match
+// the type of the dimension; string dimensions take string ranges.
+func (ndr *NDRectangle) SetRange(dimIdx uint32, r Range) error {
+	dt, err := ndr.Type(dimIdx)
+	if err != nil {
+		return err
+	}
+	if err := r.assertCompatibility(dt, isVarDimensionType(dt)); err != nil {
+		return err
+	}
+
+	var pinner runtime.Pinner
+	defer pinner.Unpin()
+	cRange := ndRectangleRange(&pinner, r)
+
+	ret := C.tiledb_ndrectangle_set_range(ndr.context.tiledbContext.Get(), ndr.tiledbNDRectangle.Get(), C.uint32_t(dimIdx), &cRange)
+	runtime.KeepAlive(ndr)
+	if ret != C.TILEDB_OK {
+		return fmt.Errorf("error setting range of ndrectangle dimension %d: %w", dimIdx, ndr.context.LastError())
+	}
+
+	return nil
+}
+
+// SetRangeForName sets the range of the dimension with name dimName. The type of the range must match
+// the type of the dimension; string dimensions take string ranges.
+func (ndr *NDRectangle) SetRangeForName(dimName string, r Range) error {
+	dt, err := ndr.TypeFromName(dimName)
+	if err != nil {
+		return err
+	}
+	if err := r.assertCompatibility(dt, isVarDimensionType(dt)); err != nil {
+		return err
+	}
+
+	cDimName := C.CString(dimName)
+	defer C.free(unsafe.Pointer(cDimName))
+
+	var pinner runtime.Pinner
+	defer pinner.Unpin()
+	cRange := ndRectangleRange(&pinner, r)
+
+	ret := C.tiledb_ndrectangle_set_range_for_name(ndr.context.tiledbContext.Get(), ndr.tiledbNDRectangle.Get(), cDimName, &cRange)
+	runtime.KeepAlive(ndr)
+	if ret != C.TILEDB_OK {
+		return fmt.Errorf("error setting range of ndrectangle dimension %s: %w", dimName, ndr.context.LastError())
+	}
+
+	return nil
+}
+
+// Range returns the range of the dimension with index dimIdx.
+// Use ExtractRange to get the typed endpoints.
+func (ndr *NDRectangle) Range(dimIdx uint32) (Range, error) {
+	dt, err := ndr.Type(dimIdx)
+	if err != nil {
+		return Range{}, err
+	}
+
+	var cRange C.tiledb_range_t
+	ret := C.tiledb_ndrectangle_get_range_from_index(ndr.context.tiledbContext.Get(), ndr.tiledbNDRectangle.Get(), C.uint32_t(dimIdx), &cRange)
+	if ret != C.TILEDB_OK {
+		return Range{}, fmt.Errorf("error getting range of ndrectangle dimension %d: %w", dimIdx, ndr.context.LastError())
+	}
+	r := rangeFromNDRectangle(cRange, dt)
+	runtime.KeepAlive(ndr)
+
+	return r, nil
+}
+
+// RangeFromName returns the range of the dimension with name dimName.
+// Use ExtractRange to get the typed endpoints.
+func (ndr *NDRectangle) RangeFromName(dimName string) (Range, error) {
+	dt, err := ndr.TypeFromName(dimName)
+	if err != nil {
+		return Range{}, err
+	}
+
+	cDimName := C.CString(dimName)
+	defer C.free(unsafe.Pointer(cDimName))
+
+	var cRange C.tiledb_range_t
+	ret := C.tiledb_ndrectangle_get_range_from_name(ndr.context.tiledbContext.Get(), ndr.tiledbNDRectangle.Get(), cDimName, &cRange)
+	if ret != C.TILEDB_OK {
+		return Range{}, fmt.Errorf("error getting range of ndrectangle dimension %s: %w", dimName, ndr.context.LastError())
+	}
+	r := rangeFromNDRectangle(cRange, dt)
+	runtime.KeepAlive(ndr)
+
+	return r, nil
+}
+
+// isVarDimensionType returns whether dimensions of type dt are var-sized.
+// Only string dimensions are var-sized.
+func isVarDimensionType(dt Datatype) bool {
+	return dt == TILEDB_STRING_ASCII
+}
+
+// ndRectangleRange converts r to a tiledb_range_t. The endpoints are pinned
+// with pinner because the C struct holds pointers to them.
+func ndRectangleRange(pinner *runtime.Pinner, r Range) C.tiledb_range_t {
+	var cRange C.tiledb_range_t
+	if start, ok := r.start.(string); ok {
+		end := r.end.(string)
+		// Empty strings have no data pointer. Use a one-byte buffer with zero size instead,
+		// the core rejects nil pointers.
+		startData := append([]byte(start), 0)
+		endData := append([]byte(end), 0)
+		pinner.Pin(&startData[0])
+		pinner.Pin(&endData[0])
+		cRange.min = slicePtr(startData)
+		cRange.min_size = C.uint64_t(len(start))
+		cRange.max = slicePtr(endData)
+		cRange.max_size = C.uint64_t(len(end))
+		return cRange
+	}
+
+	startValue := addressableValue(r.start)
+	endValue := addressableValue(r.end)
+	pinner.Pin(startValue.UnsafePointer())
+	pinner.Pin(endValue.UnsafePointer())
+	cRange.min = startValue.UnsafePointer()
+	cRange.min_size = C.uint64_t(startValue.Elem().Type().Size())
+	cRange.max = endValue.UnsafePointer()
+	cRange.max_size = C.uint64_t(endValue.Elem().Type().Size())
+	return cRange
+}
+
+// rangeFromNDRectangle copies the endpoints of a tiledb_range_t of datatype dt to a Range.
+func rangeFromNDRectangle(cRange C.tiledb_range_t, dt Datatype) Range {
+	if isVarDimensionType(dt) {
+		return Range{
+			start: string(C.GoBytes(cRange.min, C.int(cRange.min_size))),
+			end:   string(C.GoBytes(cRange.max, C.int(cRange.max_size))),
+		}
+	}
+
+	typ := dt.ReflectType()
+	return Range{
+		start: reflect.NewAt(typ, cRange.min).Elem().Interface(),
+		end:   reflect.NewAt(typ, cRange.max).Elem().Interface(),
+	}
+}
@@ -0,0 +1,19 @@
+/*
+Package npy imports NumPy .npy and .npz files into dense TileDB arrays and exports dense arrays to them.
+The NPY format is read and written in Go, NumPy is not needed.
+
+An NPY file holds an array with a header describing its dtype, shape and order. ReadHeader parses it
+and Header.Datatype maps the dtype to a TileDB datatype: booleans, integers and floats of the same width,
+datetime64 to datetimes and timedelta64 to times of the same unit. Other dtypes are not supported.
+NewArraySchema builds the schema of a dense array with a dimension per axis for NPY arrays.
+
+An NPZ file is a zip archive of NPY files, which are imported to and exported from the attributes
+of a dense array named after them:
+
+	err := npy.ImportFile(tdbCtx, "data/grid", "grid.npz", npy.ImportOptions{})
+	...
+	err = npy.ExportNPZ(tdbCtx, file, array, subarray, npy.NPZOptions{Compress: true})
+
+See https://numpy.org/doc/stable/reference/generated/numpy.lib.format.html.
+*/
+package npy
@@ -0,0 +1,246 @@
+package npy
+
+import (
+	"archive/zip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+
+	tiledb "github.com/TileDB-Inc/TileDB-Go"
+)
+
+// NPZOptions configures ExportNPZ.
+type NPZOptions struct {
+	// Attributes are the attributes to export. If empty, all of them are exported.
+	Attributes []string
+	// Compress deflates the NPY files, like numpy.savez_compressed. They are stored otherwise, like numpy.savez.
+	Compress bool
+}
+
+/*
+Export writes the attribute of the subarray of the dense array, which must be open for reading,
+to w as an NPY array in row-major order. The subarray must have a single range per dimension,
+whose sizes are the shape of the NPY array. If subarray is nil the non-empty domain is exported.
+If attribute is empty, the array must have a single attribute.
+
+The attribute must have single values of a datatype with a NumPy dtype: a numeric type,
+a datetime or a time, and cannot be nullable. The data is streamed with Query.Batches.
+*/
+func Export(tdbCtx *tiledb.Context, w io.Writer, array *tiledb.Array, subarray *tiledb.Subarray, attribute string) error {
+	subarray, shape, free, err := exportSubarray(array, subarray)
+	if err != nil {
+		return err
+	}
+	defer free()
+
+	if attribute == "" {
+		_, names, err := fieldNames(array)
+		if err != nil {
+			return err
+		}
+		if len(names) != 1 {
+			return fmt.Errorf("array has %d attributes, the attribute to export must be set", len(names))
+		}
+		attribute = names[0]
+	}
+
+	return exportAttribute(tdbCtx, w, array, subarray, shape, attribute)
+}
+
+// ExportNPZ writes attributes of the subarray of the dense array to w as an NPZ file,
+// with an NPY array per attribute named after it. See Export.
+func ExportNPZ(tdbCtx *tiledb.Context, w io.Writer, array *tiledb.Array, subarray *tiledb.Subarray, opts NPZOptions) error {
+	subarray, shape, free, err := exportSubarray(array, subarray)
+	if err != nil {
+		return err
+	}
+	defer free()
+
+	attributes := opts.Attributes
+	if len(attributes) == 0 {
+		if _, attributes, err = fieldNames(array); err != nil {
+			return err
+		}
+	}
+	method := zip.Store
+	if opts.Compress {
+		method = zip.Deflate
+	}
+
+	zw := zip.NewWriter(w)
+	for _, attribute := range attributes {
+		fw, err := zw.CreateHeader(&zip.FileHeader{Name: attribute + ".npy", Method: method})
+		if err != nil {
+			return err
+		}
+		if err := exportAttribute(tdbCtx, fw, array, subarray, shape, attribute); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// exportSubarray returns subarray, or the subarray of the non-empty domain of the array if it is nil,
+// and its shape. free releases the subarray if it was created.
+func exportSubarray(array *tiledb.Array, subarray *tiledb.Subarray) (_ *tiledb.Subarray, shape []int, free func(), err error) {
+	free = func() {}
+	if subarray == nil {
+		domains, empty, err := array.NonEmptyDomain()
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if empty {
+			return nil, nil, nil, errors.New("cannot export an empty array")
+		}
+
+		// The dimensions of dense arrays all have the same datatype.
+		bounds := reflect.ValueOf(domains[0].Bounds)
+		for _, domain := range domains[1:] {
+			bounds = reflect.AppendSlice(bounds, reflect.ValueOf(domain.Bounds))
+		}
+
+		if subarray, err = array.NewSubarray(); err != nil {
+			return nil, nil, nil, err
+		}
+		free = subarray.Free
+		if err := subarray.SetSubArray(bounds.Interface()); err != nil {
+			free()
+			return nil, nil, nil, err
+		}
+	}
+
+	ranges, err := subarray.GetRanges()
+	if err != nil {
+		free()
+		return nil, nil, nil, err
+	}
+	names, _, err := fieldNames(array)
+	if err != nil {
+		free()
+		return nil, nil, nil, err
+	}
+	shape = make([]int, len(names))
+	for d, name := range names {
+		if len(ranges[name]) != 1 {
+			free()
+			return nil, nil, nil, fmt.Errorf("dimension %s has %d ranges, NPY arrays need a single range per dimension", name, len(ranges[name]))
+		}
+		start, end := ranges[name][0].Endpoints()
+		lo, err := tiledb.DenseCoordinate(start)
+		if err != nil {
+			free()
+			return nil, nil, nil, fmt.Errorf("dimension %s: %w", name, err)
+		}
+		hi, err := tiledb.DenseCoordinate(end)
+		if err != nil {
+			free()
+			return nil, nil, nil, fmt.Errorf("dimension %s: %w", name, err)
+		}
+		shape[d] = int(hi - lo + 1)
+	}
+
+	return subarray, shape, free, nil
+}
+
+// exportAttribute writes the attribute of the subarray of shape to w as an NPY array.
+func exportAttribute(tdbCtx *tiledb.Context, w io.Writer, array *tiledb.Array, subarray *tiledb.Subarray, shape []int, attribute string) error {
+	descr, err := attributeDescr(array, attribute)
+	if err != nil {
+		return err
+	}
+	header := &Header{Descr: descr, Shape: shape}
+	if err := WriteHeader(w, header); err != nil {
+		return err
+	}
+
+	query, err := tiledb.NewQuery(tdbCtx, array)
+	if err != nil {
+		return err
+	}
+	defer query.Free()
+
+	if err := query.SetLayout(tiledb.TILEDB_ROW_MAJOR); err != nil {
+		return err
+	}
+	if err := query.SetSubarray(subarray); err != nil {
+		return err
+	}
+
+	var numCells uint64
+	query.Batches(tiledb.BatchOptions{Fields: []string{attribute}})(func(batch *tiledb.QueryBatch, batchErr error) bool {
+		if batchErr != nil {
+			err = batchErr
+			return false
+		}
+		var data any
+		if data, err = batch.Data(attribute); err != nil {
+			return false
+		}
+		if err = binary.Write(w, binary.LittleEndian, data); err != nil {
+			return false
+		}
+		numCells += batch.NumCells
+		return true
+	})
+	if err != nil {
+		return fmt.Errorf("could not export attribute %s: %w", attribute, err)
+	}
+	if numCells != uint64(header.NumCells()) {
+		return fmt.Errorf("read %d cells of attribute %s for an NPY array of shape %v", numCells, attribute, shape)
+	}
+
+	return nil
+}
+
+// attributeDescr returns the NumPy dtype of the attribute of the dense array.
+func attributeDescr(array *tiledb.Array, name string) (string, error) {
+	schema, err := array.Schema()
+	if err != nil {
+		return "", err
+	}
+	defer schema.Free()
+
+	arrayType, err := schema.Type()
+	if err != nil {
+		return "", err
+	}
+	if arrayType != tiledb.TILEDB_DENSE {
+		return "", errors.New("only dense arrays can be exported to NPY")
+	}
+
+	attribute, err := schema.AttributeFromName(name)
+	if err != nil {
+		return "", err
+	}
+	defer attribute.Free()
+
+	datatype, err := attribute.Type()
+	if err != nil {
+		return "", err
+	}
+	cellValNum, err := attribute.CellValNum()
+	if err != nil {
+		return "", err
+	}
+	nullable, err := attribute.Nullable()
+	if err != nil {
+		return "", err
+	}
+	if cellValNum != 1 || nullable {
+		return "", fmt.Errorf("attribute %s must have single non-nullable values to be exported to NPY", name)
+	}
+
+	return Descr(datatype)
+}
+
+// fieldNames returns the names of the dimensions and of the attributes of the array.
+func fieldNames(array *tiledb.Array) (dimensions, attributes []string, err error) {
+	schema, err := array.Schema()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer schema.Free()
+	return schema.FieldNames()
+}
@@ -0,0 +1,416 @@
+package npy
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	tiledb "github.com/TileDB-Inc/TileDB-Go"
+)
+
+// magic is the prefix of NPY files.
+const magic = "\x93NUMPY"
+
+// headerAlignment is the alignment of the data of NPY files.
+const headerAlignment = 64
+
+// Header is the header of an NPY file, which describes the array in the file.
+type Header struct {
+	// Descr is the NumPy dtype of the array, e.g. "<f8".
+	Descr string
+	// FortranOrder is whether the data is in column-major order, it is in row-major order otherwise.
+	FortranOrder bool
+	// Shape is the shape of the array.
+	Shape []int
+}
+
+// numericTypes maps the dtype kinds and sizes without byte order to datatypes.
+var numericTypes = map[string]tiledb.Datatype{
+	"b1": tiledb.TILEDB_BOOL,
+	"i1": tiledb.TILEDB_INT8,
+	"i2": tiledb.TILEDB_INT16,
+	"i4": tiledb.TILEDB_INT32,
+	"i8": tiledb.TILEDB_INT64,
+	"u1": tiledb.TILEDB_UINT8,
+	"u2": tiledb.TILEDB_UINT16,
+	"u4": tiledb.TILEDB_UINT32,
+	"u8": tiledb.TILEDB_UINT64,
+	"f4": tiledb.TILEDB_FLOAT32,
+	"f8": tiledb.TILEDB_FLOAT64,
+}
+
+// datetimeUnits maps the units of datetime64 dtypes to datatypes.
+var datetimeUnits = map[string]tiledb.Datatype{
+	"Y":  tiledb.TILEDB_DATETIME_YEAR,
+	"M":  tiledb.TILEDB_DATETIME_MONTH,
+	"W":  tiledb.TILEDB_DATETIME_WEEK,
+	"D":  tiledb.TILEDB_DATETIME_DAY,
+	"h":  tiledb.TILEDB_DATETIME_HR,
+	"m":  tiledb.TILEDB_DATETIME_MIN,
+	"s":  tiledb.TILEDB_DATETIME_SEC,
+	"ms": tiledb.TILEDB_DATETIME_MS,
+	"us": tiledb.TILEDB_DATETIME_US,
+	"ns": tiledb.TILEDB_DATETIME_NS,
+	"ps": tiledb.TILEDB_DATETIME_PS,
+	"fs": tiledb.TILEDB_DATETIME_FS,
+	"as": tiledb.TILEDB_DATETIME_AS,
+}
+
+// timedeltaUnits maps the units of timedelta64 dtypes to datatypes.
+var timedeltaUnits = map[string]tiledb.Datatype{
+	"h":  tiledb.TILEDB_TIME_HR,
+	"m":  tiledb.TILEDB_TIME_MIN,
+	"s":  tiledb.TILEDB_TIME_SEC,
+	"ms": tiledb.TILEDB_TIME_MS,
+	"us": tiledb.TILEDB_TIME_US,
+	"ns": tiledb.TILEDB_TIME_NS,
+	"ps": tiledb.TILEDB_TIME_PS,
+	"fs": tiledb.TILEDB_TIME_FS,
+	"as": tiledb.TILEDB_TIME_AS,
+}
+
+// Datatype returns the TileDB datatype of the dtype of the header.
+func (h *Header) Datatype() (tiledb.Datatype, error) {
+	if len(h.Descr) < 2 {
+		return 0, fmt.Errorf("invalid dtype %q", h.Descr)
+	}
+	typ := h.Descr[1:]
+	if datatype, ok := numericTypes[typ]; ok {
+		return datatype, nil
+	}
+	if unit, ok := strings.CutPrefix(typ, "M8["); ok {
+		if datatype, ok := datetimeUnits[strings.TrimSuffix(unit, "]")]; ok {
+			return datatype, nil
+		}
+	}
+	if unit, ok := strings.CutPrefix(typ, "m8["); ok {
+		if datatype, ok := timedeltaUnits[strings.TrimSuffix(unit, "]")]; ok {
+			return datatype, nil
+		}
+	}
+	return 0, fmt.Errorf("unsupported dtype %q", h.Descr)
+}
+
+// byteOrder returns the byte order of the data.
+func (h *Header) byteOrder() (binary.ByteOrder, error) {
+	if len(h.Descr) == 0 {
+		return nil, errors.New("empty dtype")
+	}
+	switch h.Descr[0] {
+	case '<', '|':
+		return binary.LittleEndian, nil
+	case '>':
+		return binary.BigEndian, nil
+	case '=':
+		return binary.NativeEndian, nil
+	default:
+		return nil, fmt.Errorf("invalid byte order in dtype %q", h.Descr)
+	}
+}
+
+// NumCells returns the number of cells of the array, the product of its shape.
+func (h *Header) NumCells() int {
+	n := 1
+	for _, s := range h.Shape {
+		n *= s
+	}
+	return n
+}
+
+// Descr returns the little-endian NumPy dtype of datatype.
+func Descr(datatype tiledb.Datatype) (string, error) {
+	for typ, d := range numericTypes {
+		if d == datatype {
+			if typ[1] == '1' {
+				return "|" + typ, nil
+			}
+			return "<" + typ, nil
+		}
+	}
+	for unit, d := range datetimeUnits {
+		if d == datatype {
+			return "<M8[" + unit + "]", nil
+		}
+	}
+	for unit, d := range timedeltaUnits {
+		if d == datatype {
+			return "<m8[" + unit + "]", nil
+		}
+	}
+	return "", fmt.Errorf("datatype %s has no NumPy dtype", datatype)
+}
+
+// ReadHeader reads the magic string and the header of an NPY file from r.
+// After it returns r is at the start of the data.
+func ReadHeader(r io.Reader) (*Header, error) {
+	prefix := make([]byte, len(magic)+2)
+	if _, err := io.ReadFull(r, prefix); err != nil {
+		return nil, fmt.Errorf("could not read NPY magic string: %w", err)
+	}
+	if string(prefix[:len(magic)]) != magic {
+		return nil, errors.New("not an NPY file")
+	}
+
+	var headerLen uint32
+	switch major := prefix[len(magic)]; major {
+	case 1:
+		var n uint16
+		if err := binary.Read(r, binary.LittleEndian, &n); err != nil {
+			return nil, fmt.Errorf("could not read NPY header length: %w", err)
+		}
+		headerLen = uint32(n)
+	case 2, 3:
+		if err := binary.Read(r, binary.LittleEndian, &headerLen); err != nil {
+			return nil, fmt.Errorf("could not read NPY header length: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported NPY format version %d", major)
+	}
+
+	header := make([]byte, headerLen)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, fmt.Errorf("could not read NPY header: %w", err)
+	}
+
+	return parseHeader(string(header))
+}
+
+// WriteHeader writes the magic string and h to w, in format version 1.0
+// or 2.0 if the header is too long for version 1.0.
+func WriteHeader(w io.Writer, h *Header) error {
+	var dict strings.Builder
+	fmt.Fprintf(&dict, "{'descr': '%s', 'fortran_order': ", h.Descr)
+	if h.FortranOrder {
+		dict.WriteString("True")
+	} else {
+		dict.WriteString("False")
+	}
+	dict.WriteString(", 'shape': (")
+	for i, s := range h.Shape {
+		if i > 0 {
+			dict.WriteString(", ")
+		}
+		dict.WriteString(strconv.Itoa(s))
+	}
+	if len(h.Shape) == 1 {
+		dict.WriteString(",")
+	}
+	dict.WriteString("), }")
+
+	// The header is padded with spaces and ends with a newline, so that the data is aligned.
+	major, lenSize := byte(1), 2
+	size := len(magic) + 2 + lenSize + dict.Len() + 1
+	if size+headerAlignment > 1<<16 {
+		major, lenSize = 2, 4
+		size = len(magic) + 2 + lenSize + dict.Len() + 1
+	}
+	padding := (headerAlignment - size%headerAlignment) % headerAlignment
+	headerLen := dict.Len() + padding + 1
+
+	var buf bytes.Buffer
+	buf.WriteString(magic)
+	buf.WriteByte(major)
+	buf.WriteByte(0)
+	if major == 1 {
+		_ = binary.Write(&buf, binary.LittleEndian, uint16(headerLen))
+	} else {
+		_ = binary.Write(&buf, binary.LittleEndian, uint32(headerLen))
+	}
+	buf.WriteString(dict.String())
+	buf.WriteString(strings.Repeat(" ", padding))
+	buf.WriteByte('\n')
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// headerParser parses the Python dict literal of NPY headers.
+type headerParser struct {
+	r *bufio.Reader
+}
+
+// parseHeader parses the header dict s, which has the keys descr, fortran_order and shape.
+func parseHeader(s string) (*Header, error) {
+	p := &headerParser{r: bufio.NewReader(strings.NewReader(s))}
+	h := &Header{}
+	if err := p.parseDict(h); err != nil {
+		return nil, fmt.Errorf("invalid NPY header %q: %w", strings.TrimSpace(s), err)
+	}
+	return h, nil
+}
+
+func (p *headerParser) parseDict(h *Header) error {
+	if err := p.expect('{'); err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool)
+	for {
+		c, err := p.peek()
+		if err != nil {
+			return err
+		}
+		if c == '}' {
+			break
+		}
+
+		key, err := p.parseString()
+		if err != nil {
+			return err
+		}
+		if err := p.expect(':'); err != nil {
+			return err
+		}
+		switch key {
+		case "descr":
+			if c, err := p.peek(); err == nil && c == '[' {
+				return errors.New("structured dtypes are not supported")
+			}
+			h.Descr, err = p.parseString()
+		case "fortran_order":
+			h.FortranOrder, err = p.parseBool()
+		case "shape":
+			h.Shape, err = p.parseTuple()
+		default:
+			err = fmt.Errorf("unexpected key %q", key)
+		}
+		if err != nil {
+			return err
+		}
+		seen[key] = true
+
+		if c, err = p.peek(); err != nil {
+			return err
+		}
+		if c != ',' {
+			break
+		}
+		p.r.ReadByte()
+	}
+	if err := p.expect('}'); err != nil {
+		return err
+	}
+
+	for _, key := range []string{"descr", "fortran_order", "shape"} {
+		if !seen[key] {
+			return fmt.Errorf("missing key %q", key)
+		}
+	}
+	return nil
+}
+
+// peek returns the next byte that is not a space, without consuming it.
+func (p *headerParser) peek() (byte, error) {
+	for {
+		c, err := p.r.ReadByte()
+		if err != nil {
+			return 0, io.ErrUnexpectedEOF
+		}
+		if c != ' ' && c != '\n' && c != '\t' {
+			return c, p.r.UnreadByte()
+		}
+	}
+}
+
+func (p *headerParser) expect(want byte) error {
+	c, err := p.peek()
+	if err != nil {
+		return err
+	}
+	if c != want {
+		return fmt.Errorf("expected %q, got %q", want, c)
+	}
+	_, err = p.r.ReadByte()
+	return err
+}
+
+func (p *headerParser) parseString() (string, error) {
+	quote, err := p.peek()
+	if err != nil {
+		return "", err
+	}
+	if quote != '\'' && quote != '"' {
+		return "", fmt.Errorf("expected string, got %q", quote)
+	}
+	p.r.ReadByte()
+	s, err := p.r.ReadString(quote)
+	if err != nil {
+		return "", io.ErrUnexpectedEOF
+	}
+	return strings.TrimSuffix(s, string(quote)), nil
+}
+
+func (p *headerParser) parseBool() (bool, error) {
+	var word []byte
+	for {
+		c, err := p.peek()
+		if err != nil {
+			return false, err
+		}
+		if c < 'A' || c > 'z' {
+			break
+		}
+		p.r.ReadByte()
+		word = append(word, c)
+	}
+	switch string(word) {
+	case "True":
+		return true, nil
+	case "False":
+		return false, nil
+	default:
+		return false, fmt.Errorf("expected True or False, got %q", word)
+	}
+}
+
+// parseTuple parses a tuple of non-negative integers.
+func (p *headerParser) parseTuple() ([]int, error) {
+	if err := p.expect('('); err != nil {
+		return nil, err
+	}
+	values := []int{}
+	for {
+		c, err := p.peek()
+		if err != nil {
+			return nil, err
+		}
+		if c == ')' {
+			break
+		}
+
+		var digits []byte
+		for c >= '0' && c <= '9' {
+			p.r.ReadByte()
+			digits = append(digits, c)
+			if c, err = p.peek(); err != nil {
+				return nil, err
+			}
+		}
+		// Python 2 wrote long integers with an L suffix.
+		if c == 'L' {
+			p.r.ReadByte()
+		}
+		v, err := strconv.Atoi(string(digits))
+		if err != nil {
+			return nil, fmt.Errorf("invalid shape: %w", err)
+		}
+		values = append(values, v)
+
+		if c, err = p.peek(); err != nil {
+			return nil, err
+		}
+		if c != ',' {
+			break
+		}
+		p.r.ReadByte()
+	}
+	if err := p.expect(')'); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
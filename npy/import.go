@@ -0,0 +1,404 @@
+package npy
+
+import (
+	"archive/zip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+
+	tiledb "github.com/TileDB-Inc/TileDB-Go"
+)
+
+// ImportOptions configures Import, ImportNPZ and ImportFile.
+type ImportOptions struct {
+	// Attribute is the attribute Import writes. If empty, the array must have a single attribute,
+	// and ImportFile names it DefaultAttribute.
+	Attribute string
+	// ChunkTiles is the number of tiles along the slowest varying dimension written per query, 1 if zero.
+	ChunkTiles int
+	// Schema configures the schema of the array ImportFile creates.
+	Schema SchemaOptions
+}
+
+// source is an NPY array to write to an attribute.
+type source struct {
+	attribute string
+	header    *Header
+	datatype  tiledb.Datatype
+	order     binary.ByteOrder
+	r         io.Reader // positioned at the data
+}
+
+func newSource(attribute string, header *Header, r io.Reader) (*source, error) {
+	datatype, err := header.Datatype()
+	if err != nil {
+		return nil, err
+	}
+	order, err := header.byteOrder()
+	if err != nil {
+		return nil, err
+	}
+	return &source{attribute: attribute, header: header, datatype: datatype, order: order, r: r}, nil
+}
+
+/*
+Import writes the NPY array read from r to an attribute of the dense array, which must be open for writing.
+
+The array must have a dimension per axis of the NPY array, each with a domain large enough for its size,
+and the NPY array is written from the lower bounds of the domain. The attribute must have the datatype of
+the NPY dtype and be the only attribute, since the core writes all the attributes of dense arrays together.
+The data is streamed in tile-aligned chunks of ImportOptions.ChunkTiles tiles along the slowest varying
+dimension: the first for row-major NPY arrays and the last for Fortran-order ones.
+*/
+func Import(tdbCtx *tiledb.Context, array *tiledb.Array, r io.Reader, opts ImportOptions) error {
+	header, err := ReadHeader(r)
+	if err != nil {
+		return err
+	}
+	src, err := newSource(opts.Attribute, header, r)
+	if err != nil {
+		return err
+	}
+	return importSources(tdbCtx, array, []*source{src}, opts.ChunkTiles)
+}
+
+// ImportNPZ writes the arrays of the NPZ file zr to the attributes of the dense array named after
+// them, like Import. The array must have an attribute per array and no other attributes,
+// and the arrays must have the same shape and order.
+func ImportNPZ(tdbCtx *tiledb.Context, array *tiledb.Array, zr *zip.Reader, opts ImportOptions) error {
+	var sources []*source
+	for _, f := range zr.File {
+		name, ok := strings.CutSuffix(f.Name, ".npy")
+		if !ok {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return err
+		}
+		defer rc.Close()
+
+		header, err := ReadHeader(rc)
+		if err != nil {
+			return fmt.Errorf("%s: %w", f.Name, err)
+		}
+		src, err := newSource(name, header, rc)
+		if err != nil {
+			return fmt.Errorf("%s: %w", f.Name, err)
+		}
+		sources = append(sources, src)
+	}
+	if len(sources) == 0 {
+		return errors.New("NPZ file has no NPY array")
+	}
+
+	return importSources(tdbCtx, array, sources, opts.ChunkTiles)
+}
+
+/*
+ImportFile creates a dense array at uri with NewArraySchema and imports the NPY or, if its name ends
+with .npz, NPZ file at path into it. The array of an NPY file has a single attribute named
+ImportOptions.Attribute or DefaultAttribute, and the array of an NPZ file an attribute per NPY array.
+
+Example:
+
+	err := npy.ImportFile(tdbCtx, "data/image", "image.npy", npy.ImportOptions{
+		Schema: npy.SchemaOptions{Dimensions: []string{"y", "x"}, TileExtents: []uint64{256, 256}},
+	})
+*/
+func ImportFile(tdbCtx *tiledb.Context, uri, path string, opts ImportOptions) error {
+	if strings.HasSuffix(path, ".npz") {
+		zr, err := zip.OpenReader(path)
+		if err != nil {
+			return err
+		}
+		defer zr.Close()
+
+		headers := make(map[string]*Header)
+		for _, f := range zr.File {
+			name, ok := strings.CutSuffix(f.Name, ".npy")
+			if !ok {
+				continue
+			}
+			rc, err := f.Open()
+			if err != nil {
+				return err
+			}
+			headers[name], err = ReadHeader(rc)
+			rc.Close()
+			if err != nil {
+				return fmt.Errorf("%s: %w", f.Name, err)
+			}
+		}
+
+		array, err := createArray(tdbCtx, uri, headers, opts.Schema)
+		if err != nil {
+			return err
+		}
+		defer array.Free()
+		defer array.Close()
+
+		return ImportNPZ(tdbCtx, array, &zr.Reader, opts)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	header, err := ReadHeader(f)
+	if err != nil {
+		return err
+	}
+	if opts.Attribute == "" {
+		opts.Attribute = DefaultAttribute
+	}
+	array, err := createArray(tdbCtx, uri, map[string]*Header{opts.Attribute: header}, opts.Schema)
+	if err != nil {
+		return err
+	}
+	defer array.Free()
+	defer array.Close()
+
+	src, err := newSource(opts.Attribute, header, f)
+	if err != nil {
+		return err
+	}
+	return importSources(tdbCtx, array, []*source{src}, opts.ChunkTiles)
+}
+
+// createArray creates the array at uri for the NPY arrays of headers and opens it for writing.
+func createArray(tdbCtx *tiledb.Context, uri string, headers map[string]*Header, opts SchemaOptions) (*tiledb.Array, error) {
+	schema, err := NewArraySchema(tdbCtx, headers, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer schema.Free()
+
+	if err := tiledb.CreateArray(tdbCtx, uri, schema); err != nil {
+		return nil, err
+	}
+
+	array, err := tiledb.NewArray(tdbCtx, uri)
+	if err != nil {
+		return nil, err
+	}
+	if err := array.Open(tiledb.TILEDB_WRITE); err != nil {
+		array.Free()
+		return nil, err
+	}
+	return array, nil
+}
+
+// importSources writes sources to the dense array in tile-aligned chunks.
+func importSources(tdbCtx *tiledb.Context, array *tiledb.Array, sources []*source, chunkTiles int) error {
+	header := sources[0].header
+	shape := header.Shape
+	for _, src := range sources[1:] {
+		if !slices.Equal(src.header.Shape, shape) || src.header.FortranOrder != header.FortranOrder {
+			return fmt.Errorf("array %s does not have the shape and order of array %s", src.attribute, sources[0].attribute)
+		}
+	}
+	if len(shape) == 0 {
+		return errors.New("cannot store a scalar NPY array in a dense array")
+	}
+	if header.NumCells() == 0 {
+		return nil
+	}
+
+	schema, err := array.Schema()
+	if err != nil {
+		return err
+	}
+	defer schema.Free()
+
+	if err := checkSources(schema, sources); err != nil {
+		return err
+	}
+	lo, extents, dimType, err := denseDomain(schema, shape)
+	if err != nil {
+		return err
+	}
+
+	// The data of the NPY array is contiguous along the slowest varying dimension.
+	slow, layout := 0, tiledb.TILEDB_ROW_MAJOR
+	if header.FortranOrder {
+		slow, layout = len(shape)-1, tiledb.TILEDB_COL_MAJOR
+	}
+	if chunkTiles <= 0 {
+		chunkTiles = 1
+	}
+	step := int(extents[slow]) * chunkTiles
+	sliceCells := header.NumCells() / shape[slow]
+
+	bounds := reflect.MakeSlice(reflect.SliceOf(dimType), 2*len(shape), 2*len(shape))
+	for d, n := range shape {
+		bounds.Index(2 * d).Set(reflect.ValueOf(lo[d]).Convert(dimType))
+		bounds.Index(2*d + 1).Set(reflect.ValueOf(lo[d] + int64(n) - 1).Convert(dimType))
+	}
+	for start := 0; start < shape[slow]; start += step {
+		n := min(step, shape[slow]-start)
+		bounds.Index(2 * slow).Set(reflect.ValueOf(lo[slow] + int64(start)).Convert(dimType))
+		bounds.Index(2*slow + 1).Set(reflect.ValueOf(lo[slow] + int64(start+n) - 1).Convert(dimType))
+		if err := writeChunk(tdbCtx, array, sources, bounds.Interface(), n*sliceCells, layout); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkSources checks that sources match all the attributes of schema.
+func checkSources(schema *tiledb.ArraySchema, sources []*source) error {
+	arrayType, err := schema.Type()
+	if err != nil {
+		return err
+	}
+	if arrayType != tiledb.TILEDB_DENSE {
+		return errors.New("NPY arrays can only be imported into dense arrays")
+	}
+
+	attributes, err := schema.Attributes()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		for _, attribute := range attributes {
+			attribute.Free()
+		}
+	}()
+	if len(sources) == 1 && sources[0].attribute == "" {
+		if len(attributes) != 1 {
+			return fmt.Errorf("array has %d attributes, the attribute to import to must be set", len(attributes))
+		}
+		if sources[0].attribute, err = attributes[0].Name(); err != nil {
+			return err
+		}
+	}
+	if len(attributes) != len(sources) {
+		return fmt.Errorf("array has %d attributes and %d NPY arrays are imported, all the attributes must be written", len(attributes), len(sources))
+	}
+
+	for _, src := range sources {
+		attribute, err := schema.AttributeFromName(src.attribute)
+		if err != nil {
+			return err
+		}
+		datatype, err := attribute.Type()
+		if err != nil {
+			attribute.Free()
+			return err
+		}
+		cellValNum, err := attribute.CellValNum()
+		attribute.Free()
+		if err != nil {
+			return err
+		}
+		if datatype != src.datatype || cellValNum != 1 {
+			return fmt.Errorf("attribute %s does not hold single %s values of NPY dtype %s", src.attribute, src.datatype, src.header.Descr)
+		}
+	}
+	return nil
+}
+
+// denseDomain returns the lower bounds and tile extents of the dimensions of schema,
+// and the Go type of their datatype. The domain must hold an array of shape.
+func denseDomain(schema *tiledb.ArraySchema, shape []int) (lo, extents []int64, dimType reflect.Type, err error) {
+	domain, err := schema.Domain()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	defer domain.Free()
+
+	nDim, err := domain.NDim()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if int(nDim) != len(shape) {
+		return nil, nil, nil, fmt.Errorf("array has %d dimensions and NPY array has shape %v", nDim, shape)
+	}
+	datatype, err := domain.Type()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	lo = make([]int64, nDim)
+	extents = make([]int64, nDim)
+	for d := range lo {
+		dimension, err := domain.DimensionFromIndex(uint(d))
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		bounds, err := dimension.Domain()
+		if err != nil {
+			dimension.Free()
+			return nil, nil, nil, err
+		}
+		extent, err := dimension.Extent()
+		dimension.Free()
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		if lo[d], err = tiledb.DenseCoordinate(reflect.ValueOf(bounds).Index(0).Interface()); err != nil {
+			return nil, nil, nil, err
+		}
+		hi, err := tiledb.DenseCoordinate(reflect.ValueOf(bounds).Index(1).Interface())
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if hi-lo[d]+1 < int64(shape[d]) {
+			return nil, nil, nil, fmt.Errorf("domain [%d, %d] of dimension %d cannot hold %d cells", lo[d], hi, d, shape[d])
+		}
+		if extents[d], err = tiledb.DenseCoordinate(extent); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	return lo, extents, datatype.ReflectType(), nil
+}
+
+// writeChunk writes numCells cells of the sources to the subarray of bounds.
+func writeChunk(tdbCtx *tiledb.Context, array *tiledb.Array, sources []*source, bounds any, numCells int, layout tiledb.Layout) error {
+	query, err := tiledb.NewQuery(tdbCtx, array)
+	if err != nil {
+		return err
+	}
+	defer query.Free()
+
+	if err := query.SetLayout(layout); err != nil {
+		return err
+	}
+	subarray, err := array.NewSubarray()
+	if err != nil {
+		return err
+	}
+	defer subarray.Free()
+	if err := subarray.SetSubArray(bounds); err != nil {
+		return err
+	}
+	if err := query.SetSubarray(subarray); err != nil {
+		return err
+	}
+
+	for _, src := range sources {
+		data := reflect.MakeSlice(reflect.SliceOf(src.datatype.ReflectType()), numCells, numCells).Interface()
+		if err := binary.Read(src.r, src.order, data); err != nil {
+			return fmt.Errorf("could not read data of NPY array %s: %w", src.attribute, err)
+		}
+		if _, err := query.SetDataBuffer(src.attribute, data); err != nil {
+			return err
+		}
+	}
+
+	if err := query.Submit(); err != nil {
+		return err
+	}
+	return query.Finalize()
+}
@@ -0,0 +1,196 @@
+package npy
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tiledb "github.com/TileDB-Inc/TileDB-Go"
+)
+
+func TestHeader(t *testing.T) {
+	for _, h := range []*Header{
+		{Descr: "<f8", Shape: []int{3, 4}},
+		{Descr: "|b1", FortranOrder: true, Shape: []int{5}},
+		{Descr: "<M8[ns]", Shape: []int{}},
+	} {
+		var buf bytes.Buffer
+		require.NoError(t, WriteHeader(&buf, h))
+		assert.Zero(t, buf.Len()%headerAlignment)
+
+		parsed, err := ReadHeader(&buf)
+		require.NoError(t, err)
+		assert.Equal(t, h, parsed)
+		assert.Zero(t, buf.Len())
+	}
+
+	h, err := parseHeader("{'descr': '>i4', 'fortran_order': True, 'shape': (2L, 3L), }      \n")
+	require.NoError(t, err)
+	assert.Equal(t, &Header{Descr: ">i4", FortranOrder: true, Shape: []int{2, 3}}, h)
+	datatype, err := h.Datatype()
+	require.NoError(t, err)
+	assert.Equal(t, tiledb.TILEDB_INT32, datatype)
+
+	for _, header := range []string{
+		"{'descr': [('a', '<i4')], 'fortran_order': False, 'shape': (1,)}",
+		"{'descr': '<i4', 'shape': (1,)}",
+		"{'descr': '<i4', 'fortran_order': false, 'shape': (1,)}",
+		"{'descr': '<i4', 'fortran_order': False, 'shape': (-1,)}",
+		"{'descr': '<i4', 'fortran_order': False, 'shape': (1,), 'other': 1}",
+	} {
+		_, err := parseHeader(header)
+		assert.Error(t, err, header)
+	}
+	_, err = ReadHeader(strings.NewReader("PK\x03\x04 not an NPY file"))
+	assert.Error(t, err)
+
+	t.Run("Descr", func(t *testing.T) {
+		for descr, datatype := range map[string]tiledb.Datatype{
+			"|b1":     tiledb.TILEDB_BOOL,
+			"|u1":     tiledb.TILEDB_UINT8,
+			"<i8":     tiledb.TILEDB_INT64,
+			"<f4":     tiledb.TILEDB_FLOAT32,
+			"<M8[D]":  tiledb.TILEDB_DATETIME_DAY,
+			"<m8[us]": tiledb.TILEDB_TIME_US,
+		} {
+			got, err := Descr(datatype)
+			require.NoError(t, err)
+			assert.Equal(t, descr, got)
+			gotDatatype, err := (&Header{Descr: descr}).Datatype()
+			require.NoError(t, err)
+			assert.Equal(t, datatype, gotDatatype)
+		}
+
+		_, err := Descr(tiledb.TILEDB_STRING_UTF8)
+		assert.Error(t, err)
+		_, err = (&Header{Descr: "<c16"}).Datatype()
+		assert.Error(t, err)
+	})
+}
+
+func TestImportExport(t *testing.T) {
+	tdbCtx, err := tiledb.NewContext(nil)
+	require.NoError(t, err)
+
+	data := make([]int32, 5*4)
+	for i := range data {
+		data[i] = int32(i)
+	}
+	input := npyBytes(t, &Header{Descr: "<i4", Shape: []int{5, 4}}, data)
+	npyPath := filepath.Join(t.TempDir(), "data.npy")
+	require.NoError(t, os.WriteFile(npyPath, input, 0o644))
+
+	// The tiles have 2 rows, so the data is written in 3 chunks.
+	arrayPath := t.TempDir()
+	require.NoError(t, ImportFile(tdbCtx, arrayPath, npyPath, ImportOptions{
+		Schema: SchemaOptions{Dimensions: []string{"y", "x"}, TileExtents: []uint64{2, 4}},
+	}))
+
+	array, err := tiledb.NewArray(tdbCtx, arrayPath)
+	require.NoError(t, err)
+	require.NoError(t, array.Open(tiledb.TILEDB_READ))
+	t.Cleanup(func() { require.NoError(t, array.Close()) })
+
+	var out bytes.Buffer
+	require.NoError(t, Export(tdbCtx, &out, array, nil, ""))
+	assert.Equal(t, input, out.Bytes())
+
+	t.Run("Subarray", func(t *testing.T) {
+		subarray, err := array.NewSubarray()
+		require.NoError(t, err)
+		defer subarray.Free()
+		require.NoError(t, subarray.SetSubArray([]uint64{1, 2, 1, 3}))
+
+		var out bytes.Buffer
+		require.NoError(t, Export(tdbCtx, &out, array, subarray, DefaultAttribute))
+		assert.Equal(t, npyBytes(t, &Header{Descr: "<i4", Shape: []int{2, 3}}, []int32{5, 6, 7, 9, 10, 11}), out.Bytes())
+	})
+
+	t.Run("FortranOrder", func(t *testing.T) {
+		// [[1, 2, 3], [4, 5, 6]] in column-major order, big-endian
+		input := npyBytes(t, &Header{Descr: ">f8", FortranOrder: true, Shape: []int{2, 3}}, []float64{1, 4, 2, 5, 3, 6})
+		arrayPath := t.TempDir()
+		schema, err := NewArraySchema(tdbCtx, map[string]*Header{"values": {Descr: ">f8", FortranOrder: true, Shape: []int{2, 3}}}, SchemaOptions{TileExtents: []uint64{2, 1}})
+		require.NoError(t, err)
+		defer schema.Free()
+		cellOrder, err := schema.CellOrder()
+		require.NoError(t, err)
+		assert.Equal(t, tiledb.TILEDB_COL_MAJOR, cellOrder)
+		require.NoError(t, tiledb.CreateArray(tdbCtx, arrayPath, schema))
+
+		array, err := tiledb.NewArray(tdbCtx, arrayPath)
+		require.NoError(t, err)
+		require.NoError(t, array.Open(tiledb.TILEDB_WRITE))
+		require.NoError(t, Import(tdbCtx, array, bytes.NewReader(input), ImportOptions{}))
+		require.NoError(t, array.Close())
+
+		require.NoError(t, array.Open(tiledb.TILEDB_READ))
+		defer array.Close()
+		var out bytes.Buffer
+		require.NoError(t, Export(tdbCtx, &out, array, nil, "values"))
+		assert.Equal(t, npyBytes(t, &Header{Descr: "<f8", Shape: []int{2, 3}}, []float64{1, 2, 3, 4, 5, 6}), out.Bytes())
+	})
+}
+
+func TestNPZ(t *testing.T) {
+	tdbCtx, err := tiledb.NewContext(nil)
+	require.NoError(t, err)
+
+	members := map[string][]byte{
+		"a.npy": npyBytes(t, &Header{Descr: "<i2", Shape: []int{2, 3}}, []int16{1, 2, 3, 4, 5, 6}),
+		"b.npy": npyBytes(t, &Header{Descr: "|b1", Shape: []int{2, 3}}, []bool{true, false, true, false, true, false}),
+	}
+	var npz bytes.Buffer
+	zw := zip.NewWriter(&npz)
+	for name, data := range members {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write(data)
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	npzPath := filepath.Join(t.TempDir(), "data.npz")
+	require.NoError(t, os.WriteFile(npzPath, npz.Bytes(), 0o644))
+
+	arrayPath := t.TempDir()
+	require.NoError(t, ImportFile(tdbCtx, arrayPath, npzPath, ImportOptions{}))
+
+	array, err := tiledb.NewArray(tdbCtx, arrayPath)
+	require.NoError(t, err)
+	require.NoError(t, array.Open(tiledb.TILEDB_READ))
+	t.Cleanup(func() { require.NoError(t, array.Close()) })
+
+	var out bytes.Buffer
+	require.NoError(t, ExportNPZ(tdbCtx, &out, array, nil, NPZOptions{Compress: true}))
+	zr, err := zip.NewReader(bytes.NewReader(out.Bytes()), int64(out.Len()))
+	require.NoError(t, err)
+	require.Len(t, zr.File, 2)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		data, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		require.NoError(t, rc.Close())
+		assert.Equal(t, members[f.Name], data, f.Name)
+	}
+
+	assert.Error(t, Export(tdbCtx, io.Discard, array, nil, ""))
+}
+
+// npyBytes returns the NPY file of the header and data.
+func npyBytes(t *testing.T, h *Header, data any) []byte {
+	var buf bytes.Buffer
+	require.NoError(t, WriteHeader(&buf, h))
+	order, err := h.byteOrder()
+	require.NoError(t, err)
+	require.NoError(t, binary.Write(&buf, order, data))
+	return buf.Bytes()
+}
@@ -0,0 +1,157 @@
+package npy
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+
+	tiledb "github.com/TileDB-Inc/TileDB-Go"
+)
+
+// DefaultTileCells is the maximum number of cells of the tiles of NewArraySchema
+// when SchemaOptions.TileExtents is empty.
+const DefaultTileCells = 1 << 20
+
+// DefaultAttribute is the name of the attribute of the arrays created from NPY files.
+const DefaultAttribute = "data"
+
+// SchemaOptions configures NewArraySchema.
+type SchemaOptions struct {
+	// Dimensions are the names of the dimensions, "d0", "d1", ... if empty.
+	Dimensions []string
+	// TileExtents are the tile extents of the dimensions. If empty, the extents are the shape,
+	// with the largest halved until the tiles have at most DefaultTileCells cells.
+	TileExtents []uint64
+}
+
+/*
+NewArraySchema returns the schema of a dense array that holds NPY arrays, with an attribute
+per entry of headers named after its key. The arrays must all have the same shape and order.
+
+The array has a TILEDB_UINT64 dimension per axis with the domain [0, n-1] of its size n.
+The cell and tile orders are TILEDB_ROW_MAJOR, or TILEDB_COL_MAJOR for arrays in Fortran order,
+so that the data of the NPY files can be written in tile-aligned chunks.
+*/
+func NewArraySchema(tdbCtx *tiledb.Context, headers map[string]*Header, opts SchemaOptions) (*tiledb.ArraySchema, error) {
+	if len(headers) == 0 {
+		return nil, errors.New("no NPY header to build the array schema from")
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	first := headers[names[0]]
+	for _, name := range names[1:] {
+		h := headers[name]
+		if !slices.Equal(h.Shape, first.Shape) || h.FortranOrder != first.FortranOrder {
+			return nil, fmt.Errorf("array %s does not have the shape and order of array %s", name, names[0])
+		}
+	}
+
+	schema, err := tiledb.NewArraySchema(tdbCtx, tiledb.TILEDB_DENSE)
+	if err != nil {
+		return nil, err
+	}
+	if err := setArraySchema(tdbCtx, schema, names, headers, first, opts); err != nil {
+		schema.Free()
+		return nil, err
+	}
+	return schema, nil
+}
+
+func setArraySchema(tdbCtx *tiledb.Context, schema *tiledb.ArraySchema, names []string, headers map[string]*Header, first *Header, opts SchemaOptions) error {
+	shape := first.Shape
+	if len(shape) == 0 {
+		return errors.New("cannot store a scalar NPY array in a dense array")
+	}
+	if slices.Contains(shape, 0) {
+		return fmt.Errorf("cannot store an NPY array of shape %v in a dense array", shape)
+	}
+	if len(opts.Dimensions) > 0 && len(opts.Dimensions) != len(shape) {
+		return fmt.Errorf("%d dimension names for an NPY array with %d dimensions", len(opts.Dimensions), len(shape))
+	}
+	extents := opts.TileExtents
+	if len(extents) == 0 {
+		extents = tileExtents(shape)
+	} else if len(extents) != len(shape) {
+		return fmt.Errorf("%d tile extents for an NPY array with %d dimensions", len(extents), len(shape))
+	}
+
+	domain, err := tiledb.NewDomain(tdbCtx)
+	if err != nil {
+		return err
+	}
+	defer domain.Free()
+
+	for i, n := range shape {
+		name := "d" + strconv.Itoa(i)
+		if len(opts.Dimensions) > 0 {
+			name = opts.Dimensions[i]
+		}
+		dimension, err := tiledb.NewDimension(tdbCtx, name, tiledb.TILEDB_UINT64, []uint64{0, uint64(n) - 1}, extents[i])
+		if err != nil {
+			return err
+		}
+		err = domain.AddDimensions(dimension)
+		dimension.Free()
+		if err != nil {
+			return err
+		}
+	}
+	if err := schema.SetDomain(domain); err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		datatype, err := headers[name].Datatype()
+		if err != nil {
+			return fmt.Errorf("array %s: %w", name, err)
+		}
+		attribute, err := tiledb.NewAttribute(tdbCtx, name, datatype)
+		if err != nil {
+			return err
+		}
+		err = schema.AddAttributes(attribute)
+		attribute.Free()
+		if err != nil {
+			return err
+		}
+	}
+
+	order := tiledb.TILEDB_ROW_MAJOR
+	if first.FortranOrder {
+		order = tiledb.TILEDB_COL_MAJOR
+	}
+	if err := schema.SetCellOrder(order); err != nil {
+		return err
+	}
+	if err := schema.SetTileOrder(order); err != nil {
+		return err
+	}
+
+	return schema.Check()
+}
+
+// tileExtents returns the default tile extents of an array of shape.
+func tileExtents(shape []int) []uint64 {
+	extents := make([]uint64, len(shape))
+	for i, n := range shape {
+		extents[i] = uint64(n)
+	}
+	for {
+		cells, largest := uint64(1), 0
+		for i, e := range extents {
+			cells *= e
+			if e > extents[largest] {
+				largest = i
+			}
+		}
+		if cells <= DefaultTileCells || extents[largest] == 1 {
+			return extents
+		}
+		extents[largest] = (extents[largest] + 1) / 2
+	}
+}
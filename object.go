@@ -45,6 +45,16 @@ type ObjectList struct {
 	objectList []groupDefinition
 }
 
+// Len returns the number of objects in the list.
+func (o *ObjectList) Len() int {
+	return len(o.objectList)
+}
+
+// At returns the path and the type of the object at index i, in visiting order.
+func (o *ObjectList) At(i int) (string, ObjectTypeEnum) {
+	return o.objectList[i].path, o.objectList[i].objectTypeEnum
+}
+
 //export objectsInPath
 func objectsInPath(path *C.cchar_t, objectTypeEnum C.tiledb_object_t, data unsafe.Pointer) int32 {
 	objectData := pointer.Restore(data).(*ObjectList)
@@ -83,8 +93,6 @@ func ObjectWalk(tdbCtx *Context, path string, walkOrder WalkOrder) (*ObjectList,
 		C.tiledb_walk_order_t(walkOrder), unsafe.Pointer(data))
 	runtime.KeepAlive(tdbCtx)
 
-	fmt.Println(objectList)
-
 	if ret != C.TILEDB_OK {
 		return nil, fmt.Errorf("cannot walk in path %s: %w", path,
 			tdbCtx.LastError())
@@ -0,0 +1,37 @@
+package tiledb
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestObjectWalk(t *testing.T) {
+	tdbCtx, err := NewContext(nil)
+	require.NoError(t, err)
+
+	groupURI := t.TempDir()
+	require.NoError(t, CreateGroup(tdbCtx, groupURI))
+	subgroupURI := filepath.Join(groupURI, "subgroup")
+	require.NoError(t, CreateGroup(tdbCtx, subgroupURI))
+	arrayURI := filepath.Join(subgroupURI, "array")
+	require.NoError(t, CreateArray(tdbCtx, arrayURI, buildArraySchema(tdbCtx, t)))
+
+	objects, err := ObjectWalk(tdbCtx, groupURI, TILEDB_PREORDER)
+	require.NoError(t, err)
+	require.Equal(t, 2, objects.Len())
+	path, objectType := objects.At(0)
+	assert.Contains(t, path, "subgroup")
+	assert.Equal(t, TILEDB_GROUP, objectType)
+	path, objectType = objects.At(1)
+	assert.Contains(t, path, "array")
+	assert.Equal(t, TILEDB_ARRAY, objectType)
+
+	objects, err = ObjectLs(tdbCtx, groupURI)
+	require.NoError(t, err)
+	require.Equal(t, 1, objects.Len())
+	_, objectType = objects.At(0)
+	assert.Equal(t, TILEDB_GROUP, objectType)
+}
@@ -7,12 +7,14 @@ package tiledb
 import "C"
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"reflect"
 	"runtime"
 	"sync"
+	"sync/atomic"
 	"unsafe"
 
 	"github.com/TileDB-Inc/TileDB-Go/bytesizes"
@@ -48,6 +50,12 @@ func (x queryHandle) Pin(pointer any) {
 	x.getState().pinner.Pin(pointer)
 }
 
+// Unpin unpins the buffers pinned so far. The core must not access them anymore:
+// they must all be replaced before the query is submitted again.
+func (x queryHandle) Unpin() {
+	x.getState().pinner.Unpin()
+}
+
 // Query construct and execute read/write queries on a tiledb Array
 type Query struct {
 	tiledbQuery          queryHandle
@@ -56,6 +64,13 @@ type Query struct {
 	config               *Config
 	bufferMutex          sync.Mutex
 	resultBufferElements map[string][3]*uint64
+
+	// State of the submissions running in the background, started by SubmitAsync or left
+	// running by a canceled SubmitContext: the query is only freed once they complete.
+	submitting atomic.Bool
+	inflightMu sync.Mutex
+	inflight   int
+	freed      bool
 }
 
 func newQueryFromHandle(context *Context, array *Array, handle queryHandle) *Query {
@@ -114,8 +129,33 @@ func NewQuery(tdbCtx *Context, array *Array) (*Query, error) {
 // called earlier to manually release memory if needed. Free is idempotent and
 // can safely be called many times on the same object; if it has already
 // been freed, it will not be freed again.
+// If a submission of the query runs in the background, see SubmitAsync and SubmitContext,
+// Free returns right away and the query is released once the submission completes.
 func (q *Query) Free() {
-	q.tiledbQuery.Free()
+	q.inflightMu.Lock()
+	defer q.inflightMu.Unlock()
+	q.freed = true
+	if q.inflight == 0 {
+		q.tiledbQuery.Free()
+	}
+}
+
+// beginInflight registers a submission running in the background, so that Free does not release the query.
+func (q *Query) beginInflight() {
+	q.inflightMu.Lock()
+	defer q.inflightMu.Unlock()
+	q.inflight++
+}
+
+// endInflight unregisters a submission started with beginInflight, and releases the query
+// if Free was called meanwhile.
+func (q *Query) endInflight() {
+	q.inflightMu.Lock()
+	defer q.inflightMu.Unlock()
+	q.inflight--
+	if q.inflight == 0 && q.freed {
+		q.tiledbQuery.Free()
+	}
 }
 
 // Context exposes the internal TileDB context used to initialize the query.
@@ -363,6 +403,50 @@ func (q *Query) Submit() error {
 	return nil
 }
 
+/*
+SubmitContext submits the query like Submit, but returns an error wrapping ErrCanceled as soon as ctx
+is done, without waiting for the query to complete. It does not submit the query if ctx is already done.
+For example, an HTTP handler can pass the context of its request to return when the client disconnects.
+
+The TileDB core can only cancel all the tasks of a Context, which would interrupt the other queries
+sharing it, so a canceled submission is not interrupted: it keeps running in the background until
+it completes. The query and its buffers must not be used anymore, only freed: Free releases the query
+once the submission completes.
+*/
+func (q *Query) SubmitContext(ctx context.Context) error {
+	_, err := q.submitContext(ctx)
+	return err
+}
+
+// submitContext is SubmitContext, and also returns a channel closed once the submission completes,
+// or nil if the query was not submitted.
+func (q *Query) submitContext(ctx context.Context) (<-chan struct{}, error) {
+	if ctx.Err() != nil {
+		return nil, canceledError(ctx)
+	}
+
+	result := make(chan error, 1)
+	completed := make(chan struct{})
+	q.beginInflight()
+	go func() {
+		defer close(completed)
+		defer q.endInflight()
+		result <- q.Submit()
+	}()
+
+	select {
+	case err := <-result:
+		return completed, err
+	case <-ctx.Done():
+		select {
+		case err := <-result:
+			return completed, err
+		default:
+			return completed, canceledError(ctx)
+		}
+	}
+}
+
 // Status returns the status of a query.
 func (q *Query) Status() (QueryStatus, error) {
 	var status C.tiledb_query_status_t
@@ -0,0 +1,315 @@
+package tiledb
+
+/*
+#include <tiledb/tiledb.h>
+#include <tiledb/tiledb_experimental.h>
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"unsafe"
+)
+
+// AggregateOperator is an operator that reduces the values of a single field
+// of a query to one value.
+type AggregateOperator uint8
+
+const (
+	// TILEDB_AGGREGATE_SUM sums the values of a numeric field. The result is int64 for
+	// signed integers, uint64 for unsigned integers and float64 for floating point fields.
+	TILEDB_AGGREGATE_SUM AggregateOperator = iota
+	// TILEDB_AGGREGATE_MIN finds the minimum value of a field. The result has the type of the field.
+	TILEDB_AGGREGATE_MIN
+	// TILEDB_AGGREGATE_MAX finds the maximum value of a field. The result has the type of the field.
+	TILEDB_AGGREGATE_MAX
+	// TILEDB_AGGREGATE_MEAN computes the mean of a numeric field. The result is float64.
+	TILEDB_AGGREGATE_MEAN
+	// TILEDB_AGGREGATE_NULL_COUNT counts the null cells of a nullable field. The result is uint64.
+	TILEDB_AGGREGATE_NULL_COUNT
+)
+
+// String returns a string representation.
+func (op AggregateOperator) String() string {
+	switch op {
+	case TILEDB_AGGREGATE_SUM:
+		return "SUM"
+	case TILEDB_AGGREGATE_MIN:
+		return "MIN"
+	case TILEDB_AGGREGATE_MAX:
+		return "MAX"
+	case TILEDB_AGGREGATE_MEAN:
+		return "MEAN"
+	case TILEDB_AGGREGATE_NULL_COUNT:
+		return "NULL_COUNT"
+	}
+
+	return "UNKNOWN"
+}
+
+// channelOperator returns the core operator for op. The returned pointer refers to a
+// static object of the library and must not be freed.
+func (op AggregateOperator) channelOperator(tdbCtx *Context) (*C.tiledb_channel_operator_t, error) {
+	var cOp *C.tiledb_channel_operator_t
+	var ret C.int32_t
+	switch op {
+	case TILEDB_AGGREGATE_SUM:
+		ret = C.tiledb_channel_operator_sum_get(tdbCtx.tiledbContext.Get(), &cOp)
+	case TILEDB_AGGREGATE_MIN:
+		ret = C.tiledb_channel_operator_min_get(tdbCtx.tiledbContext.Get(), &cOp)
+	case TILEDB_AGGREGATE_MAX:
+		ret = C.tiledb_channel_operator_max_get(tdbCtx.tiledbContext.Get(), &cOp)
+	case TILEDB_AGGREGATE_MEAN:
+		ret = C.tiledb_channel_operator_mean_get(tdbCtx.tiledbContext.Get(), &cOp)
+	case TILEDB_AGGREGATE_NULL_COUNT:
+		ret = C.tiledb_channel_operator_null_count_get(tdbCtx.tiledbContext.Get(), &cOp)
+	default:
+		return nil, fmt.Errorf("unrecognized aggregate operator: %d", op)
+	}
+	runtime.KeepAlive(tdbCtx)
+	if ret != C.TILEDB_OK {
+		return nil, fmt.Errorf("error getting %s aggregate operator: %w", op, tdbCtx.LastError())
+	}
+
+	return cOp, nil
+}
+
+// queryChannelState contains a native TileDB query channel handle and the context
+// needed to release it. The context is referenced to keep it alive until the channel is freed.
+type queryChannelState struct {
+	ptr     *C.tiledb_query_channel_t
+	context *Context
+}
+
+func freeCapiQueryChannelState(p unsafe.Pointer) {
+	h := (*queryChannelState)(p)
+	C.tiledb_query_channel_free(h.context.tiledbContext.Get(), &h.ptr)
+}
+
+type queryChannelHandle struct{ *capiHandle }
+
+func newQueryChannelHandle(context *Context, ptr *C.tiledb_query_channel_t) queryChannelHandle {
+	state := &queryChannelState{ptr: ptr, context: context}
+	return queryChannelHandle{newCapiHandle(unsafe.Pointer(state), freeCapiQueryChannelState)}
+}
+
+func (x queryChannelHandle) Get() *C.tiledb_query_channel_t {
+	return (*queryChannelState)(x.capiHandle.Get()).ptr
+}
+
+// QueryChannel is a stream of aggregated results of a query. Aggregate operations
+// are applied on a channel and their results are retrieved through buffers set on the query
+// with the output field name of the operation.
+type QueryChannel struct {
+	tiledbChannel queryChannelHandle
+	query         *Query
+	context       *Context
+}
+
+// DefaultChannel returns the default channel of the query. The default channel
+// aggregates all the cells of the query, i.e. the cells that fall in the subarray and
+// satisfy the query condition.
+func (q *Query) DefaultChannel() (*QueryChannel, error) {
+	var channelPtr *C.tiledb_query_channel_t
+	ret := C.tiledb_query_get_default_channel(q.context.tiledbContext.Get(), q.tiledbQuery.Get(), &channelPtr)
+	runtime.KeepAlive(q)
+	if ret != C.TILEDB_OK {
+		return nil, fmt.Errorf("error getting default query channel: %w", q.context.LastError())
+	}
+
+	return &QueryChannel{tiledbChannel: newQueryChannelHandle(q.context, channelPtr), query: q, context: q.context}, nil
+}
+
+// Free releases the internal TileDB core data that was allocated on the C heap.
+// It is automatically called when this object is garbage collected, but can be
+// called earlier to manually release memory if needed. Free is idempotent and
+// can safely be called many times on the same object; if it has already
+// been freed, it will not be freed again.
+func (c *QueryChannel) Free() {
+	c.tiledbChannel.Free()
+}
+
+// ApplyAggregate applies the aggregate operation on the channel. The result is
+// written to the buffer set on the query for outputFieldName, see NewAggregateResult.
+func (c *QueryChannel) ApplyAggregate(outputFieldName string, operation *ChannelOperation) error {
+	if operation == nil {
+		return errors.New("error applying aggregate: operation is nil")
+	}
+
+	cOutputFieldName := C.CString(outputFieldName)
+	defer C.free(unsafe.Pointer(cOutputFieldName))
+
+	ret := C.tiledb_channel_apply_aggregate(c.context.tiledbContext.Get(), c.tiledbChannel.Get(), cOutputFieldName, operation.get())
+	runtime.KeepAlive(c)
+	runtime.KeepAlive(operation)
+	if ret != C.TILEDB_OK {
+		return fmt.Errorf("error applying aggregate %s: %w", outputFieldName, c.context.LastError())
+	}
+
+	return nil
+}
+
+// channelOperationState contains a native TileDB channel operation handle and the context
+// needed to release it.
+type channelOperationState struct {
+	ptr     *C.tiledb_channel_operation_t
+	context *Context
+}
+
+func freeCapiChannelOperationState(p unsafe.Pointer) {
+	h := (*channelOperationState)(p)
+	C.tiledb_aggregate_free(h.context.tiledbContext.Get(), &h.ptr)
+}
+
+type channelOperationHandle struct{ *capiHandle }
+
+func newChannelOperationHandle(context *Context, ptr *C.tiledb_channel_operation_t) channelOperationHandle {
+	state := &channelOperationState{ptr: ptr, context: context}
+	return channelOperationHandle{newCapiHandle(unsafe.Pointer(state), freeCapiChannelOperationState)}
+}
+
+func (x channelOperationHandle) Get() *C.tiledb_channel_operation_t {
+	return (*channelOperationState)(x.capiHandle.Get()).ptr
+}
+
+// ChannelOperation is an aggregate operation that can be applied on a QueryChannel.
+type ChannelOperation struct {
+	// tiledbOperation is unset for COUNT, which is a static object of the library.
+	tiledbOperation channelOperationHandle
+	count           *C.tiledb_channel_operation_t
+	context         *Context
+}
+
+// NewCountAggregate returns the operation which counts the cells of the query.
+// The result is uint64.
+func NewCountAggregate(tdbCtx *Context) (*ChannelOperation, error) {
+	var operationPtr *C.tiledb_channel_operation_t
+	ret := C.tiledb_aggregate_count_get(tdbCtx.tiledbContext.Get(), &operationPtr)
+	runtime.KeepAlive(tdbCtx)
+	if ret != C.TILEDB_OK {
+		return nil, fmt.Errorf("error getting COUNT aggregate: %w", tdbCtx.LastError())
+	}
+
+	return &ChannelOperation{count: operationPtr, context: tdbCtx}, nil
+}
+
+// NewAggregate returns the operation which applies the operator op on the attribute
+// or dimension inputFieldName of the query.
+func NewAggregate(q *Query, op AggregateOperator, inputFieldName string) (*ChannelOperation, error) {
+	cOp, err := op.channelOperator(q.context)
+	if err != nil {
+		return nil, err
+	}
+
+	cInputFieldName := C.CString(inputFieldName)
+	defer C.free(unsafe.Pointer(cInputFieldName))
+
+	var operationPtr *C.tiledb_channel_operation_t
+	ret := C.tiledb_create_unary_aggregate(q.context.tiledbContext.Get(), q.tiledbQuery.Get(), cOp, cInputFieldName, &operationPtr)
+	runtime.KeepAlive(q)
+	if ret != C.TILEDB_OK {
+		return nil, fmt.Errorf("error creating %s aggregate on %s: %w", op, inputFieldName, q.context.LastError())
+	}
+
+	return &ChannelOperation{tiledbOperation: newChannelOperationHandle(q.context, operationPtr), context: q.context}, nil
+}
+
+// Free releases the internal TileDB core data that was allocated on the C heap.
+// It is automatically called when this object is garbage collected, but can be
+// called earlier to manually release memory if needed. Free is idempotent and
+// can safely be called many times on the same object; if it has already
+// been freed, it will not be freed again.
+func (op *ChannelOperation) Free() {
+	if op.tiledbOperation.capiHandle != nil {
+		op.tiledbOperation.Free()
+	}
+}
+
+func (op *ChannelOperation) get() *C.tiledb_channel_operation_t {
+	if op.count != nil {
+		return op.count
+	}
+	return op.tiledbOperation.Get()
+}
+
+// AggregateResult is the typed result buffer of an aggregate operation.
+type AggregateResult[T scalarType] struct {
+	query        *Query
+	name         string
+	data         []T
+	dataSize     *uint64
+	validity     []uint8
+	validitySize *uint64
+}
+
+// NewAggregateResult sets on the query the buffers which receive the result
+// of the aggregate with output field name outputFieldName. T must match the result type
+// of the operation: uint64 for COUNT and NULL_COUNT, float64 for MEAN, int64/uint64/float64
+// for SUM and the type of the input field for MIN and MAX. The result of SUM, MIN, MAX and MEAN
+// over a nullable field is nullable.
+func NewAggregateResult[T scalarType](q *Query, outputFieldName string, nullable bool) (*AggregateResult[T], error) {
+	r := &AggregateResult[T]{query: q, name: outputFieldName, data: make([]T, 1)}
+	var zero T
+	dataSize := uint64(unsafe.Sizeof(zero))
+	r.dataSize = &dataSize
+
+	cOutputFieldName := C.CString(outputFieldName)
+	defer C.free(unsafe.Pointer(cOutputFieldName))
+
+	q.bufferMutex.Lock()
+	defer q.bufferMutex.Unlock()
+
+	q.tiledbQuery.Pin(slicePtr(r.data))
+	q.tiledbQuery.Pin(r.dataSize)
+	ret := C.tiledb_query_set_data_buffer(q.context.tiledbContext.Get(), q.tiledbQuery.Get(), cOutputFieldName,
+		slicePtr(r.data), (*C.uint64_t)(unsafe.Pointer(r.dataSize)))
+	runtime.KeepAlive(q)
+	if ret != C.TILEDB_OK {
+		return nil, fmt.Errorf("error setting aggregate %s data buffer: %w", outputFieldName, q.context.LastError())
+	}
+
+	if nullable {
+		r.validity = make([]uint8, 1)
+		validitySize := uint64(1)
+		r.validitySize = &validitySize
+
+		q.tiledbQuery.Pin(slicePtr(r.validity))
+		q.tiledbQuery.Pin(r.validitySize)
+		ret = C.tiledb_query_set_validity_buffer(q.context.tiledbContext.Get(), q.tiledbQuery.Get(), cOutputFieldName,
+			(*C.uint8_t)(slicePtr(r.validity)), (*C.uint64_t)(unsafe.Pointer(r.validitySize)))
+		runtime.KeepAlive(q)
+		if ret != C.TILEDB_OK {
+			return nil, fmt.Errorf("error setting aggregate %s validity buffer: %w", outputFieldName, q.context.LastError())
+		}
+	}
+
+	return r, nil
+}
+
+// Value returns the aggregated value. It must be called after the query has completed;
+// aggregates are computed over all the submissions of an incomplete query and are only
+// available once its status is TILEDB_COMPLETED. The returned bool is false if the result is null,
+// e.g. the MIN of a nullable attribute whose cells are all null.
+func (r *AggregateResult[T]) Value() (T, bool, error) {
+	var zero T
+
+	status, err := r.query.Status()
+	if err != nil {
+		return zero, false, err
+	}
+	if status != TILEDB_COMPLETED {
+		return zero, false, fmt.Errorf("error getting aggregate %s: query status is %s", r.name, status)
+	}
+
+	if *r.dataSize == 0 {
+		return zero, false, nil
+	}
+	if r.validity != nil && (*r.validitySize == 0 || r.validity[0] == 0) {
+		return zero, false, nil
+	}
+
+	return r.data[0], true, nil
+}
@@ -0,0 +1,153 @@
+package tiledb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryAggregates(t *testing.T) {
+	array := create1DTestArray(t)
+	write1DTestArray(t, array, []int32{0, 1, 2, 3, 4, 5, 6, 7, 8, 9})
+
+	require.NoError(t, array.Open(TILEDB_READ))
+	t.Cleanup(func() { require.NoError(t, array.Close()) })
+
+	t.Run("AllOperators", func(t *testing.T) {
+		query, err := NewQuery(array.context, array)
+		require.NoError(t, err)
+		defer query.Free()
+
+		channel, err := query.DefaultChannel()
+		require.NoError(t, err)
+		defer channel.Free()
+
+		count, err := NewCountAggregate(array.context)
+		require.NoError(t, err)
+		require.NoError(t, channel.ApplyAggregate("count", count))
+
+		sum, err := NewAggregate(query, TILEDB_AGGREGATE_SUM, "v")
+		require.NoError(t, err)
+		defer sum.Free()
+		require.NoError(t, channel.ApplyAggregate("sum", sum))
+
+		minOp, err := NewAggregate(query, TILEDB_AGGREGATE_MIN, "v")
+		require.NoError(t, err)
+		defer minOp.Free()
+		require.NoError(t, channel.ApplyAggregate("min", minOp))
+
+		maxOp, err := NewAggregate(query, TILEDB_AGGREGATE_MAX, "v")
+		require.NoError(t, err)
+		defer maxOp.Free()
+		require.NoError(t, channel.ApplyAggregate("max", maxOp))
+
+		mean, err := NewAggregate(query, TILEDB_AGGREGATE_MEAN, "v")
+		require.NoError(t, err)
+		defer mean.Free()
+		require.NoError(t, channel.ApplyAggregate("mean", mean))
+
+		countResult, err := NewAggregateResult[uint64](query, "count", false)
+		require.NoError(t, err)
+		sumResult, err := NewAggregateResult[int64](query, "sum", false)
+		require.NoError(t, err)
+		minResult, err := NewAggregateResult[int32](query, "min", false)
+		require.NoError(t, err)
+		maxResult, err := NewAggregateResult[int32](query, "max", false)
+		require.NoError(t, err)
+		meanResult, err := NewAggregateResult[float64](query, "mean", false)
+		require.NoError(t, err)
+
+		require.NoError(t, query.Submit())
+
+		requireAggregate(t, countResult, uint64(10))
+		requireAggregate(t, sumResult, int64(45))
+		requireAggregate(t, minResult, int32(0))
+		requireAggregate(t, maxResult, int32(9))
+		requireAggregate(t, meanResult, 4.5)
+	})
+
+	t.Run("Subarray", func(t *testing.T) {
+		query, err := NewQuery(array.context, array)
+		require.NoError(t, err)
+		defer query.Free()
+
+		subarray, err := array.NewSubarray()
+		require.NoError(t, err)
+		require.NoError(t, subarray.AddRange(0, MakeRange[int8](2, 5)))
+		require.NoError(t, query.SetSubarray(subarray))
+
+		sumResult := applySumAggregate(t, query)
+		require.NoError(t, query.Submit())
+
+		requireAggregate(t, sumResult, int64(14))
+	})
+
+	t.Run("QueryCondition", func(t *testing.T) {
+		query, err := NewQuery(array.context, array)
+		require.NoError(t, err)
+		defer query.Free()
+
+		qc, err := NewQueryCondition(array.context, "v", TILEDB_QUERY_CONDITION_GE, int32(5))
+		require.NoError(t, err)
+		require.NoError(t, query.SetQueryCondition(qc))
+
+		sumResult := applySumAggregate(t, query)
+		require.NoError(t, query.Submit())
+
+		requireAggregate(t, sumResult, int64(35))
+	})
+
+	t.Run("Incomplete", func(t *testing.T) {
+		query, err := NewQuery(array.context, array)
+		require.NoError(t, err)
+		defer query.Free()
+
+		// The data buffer fits one tile only, so the query needs two submissions.
+		data := make([]int32, 6)
+		_, err = query.SetDataBuffer("v", data)
+		require.NoError(t, err)
+
+		sumResult := applySumAggregate(t, query)
+
+		submissions := 0
+		for {
+			require.NoError(t, query.Submit())
+			submissions++
+
+			status, err := query.Status()
+			require.NoError(t, err)
+			if status == TILEDB_COMPLETED {
+				break
+			}
+			require.Equal(t, TILEDB_INCOMPLETE, status)
+
+			_, _, err = sumResult.Value()
+			require.Error(t, err)
+		}
+		assert.Greater(t, submissions, 1)
+
+		requireAggregate(t, sumResult, int64(45))
+	})
+}
+
+func applySumAggregate(t *testing.T, query *Query) *AggregateResult[int64] {
+	channel, err := query.DefaultChannel()
+	require.NoError(t, err)
+
+	sum, err := NewAggregate(query, TILEDB_AGGREGATE_SUM, "v")
+	require.NoError(t, err)
+	require.NoError(t, channel.ApplyAggregate("sum", sum))
+
+	sumResult, err := NewAggregateResult[int64](query, "sum", false)
+	require.NoError(t, err)
+
+	return sumResult
+}
+
+func requireAggregate[T scalarType](t *testing.T, result *AggregateResult[T], expected T) {
+	value, valid, err := result.Value()
+	require.NoError(t, err)
+	require.True(t, valid)
+	assert.Equal(t, expected, value)
+}
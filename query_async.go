@@ -0,0 +1,103 @@
+package tiledb
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+)
+
+// SubmitPool bounds the number of queries submitted at once with Query.SubmitAsync.
+// Each submission blocks a thread in the TileDB core until it completes, so the pool keeps
+// the number of threads used by asynchronous submissions predictable. It is safe for concurrent use.
+type SubmitPool struct {
+	slots chan struct{}
+}
+
+// NewSubmitPool returns a pool that runs at most workers submissions at once.
+// If workers is not positive, runtime.GOMAXPROCS(0) is used.
+func NewSubmitPool(workers int) *SubmitPool {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	return &SubmitPool{slots: make(chan struct{}, workers)}
+}
+
+// Workers returns the maximum number of submissions the pool runs at once.
+func (p *SubmitPool) Workers() int {
+	return cap(p.slots)
+}
+
+// defaultSubmitPool is the pool used by SubmitAsync when none is given.
+var defaultSubmitPool = sync.OnceValue(func() *SubmitPool { return NewSubmitPool(0) })
+
+// SubmitResult is the outcome of an asynchronous submission.
+type SubmitResult struct {
+	// Status is the status of the query once the submission completed.
+	Status QueryStatus
+	// Err is the error of the submission, if any.
+	Err error
+}
+
+/*
+SubmitAsync submits the query on pool, or on a default pool of runtime.GOMAXPROCS(0) workers
+if pool is nil, without blocking. The returned channel receives the result when the submission
+completes and is then closed.
+
+The submission waits for a free worker of the pool; if ctx is done meanwhile, it is not run
+and the result has an ErrCanceled error. Otherwise the query is submitted with SubmitContext:
+if ctx is done while it runs, the result has an ErrCanceled error right away, and the submission
+keeps running in the background, and keeps its worker of the pool, until it completes.
+
+The query, its buffers and the pinning of its buffers stay valid until the submission completes:
+Free can be called meanwhile, the query is released once it completes. The query must not be
+modified, submitted or read from until the result is received, nor after a canceled result.
+*/
+func (q *Query) SubmitAsync(ctx context.Context, pool *SubmitPool) <-chan SubmitResult {
+	result := make(chan SubmitResult, 1)
+	if !q.submitting.CompareAndSwap(false, true) {
+		result <- SubmitResult{Status: TILEDB_UNINITIALIZED, Err: errors.New("error submitting query: query is already being submitted")}
+		close(result)
+		return result
+	}
+	if pool == nil {
+		pool = defaultSubmitPool()
+	}
+
+	q.beginInflight()
+	go func() {
+		defer close(result)
+		result <- q.submitInPool(ctx, pool)
+	}()
+	return result
+}
+
+// submitInPool submits the query once the pool has a free worker.
+func (q *Query) submitInPool(ctx context.Context, pool *SubmitPool) SubmitResult {
+	defer q.endInflight()
+	defer q.submitting.Store(false)
+
+	var err error
+	select {
+	case pool.slots <- struct{}{}:
+		var completed <-chan struct{}
+		completed, err = q.submitContext(ctx)
+		if completed == nil {
+			<-pool.slots
+			break
+		}
+		// The worker is released once the submission completes, after the result if it is canceled.
+		go func() {
+			<-completed
+			<-pool.slots
+		}()
+	case <-ctx.Done():
+		err = canceledError(ctx)
+	}
+
+	status, statusErr := q.Status()
+	if err == nil {
+		err = statusErr
+	}
+	return SubmitResult{Status: status, Err: err}
+}
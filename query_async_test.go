@@ -0,0 +1,126 @@
+package tiledb
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubmitAsync(t *testing.T) {
+	tdbCtx, err := NewContext(nil)
+	require.NoError(t, err)
+
+	arrayPath := t.TempDir()
+	require.NoError(t, CreateArray(tdbCtx, arrayPath, buildArraySchema(tdbCtx, t)))
+	array, err := NewArray(tdbCtx, arrayPath)
+	require.NoError(t, err)
+
+	require.NoError(t, array.Open(TILEDB_WRITE))
+	require.NoError(t, Write(tdbCtx, array, []struct {
+		Dim int8   `tiledb:"dim1"`
+		A1  int32  `tiledb:"a1"`
+		A2  string `tiledb:"a2"`
+	}{{Dim: 1, A1: 10, A2: "a"}, {Dim: 2, A1: 20, A2: "bb"}}, TILEDB_ROW_MAJOR))
+	require.NoError(t, array.Close())
+
+	require.NoError(t, array.Open(TILEDB_READ))
+	t.Cleanup(func() { require.NoError(t, array.Close()) })
+
+	newQuery := func(a1 []int32) *Query {
+		query, err := NewQuery(tdbCtx, array)
+		require.NoError(t, err)
+		require.NoError(t, query.SetLayout(TILEDB_ROW_MAJOR))
+		subarray, err := array.NewSubarray()
+		require.NoError(t, err)
+		require.NoError(t, subarray.SetSubArray([]int8{1, 2}))
+		require.NoError(t, query.SetSubarray(subarray))
+		_, err = query.SetDataBuffer("a1", a1)
+		require.NoError(t, err)
+		return query
+	}
+
+	pool := NewSubmitPool(2)
+	assert.Equal(t, 2, pool.Workers())
+
+	buffers := make([][]int32, 5)
+	results := make([]<-chan SubmitResult, len(buffers))
+	for i := range buffers {
+		buffers[i] = make([]int32, 2)
+		query := newQuery(buffers[i])
+		results[i] = query.SubmitAsync(context.Background(), pool)
+		// Free releases the query once the submission completes.
+		defer query.Free()
+	}
+	for i, result := range results {
+		res := <-result
+		require.NoError(t, res.Err)
+		assert.Equal(t, TILEDB_COMPLETED, res.Status)
+		assert.Equal(t, []int32{10, 20}, buffers[i])
+		_, ok := <-result
+		assert.False(t, ok)
+	}
+
+	t.Run("Canceled", func(t *testing.T) {
+		canceled, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		query := newQuery(make([]int32, 2))
+		defer query.Free()
+		res := <-query.SubmitAsync(canceled, nil)
+		assert.True(t, errors.Is(res.Err, ErrCanceled))
+		assert.Equal(t, TILEDB_UNINITIALIZED, res.Status)
+	})
+
+	t.Run("Free", func(t *testing.T) {
+		a1 := make([]int32, 2)
+		query := newQuery(a1)
+		result := query.SubmitAsync(context.Background(), nil)
+		query.Free()
+		res := <-result
+		require.NoError(t, res.Err)
+		assert.Equal(t, []int32{10, 20}, a1)
+	})
+
+	t.Run("InFlightCanceled", func(t *testing.T) {
+		large, cells := createLargeTestArray(t, tdbCtx)
+		newLargeQuery := func(end int64) (*Query, []int64) {
+			query, err := NewQuery(tdbCtx, large)
+			require.NoError(t, err)
+			require.NoError(t, query.SetLayout(TILEDB_ROW_MAJOR))
+			subarray, err := large.NewSubarray()
+			require.NoError(t, err)
+			require.NoError(t, subarray.AddRange(0, MakeRange[int64](0, end)))
+			require.NoError(t, query.SetSubarray(subarray))
+			ids := make([]int64, end+1)
+			_, err = query.SetDataBuffer("id", ids)
+			require.NoError(t, err)
+			return query, ids
+		}
+
+		for range 5 {
+			ctx, cancel := context.WithCancel(context.Background())
+			canceled, _ := newLargeQuery(int64(cells - 1))
+			other, ids := newLargeQuery(int64(cells - 1))
+			canceledResult := canceled.SubmitAsync(ctx, pool)
+			otherResult := other.SubmitAsync(context.Background(), pool)
+			time.Sleep(time.Millisecond)
+			cancel()
+
+			// Canceling a submission does not interrupt the other one on the same Context.
+			res := <-otherResult
+			require.NoError(t, res.Err)
+			assert.Equal(t, TILEDB_COMPLETED, res.Status)
+			assert.Equal(t, int64(cells-1), ids[cells-1])
+
+			// The canceled submission may have completed before being interrupted.
+			res = <-canceledResult
+			assert.True(t, res.Err == nil || errors.Is(res.Err, ErrCanceled), res.Err)
+			canceled.Free()
+			other.Free()
+		}
+	})
+}
@@ -0,0 +1,283 @@
+package tiledb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// DefaultBatchMemoryCap is the maximum total size of the buffers of Query.Batches
+// when BatchOptions.MemoryCap is zero: 1 GiB.
+const DefaultBatchMemoryCap = 1 << 30
+
+// ErrBatchMemoryCap is returned when the query buffers would need to grow
+// beyond the memory cap to hold a single result cell.
+var ErrBatchMemoryCap = errors.New("query buffers cannot grow beyond the memory cap")
+
+// BatchOptions configures Query.Batches.
+type BatchOptions struct {
+	// Fields are the attributes and dimensions to read. If empty, all of them are read.
+	Fields []string
+	// InitialCells is the number of cells the buffers are allocated for before the first submit.
+	// If zero, the buffers are sized from the estimated result size of the query.
+	InitialCells uint64
+	// MemoryCap is the maximum total size in bytes of the buffers.
+	// If zero, DefaultBatchMemoryCap is used.
+	MemoryCap uint64
+}
+
+// QueryBatch holds the results of one submit of a read query. Its slices are backed by the query
+// buffers and are only valid until the next batch is read; copy them to keep them longer.
+type QueryBatch struct {
+	// NumCells is the number of cells in the batch.
+	NumCells uint64
+
+	buffers map[string]*fieldBuffers
+}
+
+func (b *QueryBatch) fieldBuffers(name string) (*fieldBuffers, error) {
+	buffers, ok := b.buffers[name]
+	if !ok {
+		return nil, fmt.Errorf("%s is not read by the query batches", name)
+	}
+	return buffers, nil
+}
+
+// Data returns the data of name in the batch, a slice of the Go type of the datatype of name,
+// e.g. []int32 for TILEDB_INT32 or []uint8 for TILEDB_STRING_UTF8.
+func (b *QueryBatch) Data(name string) (any, error) {
+	buffers, err := b.fieldBuffers(name)
+	if err != nil {
+		return nil, err
+	}
+	return buffers.data.Slice(0, int(*buffers.dataSize/buffers.datatype.Size())).Interface(), nil
+}
+
+// Offsets returns the offsets in bytes of the cells of the var-sized attribute or dimension name in the batch.
+func (b *QueryBatch) Offsets(name string) ([]uint64, error) {
+	buffers, err := b.fieldBuffers(name)
+	if err != nil {
+		return nil, err
+	}
+	if !buffers.isVar() {
+		return nil, fmt.Errorf("%s is not var-sized", name)
+	}
+	return buffers.offsets[:b.NumCells], nil
+}
+
+// Validity returns the validity of the cells of the nullable attribute name in the batch. 0 means null.
+func (b *QueryBatch) Validity(name string) ([]uint8, error) {
+	buffers, err := b.fieldBuffers(name)
+	if err != nil {
+		return nil, err
+	}
+	if !buffers.nullable {
+		return nil, fmt.Errorf("%s is not nullable", name)
+	}
+	return buffers.validity[:b.NumCells], nil
+}
+
+/*
+Batches returns an iterator over the results of the read query q. It can be used with
+range-over-func, it has the type iter.Seq2[*QueryBatch, error]:
+
+	for batch, err := range query.Batches(tiledb.BatchOptions{}) {
+		if err != nil {
+			return err
+		}
+		a1, err := batch.Data("a1")
+		...
+	}
+
+The query is submitted until it completes and each submit that returned results is yielded as a batch.
+When the buffers cannot hold a single cell they are grown, up to BatchOptions.MemoryCap bytes in total.
+If they would need to grow beyond it the iterator yields ErrBatchMemoryCap. If the query keeps returning
+no results for another reason, such as its memory budget, the iterator yields an error with that reason.
+Iteration stops after the first error.
+Offsets are expected in the default format: 64-bit bytes offsets without an extra element.
+*/
+func (q *Query) Batches(opts BatchOptions) func(yield func(*QueryBatch, error) bool) {
+	return q.BatchesContext(context.Background(), opts)
+}
+
+// BatchesContext returns an iterator over the results of the read query q like Batches,
+// but the query is submitted with SubmitContext: the iterator yields an error wrapping ErrCanceled
+// when ctx is done before the query completes.
+func (q *Query) BatchesContext(ctx context.Context, opts BatchOptions) func(yield func(*QueryBatch, error) bool) {
+	return func(yield func(*QueryBatch, error) bool) {
+		buffers, err := batchBuffers(q, opts)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+
+		batch := &QueryBatch{buffers: make(map[string]*fieldBuffers, len(buffers))}
+		for _, b := range buffers {
+			batch.buffers[b.name] = b
+		}
+
+		memoryCap := opts.MemoryCap
+		if memoryCap == 0 {
+			memoryCap = DefaultBatchMemoryCap
+		}
+
+		err = submitBatches(ctx, q, buffers, memoryCap, func(numCells int) bool {
+			batch.NumCells = uint64(numCells)
+			return yield(batch, nil)
+		})
+		if err != nil {
+			yield(nil, err)
+		}
+	}
+}
+
+// batchBuffers allocates the buffers for the fields of opts.
+func batchBuffers(q *Query, opts BatchOptions) ([]*fieldBuffers, error) {
+	schema, err := q.array.Schema()
+	if err != nil {
+		return nil, fmt.Errorf("could not get array schema for Batches: %w", err)
+	}
+	defer schema.Free()
+
+	domain, err := schema.Domain()
+	if err != nil {
+		return nil, fmt.Errorf("could not get domain for Batches: %w", err)
+	}
+	defer domain.Free()
+
+	names := opts.Fields
+	if len(names) == 0 {
+		dimensions, attributes, err := schema.FieldNames()
+		if err != nil {
+			return nil, err
+		}
+		names = append(dimensions, attributes...)
+	}
+
+	buffers := make([]*fieldBuffers, len(names))
+	for i, name := range names {
+		f := structField{name: name}
+		f.datatype, f.cellValNum, f.nullable, err = fieldSchema(schema, domain, name)
+		if err != nil {
+			return nil, err
+		}
+
+		if opts.InitialCells > 0 {
+			buffers[i], err = newFieldBuffers(f, opts.InitialCells*f.cellElements(), opts.InitialCells)
+		} else {
+			buffers[i], err = estimateFieldBuffers(q, f)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not allocate buffers for Batches: %w", err)
+		}
+	}
+
+	return buffers, nil
+}
+
+// maxNoProgressSubmits is the number of consecutive submits returning no results, for another reason
+// than too small buffers, after which submitBatches gives up.
+const maxNoProgressSubmits = 100
+
+// submitBatches sets buffers on q and submits it until it completes. yield is called with
+// the number of cells of each submit that returned results, and submitBatches returns early if it returns false.
+// If the query returns no results because the buffers are too small, they are grown
+// up to memoryCap bytes in total.
+func submitBatches(ctx context.Context, q *Query, buffers []*fieldBuffers, memoryCap uint64, yield func(numCells int) bool) error {
+	// The estimated sizes can exceed the cap, shrink the buffers to fit.
+	if size := buffersSize(buffers); size > memoryCap {
+		if err := resizeBuffers(buffers, float64(memoryCap)/float64(size)); err != nil {
+			return err
+		}
+		if buffersSize(buffers) > memoryCap {
+			return ErrBatchMemoryCap
+		}
+	}
+
+	for _, b := range buffers {
+		if err := b.set(q); err != nil {
+			return fmt.Errorf("could not set query buffers: %w", err)
+		}
+	}
+
+	noProgress := 0
+	for {
+		if err := q.SubmitContext(ctx); err != nil {
+			return err
+		}
+
+		status, err := q.Status()
+		if err != nil {
+			return err
+		}
+		if status != TILEDB_COMPLETED && status != TILEDB_INCOMPLETE {
+			return fmt.Errorf("unexpected query status: %s", status)
+		}
+
+		numCells := buffers[0].numCells()
+		if numCells > 0 && !yield(numCells) {
+			return nil
+		}
+
+		if status == TILEDB_COMPLETED {
+			return nil
+		}
+		if numCells > 0 {
+			noProgress = 0
+			continue
+		}
+
+		details, err := q.StatusDetails()
+		if err != nil {
+			return err
+		}
+		if details.IncompleteReason != TILEDB_REASON_USER_BUFFER_SIZE {
+			// The query can make progress without larger buffers, but not forever
+			noProgress++
+			if noProgress >= maxNoProgressSubmits {
+				return fmt.Errorf("query returned no results after %d submits: incomplete with reason %s", noProgress, details.IncompleteReason)
+			}
+			continue
+		}
+
+		size := buffersSize(buffers)
+		if size >= memoryCap {
+			return ErrBatchMemoryCap
+		}
+		factor := math.Min(2, float64(memoryCap)/float64(size))
+		if err := resizeBuffers(buffers, factor); err != nil {
+			return err
+		}
+		if grown := buffersSize(buffers); grown <= size || grown > memoryCap {
+			return ErrBatchMemoryCap
+		}
+		// The previous buffers are replaced, so they are released
+		// instead of staying pinned until the query is freed.
+		q.tiledbQuery.Unpin()
+		for _, b := range buffers {
+			if err := b.set(q); err != nil {
+				return fmt.Errorf("could not set query buffers: %w", err)
+			}
+		}
+	}
+}
+
+// buffersSize returns the total size in bytes of buffers.
+func buffersSize(buffers []*fieldBuffers) uint64 {
+	var size uint64
+	for _, b := range buffers {
+		size += b.size()
+	}
+	return size
+}
+
+// resizeBuffers reallocates buffers with their capacity multiplied by factor.
+func resizeBuffers(buffers []*fieldBuffers, factor float64) error {
+	for _, b := range buffers {
+		if err := b.resize(factor); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+//go:build go1.23
+
+package tiledb
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryBatches(t *testing.T) {
+	array := createTypedTestArray(t)
+
+	var rows []typedTestRow
+	for i := range 10 {
+		row := typedTestRow{ID: int32(i + 1), Count: int32(10 * i), Name: strings.Repeat("x", i)}
+		if i%2 == 0 {
+			score := float64(i)
+			row.Score = &score
+		}
+		rows = append(rows, row)
+	}
+	require.NoError(t, array.Open(TILEDB_WRITE))
+	require.NoError(t, Write(array.context, array, rows, TILEDB_UNORDERED))
+	require.NoError(t, array.Close())
+
+	require.NoError(t, array.Open(TILEDB_READ))
+	t.Cleanup(func() { require.NoError(t, array.Close()) })
+
+	newQuery := func(t *testing.T) *Query {
+		query, err := NewQuery(array.context, array)
+		require.NoError(t, err)
+		t.Cleanup(query.Free)
+		require.NoError(t, query.SetLayout(TILEDB_ROW_MAJOR))
+		return query
+	}
+
+	t.Run("GrowBuffers", func(t *testing.T) {
+		query := newQuery(t)
+
+		var counts []int32
+		var names []string
+		var validity []uint8
+		var batches int
+		// Names are longer than a single byte so the buffers must grow
+		for batch, err := range query.Batches(BatchOptions{Fields: []string{"count", "name", "score"}, InitialCells: 1}) {
+			require.NoError(t, err)
+			batches++
+
+			data, err := batch.Data("count")
+			require.NoError(t, err)
+			counts = append(counts, data.([]int32)...)
+
+			data, err = batch.Data("name")
+			require.NoError(t, err)
+			offsets, err := batch.Offsets("name")
+			require.NoError(t, err)
+			nameData := data.([]uint8)
+			for i, offset := range offsets {
+				end := uint64(len(nameData))
+				if i+1 < len(offsets) {
+					end = offsets[i+1]
+				}
+				names = append(names, string(nameData[offset:end]))
+			}
+
+			v, err := batch.Validity("score")
+			require.NoError(t, err)
+			validity = append(validity, v...)
+
+			_, err = batch.Offsets("count")
+			require.Error(t, err)
+			_, err = batch.Validity("count")
+			require.Error(t, err)
+			_, err = batch.Data("id")
+			require.Error(t, err)
+		}
+
+		assert.Greater(t, batches, 1)
+		require.Len(t, counts, len(rows))
+		for i, row := range rows {
+			assert.Equal(t, row.Count, counts[i])
+			assert.Equal(t, row.Name, names[i])
+			assert.Equal(t, row.Score != nil, validity[i] == 1)
+		}
+	})
+
+	t.Run("AllFields", func(t *testing.T) {
+		query := newQuery(t)
+
+		var numCells uint64
+		for batch, err := range query.Batches(BatchOptions{}) {
+			require.NoError(t, err)
+			numCells += batch.NumCells
+
+			data, err := batch.Data("id")
+			require.NoError(t, err)
+			assert.Len(t, data, int(batch.NumCells))
+			data, err = batch.Data("point")
+			require.NoError(t, err)
+			assert.Len(t, data, 2*int(batch.NumCells))
+		}
+		assert.Equal(t, uint64(len(rows)), numCells)
+	})
+
+	t.Run("Break", func(t *testing.T) {
+		query := newQuery(t)
+
+		var batches int
+		for _, err := range query.Batches(BatchOptions{Fields: []string{"id"}, InitialCells: 2}) {
+			require.NoError(t, err)
+			batches++
+			break
+		}
+		assert.Equal(t, 1, batches)
+	})
+
+	t.Run("MemoryCap", func(t *testing.T) {
+		query := newQuery(t)
+
+		var lastErr error
+		for _, err := range query.Batches(BatchOptions{Fields: []string{"name"}, InitialCells: 1, MemoryCap: 10}) {
+			lastErr = err
+		}
+		require.True(t, errors.Is(lastErr, ErrBatchMemoryCap))
+	})
+
+	t.Run("UnknownField", func(t *testing.T) {
+		query := newQuery(t)
+
+		var lastErr error
+		for _, err := range query.Batches(BatchOptions{Fields: []string{"other"}}) {
+			lastErr = err
+		}
+		require.Error(t, lastErr)
+	})
+}
@@ -0,0 +1,365 @@
+package tiledb
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"sync"
+)
+
+// DefaultFlushSize is the size of the buffered cells at which BufferedWriter writes them
+// when BufferedWriterOptions.FlushSize is zero: 64 MiB.
+const DefaultFlushSize = 64 << 20
+
+// ErrWriterClosed is returned when cells are added to a closed BufferedWriter.
+var ErrWriterClosed = errors.New("buffered writer is closed")
+
+// BufferedWriterOptions configures a BufferedWriter.
+type BufferedWriterOptions struct {
+	// FlushSize is the size in bytes of the buffered cells, as they are laid out in the query buffers,
+	// at which they are written. It is the approximate size of the fragments.
+	// If zero, DefaultFlushSize is used.
+	FlushSize uint64
+	// GlobalOrder writes the cells with TILEDB_GLOBAL_ORDER layout in a single fragment,
+	// one submit per flush. The cells must be added in the global order of the array.
+	// Otherwise each flush is a TILEDB_UNORDERED write that creates a fragment.
+	GlobalOrder bool
+	// Workers is the number of unordered flushes that run at once. Add blocks while they are all running.
+	// If zero, runtime.GOMAXPROCS(0) is used. Global-order flushes run one at a time.
+	Workers int
+	// Consolidate consolidates the fragments written by the writer at Close, if there are several.
+	Consolidate bool
+	// ConsolidationConfig is the config of the consolidation. If nil, the config of the context is used.
+	ConsolidationConfig *Config
+}
+
+/*
+BufferedWriter collects the cells of a sparse array from many goroutines and writes them in batches
+of about BufferedWriterOptions.FlushSize bytes, to avoid both many tiny fragments and huge writes.
+The cells are rows of a struct type T, mapped to the array like with Write. The array must be open
+for writing until Close returns. Cells outside the current domain of the array make their flush
+fail with an error wrapping a *CurrentDomainError.
+
+Example:
+
+	w, err := tiledb.NewBufferedWriter[cell](tdbCtx, array, tiledb.BufferedWriterOptions{Consolidate: true})
+	...
+	// From any goroutine
+	err := w.Add(cells...)
+	...
+	err = w.Close()
+	uris := w.FragmentURIs()
+*/
+type BufferedWriter[T any] struct {
+	context *Context
+	array   *Array
+	opts    BufferedWriterOptions
+	fields  []structField
+	// currentDomain is the current domain of the array by dimension name, nil if it is empty.
+	currentDomain map[string]Range
+
+	mu     sync.Mutex
+	rows   []T
+	size   uint64
+	closed bool
+	// global is the query of global-order writes, created by the first flush.
+	global *Query
+
+	slots   chan struct{}
+	flushes sync.WaitGroup
+
+	resultMu sync.Mutex
+	uris     []string
+	err      error
+}
+
+// NewBufferedWriter returns a writer of rows of T to the sparse array, which must be open for writing.
+func NewBufferedWriter[T any](tdbCtx *Context, array *Array, opts BufferedWriterOptions) (*BufferedWriter[T], error) {
+	schema, err := array.Schema()
+	if err != nil {
+		return nil, fmt.Errorf("could not get array schema for BufferedWriter: %w", err)
+	}
+	defer schema.Free()
+
+	arrayType, err := schema.Type()
+	if err != nil {
+		return nil, fmt.Errorf("could not get array type for BufferedWriter: %w", err)
+	}
+	if arrayType != TILEDB_SPARSE {
+		return nil, errors.New("BufferedWriter only writes to sparse arrays")
+	}
+
+	fields, err := structFields(schema, genericType[T]())
+	if err != nil {
+		return nil, fmt.Errorf("could not map %s to array for BufferedWriter: %w", genericType[T](), err)
+	}
+	if _, err := checkWriteFields(schema, fields, arrayType); err != nil {
+		return nil, fmt.Errorf("could not map %s to array for BufferedWriter: %w", genericType[T](), err)
+	}
+	currentDomain, err := currentDomainRanges(schema)
+	if err != nil {
+		return nil, fmt.Errorf("could not get current domain for BufferedWriter: %w", err)
+	}
+
+	if opts.FlushSize == 0 {
+		opts.FlushSize = DefaultFlushSize
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = runtime.GOMAXPROCS(0)
+	}
+
+	return &BufferedWriter[T]{
+		context: tdbCtx,
+		array:   array,
+		opts:    opts,
+		fields:  fields,
+		slots:   make(chan struct{}, opts.Workers),
+
+		currentDomain: currentDomain,
+	}, nil
+}
+
+// Add buffers rows and writes the buffered cells if they reach the flush size.
+// It returns the error of a previous flush, if any. It is safe for concurrent use.
+func (w *BufferedWriter[T]) Add(rows ...T) error {
+	if err := w.flushErr(); err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return ErrWriterClosed
+	}
+	for _, row := range rows {
+		w.size += rowSize(w.fields, reflect.ValueOf(row))
+	}
+	w.rows = append(w.rows, rows...)
+	if w.size < w.opts.FlushSize {
+		w.mu.Unlock()
+		return nil
+	}
+	return w.flushLocked()
+}
+
+// Flush writes the buffered cells. Unordered flushes complete in the background, see Close.
+func (w *BufferedWriter[T]) Flush() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return ErrWriterClosed
+	}
+	return w.flushLocked()
+}
+
+// flushLocked writes the buffered cells and unlocks w.mu.
+// Global-order flushes are submitted with w.mu held so that they run in the order the cells were added.
+func (w *BufferedWriter[T]) flushLocked() error {
+	rows := w.rows
+	w.rows, w.size = nil, 0
+	if len(rows) == 0 {
+		w.mu.Unlock()
+		return w.flushErr()
+	}
+
+	if w.opts.GlobalOrder {
+		defer w.mu.Unlock()
+		if err := w.submitGlobal(rows); err != nil {
+			w.setErr(err)
+			return err
+		}
+		return nil
+	}
+	// The flush is counted before w.mu is unlocked so that Close waits for it.
+	w.flushes.Add(1)
+	w.mu.Unlock()
+
+	w.slots <- struct{}{}
+	go func() {
+		defer w.flushes.Done()
+		defer func() { <-w.slots }()
+		uri, err := w.writeUnordered(rows)
+		if err != nil {
+			w.setErr(err)
+			return
+		}
+		w.resultMu.Lock()
+		w.uris = append(w.uris, uri)
+		w.resultMu.Unlock()
+	}()
+	return w.flushErr()
+}
+
+// writeUnordered writes rows in a fragment and returns its URI.
+func (w *BufferedWriter[T]) writeUnordered(rows []T) (string, error) {
+	query, err := NewQuery(w.context, w.array)
+	if err != nil {
+		return "", err
+	}
+	defer query.Free()
+
+	if err := query.SetLayout(TILEDB_UNORDERED); err != nil {
+		return "", err
+	}
+	if err := w.setBuffers(query, rows); err != nil {
+		return "", err
+	}
+	if err := query.Submit(); err != nil {
+		return "", err
+	}
+	if err := query.Finalize(); err != nil {
+		return "", err
+	}
+	return fragmentURI(query)
+}
+
+// submitGlobal submits rows to the global-order query, which is created by the first call.
+func (w *BufferedWriter[T]) submitGlobal(rows []T) error {
+	if w.global == nil {
+		query, err := NewQuery(w.context, w.array)
+		if err != nil {
+			return err
+		}
+		if err := query.SetLayout(TILEDB_GLOBAL_ORDER); err != nil {
+			query.Free()
+			return err
+		}
+		w.global = query
+	} else {
+		// The buffers of the previous submit have been written, so they are released
+		// instead of staying pinned until the query is freed.
+		w.global.tiledbQuery.Unpin()
+	}
+
+	if err := w.setBuffers(w.global, rows); err != nil {
+		return err
+	}
+	return w.global.Submit()
+}
+
+// setBuffers sets the buffers of the fields of rows on query.
+func (w *BufferedWriter[T]) setBuffers(query *Query, rows []T) error {
+	rowsValue := reflect.ValueOf(rows)
+	for _, f := range w.fields {
+		b, err := encodeFieldBuffers(f, rowsValue)
+		if err != nil {
+			return fmt.Errorf("could not build buffers for BufferedWriter: %w", err)
+		}
+		if err := checkCurrentDomainCells(w.currentDomain, b); err != nil {
+			return err
+		}
+		if err := b.set(query); err != nil {
+			return fmt.Errorf("could not set buffers for BufferedWriter: %w", err)
+		}
+	}
+	return nil
+}
+
+/*
+Close writes the buffered cells, waits for the flushes to complete and finalizes the global-order write.
+If BufferedWriterOptions.Consolidate is set and the writer wrote several fragments, they are consolidated.
+It returns the first error of the flushes. Close can be called several times, the later calls
+only return the error.
+*/
+func (w *BufferedWriter[T]) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return w.flushErr()
+	}
+	w.closed = true
+	// Errors are recorded by the flush.
+	_ = w.flushLocked()
+	w.flushes.Wait()
+
+	if w.global != nil {
+		defer w.global.Free()
+		if err := w.global.Finalize(); err != nil {
+			w.setErr(err)
+		} else if uri, err := fragmentURI(w.global); err != nil {
+			w.setErr(err)
+		} else {
+			w.resultMu.Lock()
+			w.uris = append(w.uris, uri)
+			w.resultMu.Unlock()
+		}
+	}
+
+	if err := w.flushErr(); err != nil {
+		return err
+	}
+	if uris := w.FragmentURIs(); w.opts.Consolidate && len(uris) > 1 {
+		config := w.opts.ConsolidationConfig
+		if config == nil {
+			var err error
+			if config, err = w.context.Config(); err != nil {
+				return err
+			}
+		}
+		if err := w.array.ConsolidateFragments(config, uris); err != nil {
+			w.setErr(err)
+			return err
+		}
+	}
+	return nil
+}
+
+// FragmentURIs returns the URIs of the fragments written so far. The global-order fragment
+// is only listed once Close returns. The consolidation at Close does not change them.
+func (w *BufferedWriter[T]) FragmentURIs() []string {
+	w.resultMu.Lock()
+	defer w.resultMu.Unlock()
+	return append([]string(nil), w.uris...)
+}
+
+// setErr records the first error of the flushes.
+func (w *BufferedWriter[T]) setErr(err error) {
+	w.resultMu.Lock()
+	defer w.resultMu.Unlock()
+	if w.err == nil {
+		w.err = fmt.Errorf("could not flush buffered writer: %w", err)
+	}
+}
+
+// flushErr returns the first error of the flushes.
+func (w *BufferedWriter[T]) flushErr() error {
+	w.resultMu.Lock()
+	defer w.resultMu.Unlock()
+	return w.err
+}
+
+// fragmentURI returns the URI of the fragment written by the finalized write query.
+func fragmentURI(query *Query) (string, error) {
+	num, err := query.GetFragmentNum()
+	if err != nil {
+		return "", err
+	}
+	if *num != 1 {
+		return "", fmt.Errorf("write query created %d fragments", *num)
+	}
+	uri, err := query.GetFragmentURI(0)
+	if err != nil {
+		return "", err
+	}
+	return *uri, nil
+}
+
+// rowSize returns the size in bytes of the cells of row in the query buffers.
+func rowSize(fields []structField, row reflect.Value) uint64 {
+	var size uint64
+	for _, f := range fields {
+		if f.nullable {
+			size++
+		}
+		if !f.isVar() {
+			size += f.cellElements() * f.datatype.Size()
+			continue
+		}
+		size += 8 // offset
+		if v, ok := fieldValue(f, row); ok {
+			size += uint64(v.Len()) * f.datatype.Size()
+		}
+	}
+	return size
+}
@@ -0,0 +1,112 @@
+package tiledb
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBufferedWriter(t *testing.T) {
+	readCounts := func(t *testing.T, array *Array) map[int32]int32 {
+		require.NoError(t, array.Open(TILEDB_READ))
+		defer array.Close()
+		query, err := NewQuery(array.context, array)
+		require.NoError(t, err)
+		defer query.Free()
+		require.NoError(t, query.SetLayout(TILEDB_ROW_MAJOR))
+
+		var rows []typedTestRow
+		require.NoError(t, ReadInto(query, &rows))
+		counts := make(map[int32]int32, len(rows))
+		for _, row := range rows {
+			counts[row.ID] = row.Count
+		}
+		return counts
+	}
+
+	newRow := func(id int) typedTestRow {
+		return typedTestRow{ID: int32(id), Count: int32(10 * id), Name: fmt.Sprint(id)}
+	}
+
+	t.Run("Unordered", func(t *testing.T) {
+		array := createTypedTestArray(t)
+		require.NoError(t, array.Open(TILEDB_WRITE))
+
+		// Each row takes about 60 bytes: the flushes write 10 to 20 rows.
+		w, err := NewBufferedWriter[typedTestRow](array.context, array, BufferedWriterOptions{FlushSize: 600, Workers: 2, Consolidate: true})
+		require.NoError(t, err)
+
+		want := make(map[int32]int32)
+		var wg sync.WaitGroup
+		for g := range 4 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := range 25 {
+					assert.NoError(t, w.Add(newRow(1+g*25+i)))
+				}
+			}()
+			for i := range 25 {
+				want[int32(1+g*25+i)] = int32(10 * (1 + g*25 + i))
+			}
+		}
+		wg.Wait()
+		require.NoError(t, w.Close())
+		require.NoError(t, w.Close())
+		assert.True(t, errors.Is(w.Add(newRow(1)), ErrWriterClosed))
+		require.NoError(t, array.Close())
+
+		uris := w.FragmentURIs()
+		assert.GreaterOrEqual(t, len(uris), 5)
+
+		fragmentInfo, err := NewFragmentInfo(array.context, array.uri)
+		require.NoError(t, err)
+		defer fragmentInfo.Free()
+		require.NoError(t, fragmentInfo.Load())
+		num, err := fragmentInfo.GetFragmentNum()
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, num)
+		toVacuum, err := fragmentInfo.GetToVacuumNum()
+		require.NoError(t, err)
+		assert.EqualValues(t, len(uris), toVacuum)
+
+		assert.Equal(t, want, readCounts(t, array))
+	})
+
+	t.Run("GlobalOrder", func(t *testing.T) {
+		array := createTypedTestArray(t)
+		require.NoError(t, array.Open(TILEDB_WRITE))
+
+		w, err := NewBufferedWriter[typedTestRow](array.context, array, BufferedWriterOptions{FlushSize: 600, GlobalOrder: true})
+		require.NoError(t, err)
+		want := make(map[int32]int32)
+		for id := 1; id <= 50; id++ {
+			require.NoError(t, w.Add(newRow(id)))
+			want[int32(id)] = int32(10 * id)
+		}
+		assert.Empty(t, w.FragmentURIs())
+		require.NoError(t, w.Close())
+		require.NoError(t, array.Close())
+
+		assert.Len(t, w.FragmentURIs(), 1)
+		assert.Equal(t, want, readCounts(t, array))
+	})
+
+	t.Run("Dense", func(t *testing.T) {
+		tdbCtx, err := NewContext(nil)
+		require.NoError(t, err)
+		arrayPath := t.TempDir()
+		require.NoError(t, CreateArray(tdbCtx, arrayPath, buildArraySchema(tdbCtx, t)))
+		array, err := NewArray(tdbCtx, arrayPath)
+		require.NoError(t, err)
+		require.NoError(t, array.Open(TILEDB_WRITE))
+		defer array.Close()
+
+		_, err = NewBufferedWriter[typedTestRow](tdbCtx, array, BufferedWriterOptions{})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,118 @@
+package tiledb
+
+/*
+#include <tiledb/tiledb.h>
+#include <tiledb/tiledb_experimental.h>
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"unsafe"
+
+	"github.com/TileDB-Inc/TileDB-Go/bytesizes"
+)
+
+// NewQueryConditionSetMembership allocates a query condition which tests whether the value of
+// fieldName is (TILEDB_QUERY_CONDITION_IN) or is not (TILEDB_QUERY_CONDITION_NOT_IN) one of values.
+// values must be a non-empty slice of a numeric type matching the field or a []string for var-sized fields.
+// For attributes with enumerations values can be a []string with the enumeration labels,
+// see QueryCondition.UseEnumeration.
+func NewQueryConditionSetMembership(tdbCtx *Context, fieldName string, op QueryConditionOp, values interface{}) (*QueryCondition, error) {
+	if op != TILEDB_QUERY_CONDITION_IN && op != TILEDB_QUERY_CONDITION_NOT_IN {
+		return nil, fmt.Errorf("set membership query condition requires IN or NOT IN op, got %d", op)
+	}
+
+	switch values := values.(type) {
+	case []int:
+		return qcSetMembershipSlice(tdbCtx, fieldName, op, values)
+	case []int8:
+		return qcSetMembershipSlice(tdbCtx, fieldName, op, values)
+	case []int16:
+		return qcSetMembershipSlice(tdbCtx, fieldName, op, values)
+	case []int32:
+		return qcSetMembershipSlice(tdbCtx, fieldName, op, values)
+	case []int64:
+		return qcSetMembershipSlice(tdbCtx, fieldName, op, values)
+	case []uint:
+		return qcSetMembershipSlice(tdbCtx, fieldName, op, values)
+	case []uint8:
+		return qcSetMembershipSlice(tdbCtx, fieldName, op, values)
+	case []uint16:
+		return qcSetMembershipSlice(tdbCtx, fieldName, op, values)
+	case []uint32:
+		return qcSetMembershipSlice(tdbCtx, fieldName, op, values)
+	case []uint64:
+		return qcSetMembershipSlice(tdbCtx, fieldName, op, values)
+	case []float32:
+		return qcSetMembershipSlice(tdbCtx, fieldName, op, values)
+	case []float64:
+		return qcSetMembershipSlice(tdbCtx, fieldName, op, values)
+	case []bool:
+		return qcSetMembershipSlice(tdbCtx, fieldName, op, values)
+	case []string:
+		if len(values) == 0 {
+			return nil, errors.New("set membership query condition requires at least one value")
+		}
+		var size int
+		for _, v := range values {
+			size += len(v)
+		}
+		data := make([]byte, 0, size)
+		offsets := make([]uint64, len(values))
+		for i, v := range values {
+			offsets[i] = uint64(len(data))
+			data = append(data, v...)
+		}
+		// The core rejects a nil data pointer, which is what an empty []byte gives,
+		// so we point to a dummy byte when all the strings are empty.
+		if len(data) == 0 {
+			data = make([]byte, 1)
+		}
+		return qcSetMembershipInternal(tdbCtx, fieldName, op, slicePtr(data), uint64(size), offsets)
+	}
+
+	return nil, fmt.Errorf("cannot create set membership query condition for type %T", values)
+}
+
+func qcSetMembershipSlice[T scalarType](tdbCtx *Context, fieldName string, op QueryConditionOp, values []T) (*QueryCondition, error) {
+	if len(values) == 0 {
+		return nil, errors.New("set membership query condition requires at least one value")
+	}
+
+	var t T
+	elemSize := uint64(unsafe.Sizeof(t))
+	offsets := make([]uint64, len(values))
+	for i := range values {
+		offsets[i] = uint64(i) * elemSize
+	}
+
+	return qcSetMembershipInternal(tdbCtx, fieldName, op, slicePtr(values), elemSize*uint64(len(values)), offsets)
+}
+
+func qcSetMembershipInternal(tdbCtx *Context, fieldName string, op QueryConditionOp, data unsafe.Pointer, dataSize uint64, offsets []uint64) (*QueryCondition, error) {
+	cname := C.CString(fieldName)
+	defer C.free(unsafe.Pointer(cname))
+
+	var qcPtr *C.tiledb_query_condition_t
+	ret := C.tiledb_query_condition_alloc_set_membership(
+		tdbCtx.tiledbContext.Get(),
+		cname,
+		data,
+		C.uint64_t(dataSize),
+		slicePtr(offsets),
+		C.uint64_t(uint64(len(offsets))*bytesizes.Uint64),
+		C.tiledb_query_condition_op_t(op),
+		&qcPtr,
+	)
+	runtime.KeepAlive(tdbCtx)
+	// data and offsets are being kept alive by passing them to the cgo call.
+	if ret != C.TILEDB_OK {
+		return nil, fmt.Errorf("could not create %q set membership query condition: %w", fieldName, tdbCtx.LastError())
+	}
+
+	return newQueryConditionFromHandle(tdbCtx, newQueryConditionHandle(qcPtr)), nil
+}
@@ -0,0 +1,129 @@
+package tiledb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryConditionSetMembership(t *testing.T) {
+	array, err := createBasicTestArray(t)
+	require.NoError(t, err)
+	require.NoError(t, array.Open(TILEDB_READ))
+	t.Cleanup(func() { require.NoError(t, array.Close()) })
+
+	cases := []struct {
+		name           string
+		field          string
+		op             QueryConditionOp
+		values         interface{}
+		expectedValues []int32
+	}{
+		{"Int32In", "a1", TILEDB_QUERY_CONDITION_IN, []int32{1, 3, 7}, []int32{1, 3}},
+		{"Int32NotIn", "a1", TILEDB_QUERY_CONDITION_NOT_IN, []int32{1, 3, 7}, []int32{2}},
+		{"StringIn", "a2", TILEDB_QUERY_CONDITION_IN, []string{"ama", "string", "other"}, []int32{2, 3}},
+		{"StringNotIn", "a2", TILEDB_QUERY_CONDITION_NOT_IN, []string{"ama", "string"}, []int32{1}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			query, err := NewQuery(array.context, array)
+			require.NoError(t, err)
+			defer query.Free()
+
+			a1DataRead := make([]int32, 3)
+			_, err = query.SetDataBuffer("a1", a1DataRead)
+			require.NoError(t, err)
+
+			qc, err := NewQueryConditionSetMembership(array.context, c.field, c.op, c.values)
+			require.NoError(t, err)
+			require.NoError(t, query.SetQueryCondition(qc))
+			require.NoError(t, query.SetLayout(TILEDB_ROW_MAJOR))
+
+			require.NoError(t, query.Submit())
+
+			elements, err := query.ResultBufferElements()
+			require.NoError(t, err)
+			assert.Equal(t, c.expectedValues, a1DataRead[:elements["a1"][1]])
+		})
+	}
+
+	t.Run("InvalidOp", func(t *testing.T) {
+		_, err := NewQueryConditionSetMembership(array.context, "a1", TILEDB_QUERY_CONDITION_EQ, []int32{1})
+		require.Error(t, err)
+	})
+
+	t.Run("EmptySet", func(t *testing.T) {
+		_, err := NewQueryConditionSetMembership(array.context, "a1", TILEDB_QUERY_CONDITION_IN, []int32{})
+		require.Error(t, err)
+	})
+
+	t.Run("UnsupportedType", func(t *testing.T) {
+		_, err := NewQueryConditionSetMembership(array.context, "a1", TILEDB_QUERY_CONDITION_IN, int32(1))
+		require.Error(t, err)
+	})
+}
+
+func TestQueryConditionSetMembershipEnumeration(t *testing.T) {
+	schema := arraySchemaWithEnumerations(t)
+	tdbCtx := schema.context
+
+	arrayPath := t.TempDir()
+	require.NoError(t, CreateArray(tdbCtx, arrayPath, schema))
+
+	array, err := NewArray(tdbCtx, arrayPath)
+	require.NoError(t, err)
+	require.NoError(t, array.Open(TILEDB_WRITE))
+	wQuery, err := NewQuery(tdbCtx, array)
+	require.NoError(t, err)
+	_, err = wQuery.SetDataBuffer("rows", []uint8{1, 1, 1, 1})
+	require.NoError(t, err)
+	_, err = wQuery.SetDataBuffer("cols", []uint8{1, 2, 3, 4})
+	require.NoError(t, err)
+	_, err = wQuery.SetDataBuffer("greek", []uint8{0, 1, 2, 3})
+	require.NoError(t, err)
+	_, err = wQuery.SetDataBuffer("roman", []uint8{0, 1, 2, 3})
+	require.NoError(t, err)
+	require.NoError(t, wQuery.Submit())
+	require.NoError(t, array.Close())
+
+	require.NoError(t, array.Open(TILEDB_READ))
+	defer array.Close()
+
+	t.Run("EnumerationsEnabled", func(t *testing.T) {
+		rQuery, err := NewQuery(tdbCtx, array)
+		require.NoError(t, err)
+		qc, err := NewQueryConditionSetMembership(tdbCtx, "roman", TILEDB_QUERY_CONDITION_IN, []string{"ii", "iv"})
+		require.NoError(t, err)
+		require.NoError(t, rQuery.SetQueryCondition(qc))
+
+		colsBuffer := make([]uint8, 4)
+		_, err = rQuery.SetDataBuffer("cols", colsBuffer)
+		require.NoError(t, err)
+
+		require.NoError(t, rQuery.Submit())
+
+		elements, err := rQuery.ResultBufferElements()
+		require.NoError(t, err)
+		assert.Equal(t, []uint8{2, 4}, colsBuffer[:elements["cols"][1]])
+	})
+
+	t.Run("EnumerationsDisabled", func(t *testing.T) {
+		rQuery, err := NewQuery(tdbCtx, array)
+		require.NoError(t, err)
+		qc, err := NewQueryConditionSetMembership(tdbCtx, "roman", TILEDB_QUERY_CONDITION_NOT_IN, []uint8{1, 3})
+		require.NoError(t, err)
+		require.NoError(t, qc.UseEnumeration(false))
+		require.NoError(t, rQuery.SetQueryCondition(qc))
+
+		colsBuffer := make([]uint8, 4)
+		_, err = rQuery.SetDataBuffer("cols", colsBuffer)
+		require.NoError(t, err)
+
+		require.NoError(t, rQuery.Submit())
+
+		elements, err := rQuery.ResultBufferElements()
+		require.NoError(t, err)
+		assert.Equal(t, []uint8{1, 3}, colsBuffer[:elements["cols"][1]])
+	})
+}
@@ -0,0 +1,397 @@
+package tiledb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"runtime"
+	"sync"
+)
+
+// DefaultPrefetch is the number of batches each worker of ReadParallel reads ahead when ParallelReadOptions.Prefetch is zero.
+const DefaultPrefetch = 2
+
+// ParallelReadOptions configures ReadParallel.
+type ParallelReadOptions struct {
+	// Batch configures the batches of each partition. Each worker has its own query buffers
+	// and its own copies of the batches read ahead, all of at most Batch.MemoryCap bytes,
+	// so up to Workers times (Prefetch + 3) times Batch.MemoryCap bytes are allocated.
+	Batch BatchOptions
+	// Workers is the number of partitions read at once. If zero, runtime.GOMAXPROCS(0) is used.
+	Workers int
+	// TilesPerPartition is the number of tiles along the first dimension of each partition.
+	// If zero, each partition is a single tile.
+	TilesPerPartition uint64
+	// Prefetch is the number of batches each worker reads ahead of the consumer.
+	// If zero, DefaultPrefetch is used.
+	Prefetch int
+}
+
+// integerType are the datatypes of the dimensions ReadParallel can partition.
+type integerType interface {
+	int8 | int16 | int32 | int64 | uint8 | uint16 | uint32 | uint64
+}
+
+/*
+ReadParallel returns an iterator over the results of the subarray of array, which must be open for
+reading, like Query.Batches. The subarray is split along the tiles of the first dimension into partitions
+that are read by sub-queries with their own buffers on opts.Workers goroutines. The batches are yielded
+in row-major order: all the batches of a partition, then the batches of the next one.
+
+The subarray must have a single range per dimension and the first dimension must have an integer datatype.
+If subarray is nil, the non-empty domain of the array is read. For sparse arrays the range of the first
+dimension is also limited to the non-empty domain so that no partition is empty.
+
+The workers copy the batches they read, so that they keep reading up to opts.Prefetch batches ahead
+of the consumer while it processes the current one. A batch is only valid until the next one is read:
+its memory is then reused by the worker that read it.
+Iteration stops after the first error. The sub-queries are submitted with SubmitContext, so canceling ctx
+stops the iteration without waiting for the submissions in flight.
+*/
+func ReadParallel(ctx context.Context, tdbCtx *Context, array *Array, subarray *Subarray, opts ParallelReadOptions) func(yield func(*QueryBatch, error) bool) {
+	return func(yield func(*QueryBatch, error) bool) {
+		prefetch := opts.Prefetch
+		if prefetch <= 0 {
+			prefetch = DefaultPrefetch
+		}
+		partitions, err := partitionSubarray(array, subarray, opts.TilesPerPartition, prefetch)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		if len(partitions) == 0 {
+			return
+		}
+
+		workers := opts.Workers
+		if workers <= 0 {
+			workers = runtime.GOMAXPROCS(0)
+		}
+		workers = min(workers, len(partitions))
+
+		// Partitions are taken in order, so the partition read by the consumer is always being read by a worker.
+		jobs := make(chan *readPartition, len(partitions))
+		for _, p := range partitions {
+			jobs <- p
+		}
+		close(jobs)
+
+		stop := make(chan struct{})
+		var wg sync.WaitGroup
+		defer func() {
+			close(stop)
+			wg.Wait()
+		}()
+
+		wg.Add(workers)
+		for range workers {
+			go func() {
+				defer wg.Done()
+				// The copies of the batches rotate between the worker and the consumer, which gives them back
+				// once yielded. The worker allocates a copy when none is available: at most prefetch are
+				// in the batches channel, one is being sent and one is being yielded.
+				spare := make(chan *QueryBatch, prefetch+2)
+				for p := range jobs {
+					p.spare = spare
+					p.read(ctx, tdbCtx, array, opts.Batch, stop)
+				}
+			}()
+		}
+
+		for _, p := range partitions {
+			for batch := range p.batches {
+				if !yield(batch, nil) {
+					return
+				}
+				select {
+				case p.spare <- batch:
+				default:
+				}
+			}
+			if p.err != nil {
+				yield(nil, fmt.Errorf("could not read partition %v: %w", p.ranges[0], p.err))
+				return
+			}
+		}
+	}
+}
+
+// readPartition is a part of the subarray of ReadParallel, read by a single query.
+type readPartition struct {
+	// ranges are the ranges of the partition, per dimension. Dimensions without a range are not restricted.
+	ranges []Range
+
+	// batches receives copies of the batches of the partition and is closed when it has been read.
+	// It is buffered, so the worker reads ahead of the consumer. err is set before batches is closed.
+	batches chan *QueryBatch
+	err     error
+	// spare receives the copies the consumer is done with, for the worker to reuse. It is set before the first batch is sent.
+	spare chan *QueryBatch
+}
+
+// read reads the partition and sends its batches. It returns early when stop is closed.
+func (p *readPartition) read(ctx context.Context, tdbCtx *Context, array *Array, opts BatchOptions, stop <-chan struct{}) {
+	defer close(p.batches)
+	select {
+	case <-stop:
+		return
+	default:
+	}
+
+	query, err := NewQuery(tdbCtx, array)
+	if err != nil {
+		p.err = err
+		return
+	}
+	defer query.Free()
+
+	if p.err = query.SetLayout(TILEDB_ROW_MAJOR); p.err != nil {
+		return
+	}
+	subarray, err := array.NewSubarray()
+	if err != nil {
+		p.err = err
+		return
+	}
+	defer subarray.Free()
+	for d, r := range p.ranges {
+		if r.start == nil {
+			continue
+		}
+		if p.err = subarray.AddRange(uint32(d), r); p.err != nil {
+			return
+		}
+	}
+	if p.err = query.SetSubarray(subarray); p.err != nil {
+		return
+	}
+
+	query.BatchesContext(ctx, opts)(func(batch *QueryBatch, batchErr error) bool {
+		if batchErr != nil {
+			p.err = batchErr
+			return false
+		}
+		var dst *QueryBatch
+		select {
+		case dst = <-p.spare:
+		default:
+		}
+		select {
+		case p.batches <- copyBatch(batch, dst):
+			return true
+		case <-stop:
+			return false
+		}
+	})
+}
+
+// copyBatch returns a copy of the results of batch, reusing the memory of dst if it is not nil.
+func copyBatch(batch, dst *QueryBatch) *QueryBatch {
+	if dst == nil {
+		dst = &QueryBatch{buffers: make(map[string]*fieldBuffers, len(batch.buffers))}
+	}
+	dst.NumCells = batch.NumCells
+	for name, b := range batch.buffers {
+		dst.buffers[name] = b.copyResults(dst.buffers[name], int(batch.NumCells))
+	}
+	return dst
+}
+
+// copyResults returns a copy of the results of the last submit of b, numCells cells,
+// reusing the memory of dst if it is not nil.
+func (b *fieldBuffers) copyResults(dst *fieldBuffers, numCells int) *fieldBuffers {
+	if dst == nil {
+		dst = &fieldBuffers{structField: b.structField, dataSize: new(uint64), offsetsSize: new(uint64), validitySize: new(uint64)}
+	}
+
+	n := int(*b.dataSize / b.datatype.Size())
+	if dst.data.IsValid() && dst.data.Cap() >= n {
+		dst.data = dst.data.Slice(0, n)
+	} else {
+		dst.data = reflect.MakeSlice(b.data.Type(), n, n)
+	}
+	reflect.Copy(dst.data, b.data.Slice(0, n))
+	*dst.dataSize = *b.dataSize
+
+	if b.isVar() {
+		dst.offsets = append(dst.offsets[:0], b.offsets[:numCells]...)
+		*dst.offsetsSize = *b.offsetsSize
+	}
+	if b.nullable {
+		dst.validity = append(dst.validity[:0], b.validity[:numCells]...)
+		*dst.validitySize = *b.validitySize
+	}
+	return dst
+}
+
+// partitionSubarray splits the subarray, or the non-empty domain if it is nil, along the tiles
+// of the first dimension of the array, tilesPerPartition tiles per partition. Each partition
+// buffers prefetch batches.
+func partitionSubarray(array *Array, subarray *Subarray, tilesPerPartition uint64, prefetch int) ([]*readPartition, error) {
+	schema, err := array.Schema()
+	if err != nil {
+		return nil, fmt.Errorf("could not get array schema for ReadParallel: %w", err)
+	}
+	defer schema.Free()
+
+	arrayType, err := schema.Type()
+	if err != nil {
+		return nil, err
+	}
+	domain, err := schema.Domain()
+	if err != nil {
+		return nil, fmt.Errorf("could not get domain for ReadParallel: %w", err)
+	}
+	defer domain.Free()
+
+	nDim, err := domain.NDim()
+	if err != nil {
+		return nil, err
+	}
+	dimension, err := domain.DimensionFromIndex(0)
+	if err != nil {
+		return nil, err
+	}
+	defer dimension.Free()
+	extent, err := dimension.Extent()
+	if err != nil {
+		return nil, err
+	}
+	dimDomain, err := dimension.Domain()
+	if err != nil {
+		return nil, err
+	}
+
+	ranges := make([]Range, nDim)
+	switch {
+	case subarray != nil:
+		for d := range ranges {
+			rangeNum, err := subarray.GetRangeNum(uint32(d))
+			if err != nil {
+				return nil, err
+			}
+			if rangeNum != 1 {
+				return nil, fmt.Errorf("dimension %d has %d ranges, ReadParallel needs a single range per dimension", d, rangeNum)
+			}
+			if ranges[d], err = subarray.GetRange(uint32(d), 0); err != nil {
+				return nil, err
+			}
+		}
+	case arrayType == TILEDB_DENSE:
+		domains, empty, err := array.NonEmptyDomain()
+		if err != nil {
+			return nil, err
+		}
+		if empty {
+			return nil, nil
+		}
+		for d, ned := range domains {
+			bounds := reflect.ValueOf(ned.Bounds)
+			ranges[d] = Range{start: bounds.Index(0).Interface(), end: bounds.Index(1).Interface()}
+		}
+	}
+
+	// The other dimensions of sparse arrays are not restricted, the first one is limited to the non-empty domain.
+	var nonEmpty any
+	if arrayType == TILEDB_SPARSE {
+		ned, empty, err := array.NonEmptyDomainFromIndex(0)
+		if err != nil {
+			return nil, err
+		}
+		if empty {
+			return nil, nil
+		}
+		nonEmpty = ned.Bounds
+		if ranges[0].start == nil {
+			bounds := reflect.ValueOf(nonEmpty)
+			ranges[0] = Range{start: bounds.Index(0).Interface(), end: bounds.Index(1).Interface()}
+		}
+	}
+
+	if tilesPerPartition == 0 {
+		tilesPerPartition = 1
+	}
+	var slabs []Range
+	switch ext := extent.(type) {
+	case int8:
+		slabs, err = tileSlabs(ranges[0], dimDomain.([]int8)[0], ext, nonEmpty, tilesPerPartition)
+	case int16:
+		slabs, err = tileSlabs(ranges[0], dimDomain.([]int16)[0], ext, nonEmpty, tilesPerPartition)
+	case int32:
+		slabs, err = tileSlabs(ranges[0], dimDomain.([]int32)[0], ext, nonEmpty, tilesPerPartition)
+	case int64:
+		slabs, err = tileSlabs(ranges[0], dimDomain.([]int64)[0], ext, nonEmpty, tilesPerPartition)
+	case uint8:
+		slabs, err = tileSlabs(ranges[0], dimDomain.([]uint8)[0], ext, nonEmpty, tilesPerPartition)
+	case uint16:
+		slabs, err = tileSlabs(ranges[0], dimDomain.([]uint16)[0], ext, nonEmpty, tilesPerPartition)
+	case uint32:
+		slabs, err = tileSlabs(ranges[0], dimDomain.([]uint32)[0], ext, nonEmpty, tilesPerPartition)
+	case uint64:
+		slabs, err = tileSlabs(ranges[0], dimDomain.([]uint64)[0], ext, nonEmpty, tilesPerPartition)
+	default:
+		err = errors.New("ReadParallel needs a first dimension with an integer datatype")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	partitions := make([]*readPartition, len(slabs))
+	for i, slab := range slabs {
+		p := &readPartition{
+			ranges:  append([]Range{slab}, ranges[1:]...),
+			batches: make(chan *QueryBatch, prefetch),
+		}
+		for d := 1; d < len(p.ranges); d++ {
+			// The default range of string dimensions is empty, it must not be added.
+			if s, ok := p.ranges[d].start.(string); ok && s == "" && p.ranges[d].end.(string) == "" {
+				p.ranges[d] = Range{}
+			}
+		}
+		partitions[i] = p
+	}
+	return partitions, nil
+}
+
+// tileSlabs splits r along the tile grid of a dimension whose domain starts at low, with slabs
+// of tiles tiles of extent. If nonEmpty is not nil, r is first limited to these bounds.
+func tileSlabs[T integerType](r Range, low, extent T, nonEmpty any, tiles uint64) ([]Range, error) {
+	bounds, err := ExtractRange[T](r)
+	if err != nil {
+		return nil, err
+	}
+	if extent == 0 {
+		return nil, errors.New("ReadParallel needs a first dimension with a tile extent")
+	}
+	start, end := bounds[0], bounds[1]
+	if nonEmpty != nil {
+		ned := nonEmpty.([]T)
+		start, end = max(start, ned[0]), min(end, ned[1])
+	}
+	if start > end {
+		return nil, nil
+	}
+
+	// The offsets from low are computed in uint64: the conversions wrap around for signed types,
+	// but the differences are exact.
+	span := uint64(math.MaxUint64)
+	if tiles <= math.MaxUint64/uint64(extent) {
+		span = tiles * uint64(extent)
+	}
+	off, endOff := uint64(start)-uint64(low), uint64(end)-uint64(low)
+
+	var slabs []Range
+	for {
+		slabEnd := off - off%span + span - 1
+		if slabEnd < off || slabEnd > endOff {
+			slabEnd = endOff
+		}
+		slabs = append(slabs, MakeRange(T(uint64(low)+off), T(uint64(low)+slabEnd)))
+		if slabEnd == endOff {
+			return slabs, nil
+		}
+		off = slabEnd + 1
+	}
+}
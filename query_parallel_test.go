@@ -0,0 +1,151 @@
+//go:build go1.23
+
+package tiledb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadParallel(t *testing.T) {
+	array := createTypedTestArray(t)
+
+	var rows []typedTestRow
+	var ids []int32
+	for i := range 35 {
+		rows = append(rows, typedTestRow{ID: int32(i + 1), Count: int32(10 * i)})
+		ids = append(ids, int32(i+1))
+	}
+	require.NoError(t, array.Open(TILEDB_WRITE))
+	require.NoError(t, Write(array.context, array, rows, TILEDB_UNORDERED))
+	require.NoError(t, array.Close())
+
+	require.NoError(t, array.Open(TILEDB_READ))
+	t.Cleanup(func() { require.NoError(t, array.Close()) })
+
+	readIDs := func(t *testing.T, subarray *Subarray, opts ParallelReadOptions) []int32 {
+		opts.Batch.Fields = []string{"id"}
+		var got []int32
+		for batch, err := range ReadParallel(context.Background(), array.context, array, subarray, opts) {
+			require.NoError(t, err)
+			data, err := batch.Data("id")
+			require.NoError(t, err)
+			got = append(got, data.([]int32)...)
+		}
+		return got
+	}
+
+	// The tiles have 10 cells: the 35 rows are in 4 partitions.
+	assert.Equal(t, ids, readIDs(t, nil, ParallelReadOptions{Workers: 2}))
+	assert.Equal(t, ids, readIDs(t, nil, ParallelReadOptions{Workers: 8, TilesPerPartition: 3}))
+
+	subarray, err := array.NewSubarray()
+	require.NoError(t, err)
+	defer subarray.Free()
+	require.NoError(t, subarray.AddRange(0, MakeRange[int32](8, 25)))
+	assert.Equal(t, ids[7:25], readIDs(t, subarray, ParallelReadOptions{}))
+
+	t.Run("Break", func(t *testing.T) {
+		var batches int
+		for batch, err := range ReadParallel(context.Background(), array.context, array, nil, ParallelReadOptions{Workers: 4}) {
+			require.NoError(t, err)
+			assert.EqualValues(t, 10, batch.NumCells)
+			batches++
+			break
+		}
+		assert.Equal(t, 1, batches)
+	})
+
+	t.Run("SeveralBatchesPerPartition", func(t *testing.T) {
+		// The buffers hold 3 cells of id and count, so each partition of 10 cells spans several batches.
+		for _, prefetch := range []int{1, 0, 8} {
+			var gotIDs, gotCounts []int32
+			var batches int
+			opts := ParallelReadOptions{Workers: 2, Prefetch: prefetch, Batch: BatchOptions{Fields: []string{"id", "count"}, MemoryCap: 24}}
+			for batch, err := range ReadParallel(context.Background(), array.context, array, nil, opts) {
+				require.NoError(t, err)
+				assert.LessOrEqual(t, batch.NumCells, uint64(3))
+				data, err := batch.Data("id")
+				require.NoError(t, err)
+				gotIDs = append(gotIDs, data.([]int32)...)
+				data, err = batch.Data("count")
+				require.NoError(t, err)
+				gotCounts = append(gotCounts, data.([]int32)...)
+				batches++
+			}
+			assert.Equal(t, ids, gotIDs)
+			for i, count := range gotCounts {
+				assert.Equal(t, 10*(gotIDs[i]-1), count)
+			}
+			assert.GreaterOrEqual(t, batches, 14)
+		}
+	})
+
+	t.Run("MultipleRanges", func(t *testing.T) {
+		subarray, err := array.NewSubarray()
+		require.NoError(t, err)
+		defer subarray.Free()
+		require.NoError(t, subarray.AddRange(0, MakeRange[int32](1, 2)))
+		require.NoError(t, subarray.AddRange(0, MakeRange[int32](5, 6)))
+		for _, err := range ReadParallel(context.Background(), array.context, array, subarray, ParallelReadOptions{}) {
+			assert.Error(t, err)
+		}
+	})
+}
+
+func TestReadParallelDense(t *testing.T) {
+	tdbCtx, err := NewContext(nil)
+	require.NoError(t, err)
+
+	arrayPath := t.TempDir()
+	require.NoError(t, CreateArray(tdbCtx, arrayPath, buildArraySchema(tdbCtx, t)))
+	array, err := NewArray(tdbCtx, arrayPath)
+	require.NoError(t, err)
+
+	type row struct {
+		Dim int8   `tiledb:"dim1"`
+		A1  int32  `tiledb:"a1"`
+		A2  string `tiledb:"a2"`
+	}
+	var written []row
+	var a1 []int32
+	for i := range 8 {
+		written = append(written, row{Dim: int8(i + 1), A1: int32(i * i), A2: "a"})
+		a1 = append(a1, int32(i*i))
+	}
+	require.NoError(t, array.Open(TILEDB_WRITE))
+	require.NoError(t, Write(tdbCtx, array, written, TILEDB_ROW_MAJOR))
+	require.NoError(t, array.Close())
+
+	require.NoError(t, array.Open(TILEDB_READ))
+	t.Cleanup(func() { require.NoError(t, array.Close()) })
+
+	var got []int32
+	var batches int
+	for batch, err := range ReadParallel(context.Background(), tdbCtx, array, nil, ParallelReadOptions{Batch: BatchOptions{Fields: []string{"a1"}}}) {
+		require.NoError(t, err)
+		data, err := batch.Data("a1")
+		require.NoError(t, err)
+		got = append(got, data.([]int32)...)
+		batches++
+	}
+	assert.Equal(t, a1, got)
+	assert.Equal(t, 2, batches)
+}
+
+func TestTileSlabs(t *testing.T) {
+	slabs, err := tileSlabs[int8](MakeRange[int8](-120, 127), -128, 100, nil, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []Range{MakeRange[int8](-120, -29), MakeRange[int8](-28, 71), MakeRange[int8](72, 127)}, slabs)
+
+	slabs, err = tileSlabs[uint64](MakeRange[uint64](0, 100), 0, 10, []uint64{15, 200}, 5)
+	require.NoError(t, err)
+	assert.Equal(t, []Range{MakeRange[uint64](15, 49), MakeRange[uint64](50, 99), MakeRange[uint64](100, 100)}, slabs)
+
+	slabs, err = tileSlabs[int32](MakeRange[int32](1, 5), 1, 10, []int32{7, 9}, 1)
+	require.NoError(t, err)
+	assert.Empty(t, slabs)
+}
@@ -0,0 +1,249 @@
+package tiledb
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"reflect"
+
+	"github.com/TileDB-Inc/TileDB-Go/bytesizes"
+)
+
+// fieldBuffers are the query buffers of a struct field.
+type fieldBuffers struct {
+	structField
+	data     reflect.Value // slice of f.datatype.ReflectType()
+	offsets  []uint64
+	validity []uint8
+
+	// Sizes in bytes of the results, updated by the core on submit.
+	dataSize     *uint64
+	offsetsSize  *uint64
+	validitySize *uint64
+}
+
+// newFieldBuffers allocates buffers for cells of f, with room for at least one cell.
+func newFieldBuffers(f structField, dataElements, cells uint64) (*fieldBuffers, error) {
+	dataElements = max(dataElements, f.cellElements())
+	cells = max(cells, 1)
+
+	data, _, err := f.datatype.MakeSlice(dataElements)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &fieldBuffers{structField: f, data: reflect.ValueOf(data)}
+	if f.isVar() {
+		b.offsets = make([]uint64, cells)
+	}
+	if f.nullable {
+		b.validity = make([]uint8, cells)
+	}
+
+	return b, nil
+}
+
+// estimateFieldBuffers allocates buffers for cells of f, sized from the estimated result size of q.
+func estimateFieldBuffers(q *Query, f structField) (*fieldBuffers, error) {
+	var dataSize, cellsSize, cellSize uint64
+	switch {
+	case f.isVar() && f.nullable:
+		offsetsSize, size, _, err := q.EstResultSizeVarNullable(f.name)
+		if err != nil {
+			return nil, err
+		}
+		dataSize, cellsSize, cellSize = *size, *offsetsSize, bytesizes.Uint64
+	case f.isVar():
+		offsetsSize, size, err := q.EstResultSizeVar(f.name)
+		if err != nil {
+			return nil, err
+		}
+		dataSize, cellsSize, cellSize = *size, *offsetsSize, bytesizes.Uint64
+	case f.nullable:
+		size, validitySize, err := q.EstResultSizeNullable(f.name)
+		if err != nil {
+			return nil, err
+		}
+		dataSize, cellsSize, cellSize = *size, *validitySize, bytesizes.Uint8
+	default:
+		size, err := q.EstResultSize(f.name)
+		if err != nil {
+			return nil, err
+		}
+		dataSize, cellsSize, cellSize = *size, 0, 1
+	}
+
+	return newFieldBuffers(f, dataSize/f.datatype.Size(), cellsSize/cellSize)
+}
+
+// set sets the buffers on q. It must be called again after the buffers are reallocated.
+func (b *fieldBuffers) set(q *Query) error {
+	var err error
+	if b.data.Len() == 0 {
+		// The var-sized cells of a write can all be empty. The core rejects
+		// nil buffers, so we point to a dummy element with a zero size instead.
+		dummy := reflect.MakeSlice(b.data.Type(), 1, 1)
+		if b.dataSize, err = q.SetDataBufferUnsafe(b.name, dummy.UnsafePointer(), 0); err != nil {
+			return err
+		}
+	} else if b.dataSize, err = q.SetDataBuffer(b.name, b.data.Interface()); err != nil {
+		return err
+	}
+	if b.isVar() {
+		if b.offsetsSize, err = q.SetOffsetsBuffer(b.name, b.offsets); err != nil {
+			return err
+		}
+	}
+	if b.nullable {
+		if b.validitySize, err = q.SetValidityBuffer(b.name, b.validity); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// resize reallocates the buffers with their capacity multiplied by factor.
+func (b *fieldBuffers) resize(factor float64) error {
+	resized, err := newFieldBuffers(b.structField,
+		uint64(factor*float64(b.data.Len())),
+		uint64(factor*float64(max(len(b.offsets), len(b.validity)))))
+	if err != nil {
+		return err
+	}
+	*b = *resized
+
+	return nil
+}
+
+// size returns the size in bytes of the buffers.
+func (b *fieldBuffers) size() uint64 {
+	return uint64(b.data.Len())*b.datatype.Size() +
+		uint64(len(b.offsets))*bytesizes.Uint64 +
+		uint64(len(b.validity))*bytesizes.Uint8
+}
+
+// numCells returns the number of cells read by the last submit.
+func (b *fieldBuffers) numCells() int {
+	if b.isVar() {
+		return int(*b.offsetsSize / bytesizes.Uint64)
+	}
+	return int(*b.dataSize / b.datatype.Size() / uint64(b.cellValNum))
+}
+
+// cell returns the data elements of cell i of the last submit.
+func (b *fieldBuffers) cell(i int) reflect.Value {
+	if !b.isVar() {
+		n := int(b.cellValNum)
+		return b.data.Slice(i*n, (i+1)*n)
+	}
+
+	// Offsets are in bytes
+	elemSize := b.datatype.Size()
+	start := b.offsets[i] / elemSize
+	end := *b.dataSize / elemSize
+	if i+1 < b.numCells() {
+		end = b.offsets[i+1] / elemSize
+	}
+	return b.data.Slice(int(start), int(end))
+}
+
+// decode sets field f of each element of rows from the cells of the last submit.
+func (b *fieldBuffers) decode(rows reflect.Value) {
+	for i := 0; i < rows.Len(); i++ {
+		dst := rows.Index(i).Field(b.index)
+		if b.nullable {
+			if b.validity[i] == 0 {
+				dst.SetZero()
+				continue
+			}
+			ptr := reflect.New(b.typ)
+			decodeCell(b.structField, ptr.Elem(), b.cell(i))
+			dst.Set(ptr)
+			continue
+		}
+		decodeCell(b.structField, dst, b.cell(i))
+	}
+}
+
+// decodeCell sets dst, a value of type f.typ, from the data elements of a cell.
+func decodeCell(f structField, dst, cell reflect.Value) {
+	switch {
+	case f.typ == timeType:
+		dst.Set(reflect.ValueOf(GetTimeFromTimestamp(f.datatype, cell.Index(0).Int())))
+	case f.typ.Kind() == reflect.String:
+		dst.SetString(string(cell.Bytes()))
+	case f.typ.Kind() == reflect.Slice:
+		s := reflect.MakeSlice(f.typ, cell.Len(), cell.Len())
+		copyElements(s, cell)
+		dst.Set(s)
+	case f.typ.Kind() == reflect.Array:
+		copyElements(dst, cell)
+	default:
+		dst.Set(cell.Index(0).Convert(f.typ))
+	}
+}
+
+/*
+ReadInto submits the read query q until it completes and appends the results to out.
+
+The exported fields of T are mapped to attributes and dimensions with a `tiledb:"name"` tag,
+fields without a tag are ignored. A field can be:
+  - a scalar of the attribute type for single-valued cells, e.g. int32 for TILEDB_INT32
+  - an array [N]E for cells with cell_val_num N
+  - a slice []E or, for TILEDB_STRING_ASCII, TILEDB_STRING_UTF8 and other byte types, a string for var-sized cells
+  - a time.Time for datetime and time types
+  - a pointer to any of the above for nullable attributes. Null cells are read as nil.
+
+The buffers of the query are allocated from its estimated result size
+and grown when they cannot hold a single cell, see Query.Batches.
+Offsets are expected in the default format: 64-bit bytes offsets without an extra element.
+
+Example:
+
+	type cell struct {
+		Row   int32   `tiledb:"rows"`
+		Col   int32   `tiledb:"cols"`
+		Label *string `tiledb:"label"`
+	}
+
+	var cells []cell
+	err := tiledb.ReadInto(query, &cells)
+*/
+func ReadInto[T any](q *Query, out *[]T) error {
+	return ReadIntoContext(context.Background(), q, out)
+}
+
+// ReadIntoContext reads the results of q into out like ReadInto, but the query is submitted
+// with SubmitContext: it returns an error wrapping ErrCanceled when ctx is done before the query completes.
+// The results of the submits that completed before are still appended to out.
+func ReadIntoContext[T any](ctx context.Context, q *Query, out *[]T) error {
+	schema, err := q.array.Schema()
+	if err != nil {
+		return fmt.Errorf("could not get array schema for ReadInto: %w", err)
+	}
+	defer schema.Free()
+
+	fields, err := structFields(schema, genericType[T]())
+	if err != nil {
+		return fmt.Errorf("could not map %s to array for ReadInto: %w", genericType[T](), err)
+	}
+
+	buffers := make([]*fieldBuffers, len(fields))
+	for i, f := range fields {
+		buffers[i], err = estimateFieldBuffers(q, f)
+		if err != nil {
+			return fmt.Errorf("could not allocate buffers for ReadInto: %w", err)
+		}
+	}
+
+	return submitBatches(ctx, q, buffers, math.MaxUint64, func(numCells int) bool {
+		start := len(*out)
+		*out = append(*out, make([]T, numCells)...)
+		rows := reflect.ValueOf(*out).Slice(start, start+numCells)
+		for _, b := range buffers {
+			b.decode(rows)
+		}
+		return true
+	})
+}
@@ -0,0 +1,196 @@
+package tiledb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type typedTestRow struct {
+	ID      int32      `tiledb:"id"`
+	Count   int32      `tiledb:"count"`
+	Name    string     `tiledb:"name"`
+	Blob    []byte     `tiledb:"blob"`
+	Point   [2]float64 `tiledb:"point"`
+	Score   *float64   `tiledb:"score"`
+	Created time.Time  `tiledb:"created"`
+	Ignored string
+}
+
+func TestReadInto(t *testing.T) {
+	array := createTypedTestArray(t)
+
+	require.NoError(t, array.Open(TILEDB_WRITE))
+	query, err := NewQuery(array.context, array)
+	require.NoError(t, err)
+	require.NoError(t, query.SetLayout(TILEDB_UNORDERED))
+	_, err = query.SetDataBuffer("id", []int32{1, 2, 3})
+	require.NoError(t, err)
+	_, err = query.SetDataBuffer("count", []int32{10, 20, 30})
+	require.NoError(t, err)
+	_, err = query.SetDataBuffer("name", []byte("oneτwo"))
+	require.NoError(t, err)
+	_, err = query.SetOffsetsBuffer("name", []uint64{0, 3, 3})
+	require.NoError(t, err)
+	_, err = query.SetDataBuffer("blob", []byte{1, 2, 3})
+	require.NoError(t, err)
+	_, err = query.SetOffsetsBuffer("blob", []uint64{0, 1, 3})
+	require.NoError(t, err)
+	_, err = query.SetDataBuffer("point", []float64{0.5, 1.5, 2.5, 3.5, 4.5, 5.5})
+	require.NoError(t, err)
+	_, err = query.SetDataBuffer("score", []float64{0.25, 0, 0.75})
+	require.NoError(t, err)
+	_, err = query.SetValidityBuffer("score", []uint8{1, 0, 1})
+	require.NoError(t, err)
+	_, err = query.SetDataBuffer("created", []int64{0, 32503680000000, 1500})
+	require.NoError(t, err)
+	require.NoError(t, query.Submit())
+	require.NoError(t, array.Close())
+
+	require.NoError(t, array.Open(TILEDB_READ))
+	t.Cleanup(func() { require.NoError(t, array.Close()) })
+
+	score0, score2 := 0.25, 0.75
+	expected := []typedTestRow{
+		{ID: 1, Count: 10, Name: "one", Blob: []byte{1}, Point: [2]float64{0.5, 1.5}, Score: &score0, Created: time.Unix(0, 0).UTC()},
+		{ID: 2, Count: 20, Name: "", Blob: []byte{2, 3}, Point: [2]float64{2.5, 3.5}, Score: nil, Created: time.Date(3000, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{ID: 3, Count: 30, Name: "τwo", Blob: []byte{}, Point: [2]float64{4.5, 5.5}, Score: &score2, Created: time.Unix(1, 500*int64(time.Millisecond)).UTC()},
+	}
+
+	t.Run("AllFields", func(t *testing.T) {
+		query, err := NewQuery(array.context, array)
+		require.NoError(t, err)
+		defer query.Free()
+		require.NoError(t, query.SetLayout(TILEDB_ROW_MAJOR))
+
+		var rows []typedTestRow
+		require.NoError(t, ReadInto(query, &rows))
+		assert.Equal(t, expected, rows)
+	})
+
+	t.Run("Subset", func(t *testing.T) {
+		type idName struct {
+			ID   int    `tiledb:"id"`
+			Name []byte `tiledb:"name"`
+		}
+
+		query, err := NewQuery(array.context, array)
+		require.NoError(t, err)
+		defer query.Free()
+		require.NoError(t, query.SetLayout(TILEDB_ROW_MAJOR))
+
+		subarray, err := array.NewSubarray()
+		require.NoError(t, err)
+		require.NoError(t, subarray.AddRangeByName("id", MakeRange[int32](2, 3)))
+		require.NoError(t, query.SetSubarray(subarray))
+
+		// results are appended
+		rows := []idName{{ID: 100}}
+		require.NoError(t, ReadInto(query, &rows))
+		assert.Equal(t, []idName{{ID: 100}, {ID: 2, Name: []byte{}}, {ID: 3, Name: []byte("τwo")}}, rows)
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		cases := []struct {
+			name string
+			read func(q *Query) error
+		}{
+			{"WrongType", func(q *Query) error {
+				var out []struct {
+					Count int64 `tiledb:"count"`
+				}
+				return ReadInto(q, &out)
+			}},
+			{"WrongCellValNum", func(q *Query) error {
+				var out []struct {
+					Point [3]float64 `tiledb:"point"`
+				}
+				return ReadInto(q, &out)
+			}},
+			{"NullableNotPointer", func(q *Query) error {
+				var out []struct {
+					Score float64 `tiledb:"score"`
+				}
+				return ReadInto(q, &out)
+			}},
+			{"PointerNotNullable", func(q *Query) error {
+				var out []struct {
+					Count *int32 `tiledb:"count"`
+				}
+				return ReadInto(q, &out)
+			}},
+			{"StringNotVar", func(q *Query) error {
+				var out []struct {
+					Count string `tiledb:"count"`
+				}
+				return ReadInto(q, &out)
+			}},
+			{"NotInSchema", func(q *Query) error {
+				var out []struct {
+					Other int32 `tiledb:"other"`
+				}
+				return ReadInto(q, &out)
+			}},
+			{"NoTags", func(q *Query) error {
+				var out []struct {
+					Count int32
+				}
+				return ReadInto(q, &out)
+			}},
+		}
+		for _, c := range cases {
+			t.Run(c.name, func(t *testing.T) {
+				query, err := NewQuery(array.context, array)
+				require.NoError(t, err)
+				defer query.Free()
+
+				require.Error(t, c.read(query))
+			})
+		}
+	})
+}
+
+// createTypedTestArray creates a sparse array with a dimension id and an attribute
+// for each kind of field of typedTestRow.
+func createTypedTestArray(t testing.TB) *Array {
+	tdbCtx, err := NewContext(nil)
+	require.NoError(t, err)
+
+	dimension, err := NewDimension(tdbCtx, "id", TILEDB_INT32, []int32{1, 100}, int32(10))
+	require.NoError(t, err)
+	domain, err := NewDomain(tdbCtx)
+	require.NoError(t, err)
+	require.NoError(t, domain.AddDimensions(dimension))
+
+	schema, err := NewArraySchema(tdbCtx, TILEDB_SPARSE)
+	require.NoError(t, err)
+	require.NoError(t, schema.SetDomain(domain))
+
+	count, err := NewAttribute(tdbCtx, "count", TILEDB_INT32)
+	require.NoError(t, err)
+	name, err := NewAttribute(tdbCtx, "name", TILEDB_STRING_UTF8)
+	require.NoError(t, err)
+	require.NoError(t, name.SetCellValNum(TILEDB_VAR_NUM))
+	blob, err := NewAttribute(tdbCtx, "blob", TILEDB_BLOB)
+	require.NoError(t, err)
+	require.NoError(t, blob.SetCellValNum(TILEDB_VAR_NUM))
+	point, err := NewAttribute(tdbCtx, "point", TILEDB_FLOAT64)
+	require.NoError(t, err)
+	require.NoError(t, point.SetCellValNum(2))
+	score, err := NewAttribute(tdbCtx, "score", TILEDB_FLOAT64)
+	require.NoError(t, err)
+	require.NoError(t, score.SetNullable(true))
+	created, err := NewAttribute(tdbCtx, "created", TILEDB_DATETIME_MS)
+	require.NoError(t, err)
+	require.NoError(t, schema.AddAttributes(count, name, blob, point, score, created))
+
+	arrayPath := t.TempDir()
+	require.NoError(t, CreateArray(tdbCtx, arrayPath, schema))
+
+	array, err := NewArray(tdbCtx, arrayPath)
+	require.NoError(t, err)
+
+	return array
+}
@@ -0,0 +1,399 @@
+package tiledb
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"time"
+)
+
+// encodeFieldBuffers builds the buffers to write field f of each element of rows.
+func encodeFieldBuffers(f structField, rows reflect.Value) (*fieldBuffers, error) {
+	numRows := rows.Len()
+	b := &fieldBuffers{structField: f}
+	if f.nullable {
+		b.validity = make([]uint8, numRows)
+	}
+
+	dataElements := numRows * int(f.cellValNum)
+	if f.isVar() {
+		b.offsets = make([]uint64, numRows)
+		dataElements = 0
+		for i := 0; i < numRows; i++ {
+			if v, ok := fieldValue(f, rows.Index(i)); ok {
+				dataElements += v.Len()
+			}
+		}
+	}
+
+	data, _, err := f.datatype.MakeSlice(uint64(dataElements))
+	if err != nil {
+		return nil, err
+	}
+	b.data = reflect.ValueOf(data)
+
+	elemSize := f.datatype.Size()
+	at := 0
+	for i := 0; i < numRows; i++ {
+		if f.isVar() {
+			// Offsets are in bytes
+			b.offsets[i] = uint64(at) * elemSize
+		}
+
+		v, ok := fieldValue(f, rows.Index(i))
+		if !ok {
+			// Null fixed-sized cells still take cell_val_num elements
+			if !f.isVar() {
+				at += int(f.cellValNum)
+			}
+			continue
+		}
+		if f.nullable {
+			b.validity[i] = 1
+		}
+		n, err := encodeCell(f, b.data, at, v)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.name, err)
+		}
+		at += n
+	}
+
+	return b, nil
+}
+
+// fieldValue returns the value of field f of row, without the pointer for nullable fields.
+// It returns false if the field is a nil pointer.
+func fieldValue(f structField, row reflect.Value) (reflect.Value, bool) {
+	v := row.Field(f.index)
+	if f.nullable {
+		if v.IsNil() {
+			return reflect.Value{}, false
+		}
+		v = v.Elem()
+	}
+	return v, true
+}
+
+// encodeCell writes v, a value of type f.typ, to data from index at
+// and returns the number of data elements written.
+func encodeCell(f structField, data reflect.Value, at int, v reflect.Value) (int, error) {
+	switch {
+	case f.typ == timeType:
+		timestamp, err := GetTimestampFromTime(f.datatype, v.Interface().(time.Time))
+		if err != nil {
+			return 0, err
+		}
+		data.Index(at).SetInt(timestamp)
+		return 1, nil
+	case f.typ.Kind() == reflect.String:
+		s := []byte(v.String())
+		copyElements(data.Slice(at, at+len(s)), reflect.ValueOf(s))
+		return len(s), nil
+	case f.typ.Kind() == reflect.Slice, f.typ.Kind() == reflect.Array:
+		copyElements(data.Slice(at, at+v.Len()), v)
+		return v.Len(), nil
+	default:
+		data.Index(at).Set(v.Convert(data.Type().Elem()))
+		return 1, nil
+	}
+}
+
+// checkWriteFields checks that fields cover all the attributes of schema and, for sparse arrays, all the dimensions.
+// It returns the names of the dimensions in domain order.
+func checkWriteFields(schema *ArraySchema, fields []structField, arrayType ArrayType) ([]string, error) {
+	mapped := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		mapped[f.name] = true
+	}
+
+	domain, err := schema.Domain()
+	if err != nil {
+		return nil, err
+	}
+	defer domain.Free()
+
+	nDim, err := domain.NDim()
+	if err != nil {
+		return nil, err
+	}
+	dimNames := make([]string, 0, nDim)
+	for i := uint(0); i < nDim; i++ {
+		dimension, err := domain.DimensionFromIndex(i)
+		if err != nil {
+			return nil, err
+		}
+		name, err := dimension.Name()
+		dimension.Free()
+		if err != nil {
+			return nil, err
+		}
+		if !mapped[name] {
+			if arrayType == TILEDB_DENSE {
+				return nil, fmt.Errorf("no field for dimension %s: dense writes need the coordinates to compute the subarray", name)
+			}
+			return nil, fmt.Errorf("no field for dimension %s", name)
+		}
+		dimNames = append(dimNames, name)
+	}
+
+	attributes, err := schema.Attributes()
+	if err != nil {
+		return nil, err
+	}
+	for _, attribute := range attributes {
+		name, err := attribute.Name()
+		attribute.Free()
+		if err != nil {
+			return nil, err
+		}
+		if !mapped[name] {
+			return nil, fmt.Errorf("no field for attribute %s", name)
+		}
+	}
+
+	return dimNames, nil
+}
+
+// denseCoordinate returns the coordinate of a dense dimension field as an int64.
+// Dense dimensions are of integer, datetime or time types.
+func denseCoordinate(f structField, v reflect.Value) (int64, error) {
+	if f.typ == timeType {
+		c, err := GetTimestampFromTime(f.datatype, v.Interface().(time.Time))
+		if err != nil {
+			return 0, fmt.Errorf("dense dimension %s: %w", f.name, err)
+		}
+		return c, nil
+	}
+	c, err := DenseCoordinate(v.Interface())
+	if err != nil {
+		return 0, fmt.Errorf("dense dimension %s: %w", f.name, err)
+	}
+	return c, nil
+}
+
+// DenseCoordinate returns the coordinate v of a dense dimension, or a bound or the tile extent
+// returned by Dimension.Domain and Dimension.Extent, as an int64. v must be an integer,
+// as are the values of the integer and datetime datatypes.
+func DenseCoordinate(v any) (int64, error) {
+	rv := reflect.ValueOf(v)
+	switch {
+	case rv.CanInt():
+		return rv.Int(), nil
+	case rv.CanUint():
+		if rv.Uint() > math.MaxInt64 {
+			return 0, fmt.Errorf("dense coordinate %d overflows int64", rv.Uint())
+		}
+		return int64(rv.Uint()), nil
+	default:
+		return 0, fmt.Errorf("dense coordinate has non-integer type %T", v)
+	}
+}
+
+// denseWriteSubarray returns the subarray of a dense write of rows in layout,
+// computed by NewDenseWriteSubarray from the coordinates of the dimension fields.
+func denseWriteSubarray(array *Array, fields []structField, dimNames []string, rows reflect.Value, layout Layout) (*Subarray, error) {
+	numRows := rows.Len()
+	coords := make([][]int64, len(dimNames))
+	for d, name := range dimNames {
+		for _, f := range fields {
+			if f.name != name {
+				continue
+			}
+			coords[d] = make([]int64, numRows)
+			for i := 0; i < numRows; i++ {
+				c, err := denseCoordinate(f, rows.Index(i).Field(f.index))
+				if err != nil {
+					return nil, err
+				}
+				coords[d][i] = c
+			}
+		}
+	}
+
+	return NewDenseWriteSubarray(array, coords, layout)
+}
+
+/*
+NewDenseWriteSubarray returns the subarray of a dense write of cells to the array in layout,
+TILEDB_ROW_MAJOR or TILEDB_COL_MAJOR. coords holds the coordinates of the cells for each dimension,
+in domain order: coords[d][i] is the coordinate of cell i on dimension d, see DenseCoordinate.
+
+The subarray is the bounding box of the coordinates, and the cells must fill it exactly, in layout order,
+so that they can be written with one query. The subarray must be freed by the caller.
+
+Example:
+
+	// Cells (1, 1), (1, 2), (2, 1) and (2, 2) of a 2-D array.
+	subarray, err := tiledb.NewDenseWriteSubarray(array, [][]int64{{1, 1, 2, 2}, {1, 2, 1, 2}}, tiledb.TILEDB_ROW_MAJOR)
+*/
+func NewDenseWriteSubarray(array *Array, coords [][]int64, layout Layout) (*Subarray, error) {
+	if layout != TILEDB_ROW_MAJOR && layout != TILEDB_COL_MAJOR {
+		return nil, errors.New("dense writes need TILEDB_ROW_MAJOR or TILEDB_COL_MAJOR layout")
+	}
+	if len(coords) == 0 {
+		return nil, errors.New("dense writes need the coordinates of at least one dimension")
+	}
+
+	numCells := len(coords[0])
+	lo := make([]int64, len(coords))
+	hi := make([]int64, len(coords))
+	for d := range coords {
+		if len(coords[d]) != numCells {
+			return nil, fmt.Errorf("dense write has %d coordinates on dimension %d and %d on dimension 0", len(coords[d]), d, numCells)
+		}
+		for i, c := range coords[d] {
+			if i == 0 || c < lo[d] {
+				lo[d] = c
+			}
+			if i == 0 || c > hi[d] {
+				hi[d] = c
+			}
+		}
+	}
+
+	// Strides of the dimensions in the subarray: the last dimension varies
+	// fastest in row-major order, the first one in column-major order.
+	strides := make([]int64, len(coords))
+	boxCells := int64(1)
+	for k := range coords {
+		d := len(coords) - 1 - k
+		if layout == TILEDB_COL_MAJOR {
+			d = k
+		}
+		strides[d] = boxCells
+		boxCells *= hi[d] - lo[d] + 1
+	}
+	if boxCells != int64(numCells) {
+		return nil, fmt.Errorf("dense write of %d cells does not fill the subarray of %d cells of their coordinates", numCells, boxCells)
+	}
+	for i := 0; i < numCells; i++ {
+		var pos int64
+		for d := range coords {
+			pos += (coords[d][i] - lo[d]) * strides[d]
+		}
+		if pos != int64(i) {
+			return nil, fmt.Errorf("dense write cells are not in layout order: cell %d is at position %d of the subarray", i, pos)
+		}
+	}
+
+	subarray, err := array.NewSubarray()
+	if err != nil {
+		return nil, err
+	}
+	for d := range coords {
+		datatype, _, err := datatypeOfDimensionFromIndex(array, uint32(d))
+		if err != nil {
+			subarray.Free()
+			return nil, err
+		}
+		typ := datatype.ReflectType()
+		r := Range{
+			start: reflect.ValueOf(lo[d]).Convert(typ).Interface(),
+			end:   reflect.ValueOf(hi[d]).Convert(typ).Interface(),
+		}
+		if err := subarray.AddRange(uint32(d), r); err != nil {
+			subarray.Free()
+			return nil, err
+		}
+	}
+
+	return subarray, nil
+}
+
+/*
+Write writes rows to the array, which must be open for writing, then finalizes the query.
+
+The fields of T are mapped to attributes and dimensions with `tiledb:"name"` tags like for ReadInto,
+and are checked against the array schema before anything is written. All the attributes must be mapped.
+For sparse arrays the dimension fields are the coordinates of the cells and layout is
+TILEDB_UNORDERED or TILEDB_GLOBAL_ORDER.
+For dense arrays the dimension fields are used to compute the subarray to write:
+the rows must fill it exactly, in TILEDB_ROW_MAJOR or TILEDB_COL_MAJOR layout.
+Nil pointer fields are written as null cells. Cells outside the current domain of the array
+fail with a *CurrentDomainError before anything is written.
+
+Example:
+
+	cells := []cell{{Row: 1, Col: 1, Label: &label}, {Row: 1, Col: 2}}
+	err := tiledb.Write(tdbCtx, array, cells, tiledb.TILEDB_UNORDERED)
+*/
+func Write[T any](tdbCtx *Context, array *Array, rows []T, layout Layout) error {
+	if len(rows) == 0 {
+		return errors.New("no rows to write")
+	}
+
+	schema, err := array.Schema()
+	if err != nil {
+		return fmt.Errorf("could not get array schema for Write: %w", err)
+	}
+	defer schema.Free()
+
+	fields, err := structFields(schema, genericType[T]())
+	if err != nil {
+		return fmt.Errorf("could not map %s to array for Write: %w", genericType[T](), err)
+	}
+
+	arrayType, err := schema.Type()
+	if err != nil {
+		return fmt.Errorf("could not get array type for Write: %w", err)
+	}
+
+	dimNames, err := checkWriteFields(schema, fields, arrayType)
+	if err != nil {
+		return fmt.Errorf("could not map %s to array for Write: %w", genericType[T](), err)
+	}
+
+	currentDomain, err := currentDomainRanges(schema)
+	if err != nil {
+		return fmt.Errorf("could not get current domain for Write: %w", err)
+	}
+
+	query, err := NewQuery(tdbCtx, array)
+	if err != nil {
+		return err
+	}
+	defer query.Free()
+
+	if err := query.SetLayout(layout); err != nil {
+		return err
+	}
+
+	rowsValue := reflect.ValueOf(rows)
+	if arrayType == TILEDB_DENSE {
+		subarray, err := denseWriteSubarray(array, fields, dimNames, rowsValue, layout)
+		if err != nil {
+			return err
+		}
+		defer subarray.Free()
+		if err := checkCurrentDomainSubarray(currentDomain, subarray); err != nil {
+			return err
+		}
+		if err := query.SetSubarray(subarray); err != nil {
+			return err
+		}
+	}
+
+	for _, f := range fields {
+		if arrayType == TILEDB_DENSE && slices.Contains(dimNames, f.name) {
+			continue
+		}
+		b, err := encodeFieldBuffers(f, rowsValue)
+		if err != nil {
+			return fmt.Errorf("could not build buffers for Write: %w", err)
+		}
+		if err := checkCurrentDomainCells(currentDomain, b); err != nil {
+			return err
+		}
+		if err := b.set(query); err != nil {
+			return fmt.Errorf("could not set buffers for Write: %w", err)
+		}
+	}
+
+	if err := query.Submit(); err != nil {
+		return err
+	}
+
+	return query.Finalize()
+}
@@ -0,0 +1,189 @@
+package tiledb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrite(t *testing.T) {
+	t.Run("Sparse", func(t *testing.T) {
+		array := createTypedTestArray(t)
+
+		score := 0.5
+		rows := []typedTestRow{
+			{ID: 7, Count: 1, Name: "seven", Blob: []byte{7}, Point: [2]float64{7, 0.7}, Score: &score, Created: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)},
+			{ID: 3, Count: 2, Name: "", Blob: []byte{}, Point: [2]float64{3, 0.3}, Score: nil, Created: time.Unix(0, 0).UTC()},
+			{ID: 5, Count: 3, Name: "fünf", Blob: []byte{5, 5}, Point: [2]float64{5, 0.5}, Score: nil, Created: time.Date(1969, 7, 20, 20, 17, 0, 0, time.UTC)},
+		}
+
+		require.NoError(t, array.Open(TILEDB_WRITE))
+		require.NoError(t, Write(array.context, array, rows, TILEDB_UNORDERED))
+		require.NoError(t, array.Close())
+
+		require.NoError(t, array.Open(TILEDB_READ))
+		t.Cleanup(func() { require.NoError(t, array.Close()) })
+
+		query, err := NewQuery(array.context, array)
+		require.NoError(t, err)
+		defer query.Free()
+		require.NoError(t, query.SetLayout(TILEDB_ROW_MAJOR))
+
+		var read []typedTestRow
+		require.NoError(t, ReadInto(query, &read))
+		assert.Equal(t, []typedTestRow{rows[1], rows[2], rows[0]}, read)
+	})
+
+	t.Run("Dense", func(t *testing.T) {
+		type denseRow struct {
+			Row int32  `tiledb:"rows"`
+			Col int32  `tiledb:"cols"`
+			V   uint64 `tiledb:"v"`
+		}
+
+		array := createDenseTypedTestArray(t)
+
+		rows := []denseRow{{2, 2, 1}, {2, 3, 2}, {3, 2, 3}, {3, 3, 4}}
+		require.NoError(t, array.Open(TILEDB_WRITE))
+		require.NoError(t, Write(array.context, array, rows, TILEDB_ROW_MAJOR))
+		colMajor := []denseRow{{1, 1, 5}, {2, 1, 6}}
+		require.NoError(t, Write(array.context, array, colMajor, TILEDB_COL_MAJOR))
+		require.NoError(t, array.Close())
+
+		require.NoError(t, array.Open(TILEDB_READ))
+		t.Cleanup(func() { require.NoError(t, array.Close()) })
+
+		query, err := NewQuery(array.context, array)
+		require.NoError(t, err)
+		defer query.Free()
+		require.NoError(t, query.SetLayout(TILEDB_ROW_MAJOR))
+
+		subarray, err := array.NewSubarray()
+		require.NoError(t, err)
+		require.NoError(t, subarray.AddRangeByName("rows", MakeRange[int32](1, 3)))
+		require.NoError(t, subarray.AddRangeByName("cols", MakeRange[int32](1, 3)))
+		require.NoError(t, query.SetSubarray(subarray))
+
+		var read []denseRow
+		require.NoError(t, ReadInto(query, &read))
+		// cells that were not written have the fill value
+		fill := ^uint64(0)
+		assert.Equal(t, []denseRow{
+			{1, 1, 5}, {1, 2, fill}, {1, 3, fill},
+			{2, 1, 6}, {2, 2, 1}, {2, 3, 2},
+			{3, 1, fill}, {3, 2, 3}, {3, 3, 4},
+		}, read)
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		sparse := createTypedTestArray(t)
+		require.NoError(t, sparse.Open(TILEDB_WRITE))
+		t.Cleanup(func() { require.NoError(t, sparse.Close()) })
+
+		dense := createDenseTypedTestArray(t)
+		require.NoError(t, dense.Open(TILEDB_WRITE))
+		t.Cleanup(func() { require.NoError(t, dense.Close()) })
+
+		type missingAttributes struct {
+			ID    int32 `tiledb:"id"`
+			Count int32 `tiledb:"count"`
+		}
+		require.Error(t, Write(sparse.context, sparse, []missingAttributes{{1, 1}}, TILEDB_UNORDERED))
+		require.Error(t, Write(sparse.context, sparse, []typedTestRow{}, TILEDB_UNORDERED))
+
+		type denseRow struct {
+			Row int32  `tiledb:"rows"`
+			Col int32  `tiledb:"cols"`
+			V   uint64 `tiledb:"v"`
+		}
+		// the rows do not fill their bounding box
+		require.Error(t, Write(dense.context, dense, []denseRow{{1, 1, 1}, {2, 2, 2}}, TILEDB_ROW_MAJOR))
+		// the rows are not in row-major order
+		require.Error(t, Write(dense.context, dense, []denseRow{{1, 1, 1}, {2, 1, 2}, {1, 2, 3}, {2, 2, 4}}, TILEDB_ROW_MAJOR))
+		require.Error(t, Write(dense.context, dense, []denseRow{{1, 1, 1}}, TILEDB_UNORDERED))
+
+		type denseNoCoordinates struct {
+			V uint64 `tiledb:"v"`
+		}
+		require.Error(t, Write(dense.context, dense, []denseNoCoordinates{{1}}, TILEDB_ROW_MAJOR))
+	})
+}
+
+func TestNewDenseWriteSubarray(t *testing.T) {
+	array := createDenseTypedTestArray(t)
+	require.NoError(t, array.Open(TILEDB_WRITE))
+	t.Cleanup(func() { require.NoError(t, array.Close()) })
+
+	subarray, err := NewDenseWriteSubarray(array, [][]int64{{2, 2, 3, 3}, {2, 3, 2, 3}}, TILEDB_ROW_MAJOR)
+	require.NoError(t, err)
+	defer subarray.Free()
+	ranges, err := subarray.GetRanges()
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]Range{
+		"rows": {MakeRange[int32](2, 3)},
+		"cols": {MakeRange[int32](2, 3)},
+	}, ranges)
+
+	colMajor, err := NewDenseWriteSubarray(array, [][]int64{{1, 2}, {1, 1}}, TILEDB_COL_MAJOR)
+	require.NoError(t, err)
+	colMajor.Free()
+
+	// the cells do not fill their bounding box
+	_, err = NewDenseWriteSubarray(array, [][]int64{{1, 2}, {1, 2}}, TILEDB_ROW_MAJOR)
+	require.Error(t, err)
+	// the cells are not in row-major order
+	_, err = NewDenseWriteSubarray(array, [][]int64{{1, 2, 1, 2}, {1, 1, 2, 2}}, TILEDB_ROW_MAJOR)
+	require.Error(t, err)
+	// the dimensions have different numbers of coordinates
+	_, err = NewDenseWriteSubarray(array, [][]int64{{1, 2}, {1}}, TILEDB_ROW_MAJOR)
+	require.Error(t, err)
+	_, err = NewDenseWriteSubarray(array, [][]int64{{1}, {1}}, TILEDB_UNORDERED)
+	require.Error(t, err)
+}
+
+func TestDenseCoordinate(t *testing.T) {
+	for _, v := range []any{int8(-3), int32(-3), int64(-3)} {
+		c, err := DenseCoordinate(v)
+		require.NoError(t, err)
+		assert.Equal(t, int64(-3), c)
+	}
+	c, err := DenseCoordinate(uint16(7))
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), c)
+
+	_, err = DenseCoordinate(^uint64(0))
+	require.Error(t, err)
+	_, err = DenseCoordinate(1.5)
+	require.Error(t, err)
+}
+
+// createDenseTypedTestArray creates a 4x4 dense array with dimensions rows and cols and a uint64 attribute v.
+func createDenseTypedTestArray(t testing.TB) *Array {
+	tdbCtx, err := NewContext(nil)
+	require.NoError(t, err)
+
+	rows, err := NewDimension(tdbCtx, "rows", TILEDB_INT32, []int32{1, 4}, int32(2))
+	require.NoError(t, err)
+	cols, err := NewDimension(tdbCtx, "cols", TILEDB_INT32, []int32{1, 4}, int32(2))
+	require.NoError(t, err)
+	domain, err := NewDomain(tdbCtx)
+	require.NoError(t, err)
+	require.NoError(t, domain.AddDimensions(rows, cols))
+
+	schema, err := NewArraySchema(tdbCtx, TILEDB_DENSE)
+	require.NoError(t, err)
+	require.NoError(t, schema.SetDomain(domain))
+	v, err := NewAttribute(tdbCtx, "v", TILEDB_UINT64)
+	require.NoError(t, err)
+	require.NoError(t, schema.AddAttributes(v))
+
+	arrayPath := t.TempDir()
+	require.NoError(t, CreateArray(tdbCtx, arrayPath, schema))
+
+	array, err := NewArray(tdbCtx, arrayPath)
+	require.NoError(t, err)
+
+	return array
+}
@@ -1,7 +1,9 @@
 package tiledb
 
 import (
+	"fmt"
 	"reflect"
+	"time"
 )
 
 // genericType returns the reflect.Type for T
@@ -80,3 +82,224 @@ func addressableValue(val any) reflect.Value {
 	pointable.Elem().Set(valVal)
 	return pointable
 }
+
+// structTag is the struct tag key mapping a field to an attribute or dimension.
+const structTag = "tiledb"
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// structField is a struct field mapped to an attribute or dimension with a `tiledb:"name"` tag.
+type structField struct {
+	name       string       // attribute or dimension name
+	index      int          // index of the field in the struct
+	typ        reflect.Type // field type, without the pointer for nullable fields
+	nullable   bool         // the field is a pointer; the attribute must be nullable
+	datatype   Datatype
+	cellValNum uint32
+}
+
+// isVar returns whether the field maps to a var-sized attribute or dimension.
+func (f *structField) isVar() bool {
+	return f.cellValNum == TILEDB_VAR_NUM
+}
+
+// cellElements returns the number of data elements of a fixed-sized cell.
+func (f *structField) cellElements() uint64 {
+	if f.isVar() {
+		return 1
+	}
+	return uint64(f.cellValNum)
+}
+
+// structFields maps the exported fields of struct type t that have a `tiledb:"name"` tag
+// to the attributes and dimensions of schema and checks that their types are compatible.
+// Untagged fields and fields tagged with "-" are ignored.
+func structFields(schema *ArraySchema, t reflect.Type) ([]structField, error) {
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("type %s is not a struct", t)
+	}
+
+	domain, err := schema.Domain()
+	if err != nil {
+		return nil, err
+	}
+	defer domain.Free()
+
+	var fields []structField
+	seen := make(map[string]bool)
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name, ok := sf.Tag.Lookup(structTag)
+		if !ok || name == "-" {
+			continue
+		}
+		if !sf.IsExported() {
+			return nil, fmt.Errorf("field %s of %s is tagged but not exported", sf.Name, t)
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("%s is mapped to more than one field of %s", name, t)
+		}
+		seen[name] = true
+
+		f := structField{name: name, index: i, typ: sf.Type}
+		if f.typ.Kind() == reflect.Pointer {
+			f.typ = f.typ.Elem()
+			f.nullable = true
+		}
+
+		var nullable bool
+		f.datatype, f.cellValNum, nullable, err = fieldSchema(schema, domain, name)
+		if err != nil {
+			return nil, err
+		}
+		if f.nullable != nullable {
+			if nullable {
+				return nil, fmt.Errorf("field %s of %s must be a pointer: %s is nullable", sf.Name, t, name)
+			}
+			return nil, fmt.Errorf("field %s of %s must not be a pointer: %s is not nullable", sf.Name, t, name)
+		}
+		if err := f.checkType(); err != nil {
+			return nil, fmt.Errorf("field %s of %s: %w", sf.Name, t, err)
+		}
+
+		fields = append(fields, f)
+	}
+
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("type %s has no fields with a %q tag", t, structTag)
+	}
+
+	return fields, nil
+}
+
+// fieldSchema returns the Datatype, cell_val_num and nullability of the attribute or dimension name.
+// Dimensions are never nullable.
+func fieldSchema(schema *ArraySchema, domain *Domain, name string) (Datatype, uint32, bool, error) {
+	hasDim, err := domain.HasDimension(name)
+	if err != nil {
+		return 0, 0, false, err
+	}
+	if hasDim {
+		dimension, err := domain.DimensionFromName(name)
+		if err != nil {
+			return 0, 0, false, err
+		}
+		defer dimension.Free()
+
+		datatype, err := dimension.Type()
+		if err != nil {
+			return 0, 0, false, err
+		}
+		cellValNum, err := dimension.CellValNum()
+		if err != nil {
+			return 0, 0, false, err
+		}
+		return datatype, cellValNum, false, nil
+	}
+
+	hasAttr, err := schema.HasAttribute(name)
+	if err != nil {
+		return 0, 0, false, err
+	}
+	if !hasAttr {
+		return 0, 0, false, fmt.Errorf("no attribute or dimension named %s", name)
+	}
+
+	attribute, err := schema.AttributeFromName(name)
+	if err != nil {
+		return 0, 0, false, err
+	}
+	defer attribute.Free()
+
+	datatype, err := attribute.Type()
+	if err != nil {
+		return 0, 0, false, err
+	}
+	cellValNum, err := attribute.CellValNum()
+	if err != nil {
+		return 0, 0, false, err
+	}
+	nullable, err := attribute.Nullable()
+	if err != nil {
+		return 0, 0, false, err
+	}
+	return datatype, cellValNum, nullable, nil
+}
+
+// checkType checks that the field type can hold cells of the attribute or dimension:
+//   - time.Time for single-valued datetime and time types
+//   - string for var-sized types of 1 byte, e.g. TILEDB_STRING_UTF8
+//   - slices for var-sized cells
+//   - arrays of length cell_val_num for fixed-sized cells
+//   - scalars for single-valued cells
+func (f *structField) checkType() error {
+	elem := f.typ
+	switch {
+	case f.typ == timeType:
+		if !isTimeDatatype(f.datatype) || f.cellValNum != 1 {
+			return fmt.Errorf("%s cannot hold %s cells of %s with cell_val_num %d", f.typ, f.datatype, f.name, f.cellValNum)
+		}
+		return nil
+	case f.typ.Kind() == reflect.String:
+		if !f.isVar() || f.datatype.ReflectKind() != reflect.Uint8 {
+			return fmt.Errorf("%s cannot hold %s cells of %s with cell_val_num %d", f.typ, f.datatype, f.name, f.cellValNum)
+		}
+		return nil
+	case f.typ.Kind() == reflect.Slice:
+		if !f.isVar() {
+			return fmt.Errorf("%s cannot hold fixed-sized cells of %s with cell_val_num %d", f.typ, f.name, f.cellValNum)
+		}
+		elem = f.typ.Elem()
+	case f.typ.Kind() == reflect.Array:
+		if f.isVar() || uint32(f.typ.Len()) != f.cellValNum {
+			return fmt.Errorf("%s cannot hold cells of %s with cell_val_num %d", f.typ, f.name, f.cellValNum)
+		}
+		elem = f.typ.Elem()
+	default:
+		if f.cellValNum != 1 {
+			return fmt.Errorf("%s cannot hold cells of %s with cell_val_num %d", f.typ, f.name, f.cellValNum)
+		}
+	}
+
+	if !kindMatchesDatatype(elem.Kind(), f.datatype) {
+		return fmt.Errorf("%s cannot hold %s values of %s", f.typ, f.datatype, f.name)
+	}
+	return nil
+}
+
+// kindMatchesDatatype returns whether values of kind k have the same representation as values of datatype.
+func kindMatchesDatatype(k reflect.Kind, datatype Datatype) bool {
+	switch k {
+	case reflect.Int:
+		k = tileDBInt.ReflectKind()
+	case reflect.Uint:
+		k = tileDBUint.ReflectKind()
+	}
+	return k == datatype.ReflectKind()
+}
+
+// isTimeDatatype returns whether datatype is a datetime or time type.
+func isTimeDatatype(datatype Datatype) bool {
+	switch datatype {
+	case TILEDB_DATETIME_YEAR, TILEDB_DATETIME_MONTH, TILEDB_DATETIME_WEEK, TILEDB_DATETIME_DAY, TILEDB_DATETIME_HR, TILEDB_DATETIME_MIN, TILEDB_DATETIME_SEC, TILEDB_DATETIME_MS, TILEDB_DATETIME_US, TILEDB_DATETIME_NS, TILEDB_DATETIME_PS, TILEDB_DATETIME_FS, TILEDB_DATETIME_AS, TILEDB_TIME_HR, TILEDB_TIME_MIN, TILEDB_TIME_SEC, TILEDB_TIME_MS, TILEDB_TIME_US, TILEDB_TIME_NS, TILEDB_TIME_PS, TILEDB_TIME_FS, TILEDB_TIME_AS:
+		return true
+	default:
+		return false
+	}
+}
+
+// copyElements copies the elements of src to dst, converting them if the element types differ.
+// dst must be a slice or an addressable array at least as long as src.
+func copyElements(dst, src reflect.Value) {
+	if dst.Type().Elem() == src.Type().Elem() {
+		reflect.Copy(dst, src)
+		return
+	}
+	elem := dst.Type().Elem()
+	for i := 0; i < src.Len(); i++ {
+		dst.Index(i).Set(src.Index(i).Convert(elem))
+	}
+}
@@ -0,0 +1,89 @@
+package tiledbtest
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	tiledb "github.com/TileDB-Inc/TileDB-Go"
+)
+
+// Backend is the storage of the arrays created by the fixtures.
+type Backend string
+
+const (
+	// File stores the arrays in a temporary directory of the test, with file:// URIs.
+	File Backend = "file"
+	// Mem stores the arrays in the in-memory file system of the context, with mem:// URIs.
+	// They are only visible through the context that created them.
+	Mem Backend = "mem"
+)
+
+// Backends are all the backends, in the order ForEachBackend runs them.
+var Backends = []Backend{File, Mem}
+
+// memPrefixes numbers the mem:// URIs so that they are unique across tests.
+var memPrefixes atomic.Uint64
+
+// ForEachBackend runs f as a subtest of t, named after the backend, for each backend.
+func ForEachBackend(t *testing.T, f func(t *testing.T, backend Backend)) {
+	t.Helper()
+	for _, backend := range Backends {
+		t.Run(string(backend), func(t *testing.T) {
+			f(t, backend)
+		})
+	}
+}
+
+// NewContext returns a context with the default config, freed when the test completes.
+func NewContext(t testing.TB) *tiledb.Context {
+	t.Helper()
+	tdbCtx, err := tiledb.NewContext(nil)
+	require.NoError(t, err)
+	t.Cleanup(tdbCtx.Free)
+	return tdbCtx
+}
+
+/*
+URI returns a new URI named name for an array or a group of the test on the backend.
+Nothing is created at the URI. The objects created there are removed when the test completes:
+with the temporary directory for File, and with tiledb.ObjectRemove for Mem.
+*/
+func (b Backend) URI(t testing.TB, tdbCtx *tiledb.Context, name string) string {
+	t.Helper()
+	switch b {
+	case File:
+		return "file://" + filepath.Join(t.TempDir(), name)
+	case Mem:
+		uri := fmt.Sprintf("mem://%s-%d/%s", memName(t.Name()), memPrefixes.Add(1), name)
+		t.Cleanup(func() {
+			objectType, err := tiledb.ObjectType(tdbCtx, uri)
+			if err != nil || objectType == tiledb.TILEDB_INVALID {
+				return
+			}
+			if err := tiledb.ObjectRemove(tdbCtx, uri); err != nil {
+				t.Errorf("could not remove %s: %v", uri, err)
+			}
+		})
+		return uri
+	default:
+		t.Fatalf("unknown backend %q", b)
+		return ""
+	}
+}
+
+// memName returns the test name with the characters other than letters, digits, '-' and '_' replaced with '_'.
+func memName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+}
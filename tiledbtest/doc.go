@@ -0,0 +1,28 @@
+/*
+Package tiledbtest provides helpers for tests of code using TileDB arrays: schema builders,
+fixtures that create and fill arrays and free them through t.Cleanup, and assertions that
+compare the contents of arrays to Go slices.
+
+Failures are reported with t.Fatal through the require package, so that a test can use
+the helpers without checking errors:
+
+	func TestCities(t *testing.T) {
+		tiledbtest.ForEachBackend(t, func(t *testing.T, backend tiledbtest.Backend) {
+			tdbCtx := tiledbtest.NewContext(t)
+			schema := tiledbtest.NewSchema(t, tdbCtx, tiledb.TILEDB_SPARSE).
+				Dimension("id", tiledb.TILEDB_INT32, []int32{1, 100}, int32(10)).
+				Attribute("name", tiledb.TILEDB_STRING_UTF8, tiledbtest.Var()).
+				Build()
+			uri := tiledbtest.NewArrayWithRows(t, tdbCtx, backend, "cities", schema, cities)
+
+			// ... code under test
+
+			tiledbtest.AssertRows(t, tdbCtx, uri, want)
+		})
+	}
+
+The rows are structs mapped to the array with `tiledb:"name"` tags, like for tiledb.Write and tiledb.ReadInto.
+Arrays are created on a Backend: a temporary directory of the test for File, or the in-memory
+file system of the context for Mem, so the same test can run against both.
+*/
+package tiledbtest
@@ -0,0 +1,122 @@
+package tiledbtest
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tiledb "github.com/TileDB-Inc/TileDB-Go"
+)
+
+// NewArray creates an array named name with the schema on the backend and returns its URI.
+// It is removed when the test completes.
+func NewArray(t testing.TB, tdbCtx *tiledb.Context, backend Backend, name string, schema *tiledb.ArraySchema) string {
+	t.Helper()
+	uri := backend.URI(t, tdbCtx, name)
+	require.NoError(t, tiledb.CreateArray(tdbCtx, uri, schema))
+	return uri
+}
+
+// NewArrayWithRows creates an array like NewArray and writes rows to it with WriteRows.
+func NewArrayWithRows[T any](t testing.TB, tdbCtx *tiledb.Context, backend Backend, name string, schema *tiledb.ArraySchema, rows []T) string {
+	t.Helper()
+	uri := NewArray(t, tdbCtx, backend, name, schema)
+	WriteRows(t, tdbCtx, uri, rows)
+	return uri
+}
+
+// OpenArray opens the array at uri for queryType. It is closed and freed when the test completes.
+func OpenArray(t testing.TB, tdbCtx *tiledb.Context, uri string, queryType tiledb.QueryType) *tiledb.Array {
+	t.Helper()
+	array, err := tiledb.NewArray(tdbCtx, uri)
+	require.NoError(t, err)
+	t.Cleanup(array.Free)
+	require.NoError(t, array.Open(queryType))
+	t.Cleanup(func() {
+		if err := array.Close(); err != nil {
+			t.Errorf("could not close array %s: %v", uri, err)
+		}
+	})
+	return array
+}
+
+// WriteRows writes rows to the array at uri in a fragment with tiledb.Write: with TILEDB_UNORDERED layout
+// for sparse arrays, and TILEDB_ROW_MAJOR for dense arrays, whose rows must fill a subarray.
+func WriteRows[T any](t testing.TB, tdbCtx *tiledb.Context, uri string, rows []T) {
+	t.Helper()
+	array, err := tiledb.NewArray(tdbCtx, uri)
+	require.NoError(t, err)
+	defer array.Free()
+	require.NoError(t, array.Open(tiledb.TILEDB_WRITE))
+	defer array.Close()
+
+	layout := tiledb.TILEDB_UNORDERED
+	if arrayType(t, array) == tiledb.TILEDB_DENSE {
+		layout = tiledb.TILEDB_ROW_MAJOR
+	}
+	require.NoError(t, tiledb.Write(tdbCtx, array, rows, layout))
+}
+
+// ReadRows reads all the cells of the array at uri in row-major order with tiledb.ReadInto:
+// the cells of a sparse array, or the non-empty domain of a dense array.
+func ReadRows[T any](t testing.TB, tdbCtx *tiledb.Context, uri string) []T {
+	t.Helper()
+	array, err := tiledb.NewArray(tdbCtx, uri)
+	require.NoError(t, err)
+	defer array.Free()
+	require.NoError(t, array.Open(tiledb.TILEDB_READ))
+	defer array.Close()
+
+	query, err := tiledb.NewQuery(tdbCtx, array)
+	require.NoError(t, err)
+	defer query.Free()
+	require.NoError(t, query.SetLayout(tiledb.TILEDB_ROW_MAJOR))
+
+	if arrayType(t, array) == tiledb.TILEDB_DENSE {
+		domains, empty, err := array.NonEmptyDomain()
+		require.NoError(t, err)
+		if empty {
+			return nil
+		}
+		// The dimensions of dense arrays all have the same datatype.
+		bounds := reflect.ValueOf(domains[0].Bounds)
+		for _, domain := range domains[1:] {
+			bounds = reflect.AppendSlice(bounds, reflect.ValueOf(domain.Bounds))
+		}
+		subarray, err := array.NewSubarray()
+		require.NoError(t, err)
+		defer subarray.Free()
+		require.NoError(t, subarray.SetSubArray(bounds.Interface()))
+		require.NoError(t, query.SetSubarray(subarray))
+	}
+
+	var rows []T
+	require.NoError(t, tiledb.ReadInto(query, &rows))
+	return rows
+}
+
+// AssertRows asserts that the cells of the array at uri, read by ReadRows, are want in the same order.
+func AssertRows[T any](t testing.TB, tdbCtx *tiledb.Context, uri string, want []T) bool {
+	t.Helper()
+	return assert.Equal(t, want, ReadRows[T](t, tdbCtx, uri))
+}
+
+// AssertRowsUnordered asserts that the cells of the array at uri, read by ReadRows, are want in any order,
+// e.g. for sparse arrays that allow duplicates.
+func AssertRowsUnordered[T any](t testing.TB, tdbCtx *tiledb.Context, uri string, want []T) bool {
+	t.Helper()
+	return assert.ElementsMatch(t, want, ReadRows[T](t, tdbCtx, uri))
+}
+
+// arrayType returns the type of the open array.
+func arrayType(t testing.TB, array *tiledb.Array) tiledb.ArrayType {
+	t.Helper()
+	schema, err := array.Schema()
+	require.NoError(t, err)
+	defer schema.Free()
+	arrayType, err := schema.Type()
+	require.NoError(t, err)
+	return arrayType
+}
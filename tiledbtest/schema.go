@@ -0,0 +1,120 @@
+package tiledbtest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	tiledb "github.com/TileDB-Inc/TileDB-Go"
+)
+
+/*
+SchemaBuilder builds an array schema in a single expression:
+
+	schema := tiledbtest.NewSchema(t, tdbCtx, tiledb.TILEDB_DENSE).
+		Dimension("rows", tiledb.TILEDB_INT32, []int32{1, 4}, int32(2)).
+		Dimension("cols", tiledb.TILEDB_INT32, []int32{1, 4}, int32(2)).
+		Attribute("a", tiledb.TILEDB_INT32).
+		Attribute("label", tiledb.TILEDB_STRING_UTF8, tiledbtest.Var(), tiledbtest.Nullable()).
+		Build()
+
+The methods fail the test on errors.
+*/
+type SchemaBuilder struct {
+	t      testing.TB
+	ctx    *tiledb.Context
+	schema *tiledb.ArraySchema
+	domain *tiledb.Domain
+}
+
+// NewSchema returns a builder of a schema of arrayType. The schema is freed when the test completes.
+func NewSchema(t testing.TB, tdbCtx *tiledb.Context, arrayType tiledb.ArrayType) *SchemaBuilder {
+	t.Helper()
+	schema, err := tiledb.NewArraySchema(tdbCtx, arrayType)
+	require.NoError(t, err)
+	t.Cleanup(schema.Free)
+	domain, err := tiledb.NewDomain(tdbCtx)
+	require.NoError(t, err)
+	t.Cleanup(domain.Free)
+	return &SchemaBuilder{t: t, ctx: tdbCtx, schema: schema, domain: domain}
+}
+
+// Dimension adds a dimension with the domain and tile extent, e.g. []int32{1, 100} and int32(10).
+func (b *SchemaBuilder) Dimension(name string, datatype tiledb.Datatype, domain, extent any) *SchemaBuilder {
+	b.t.Helper()
+	dimension, err := tiledb.NewDimension(b.ctx, name, datatype, domain, extent)
+	require.NoError(b.t, err)
+	defer dimension.Free()
+	require.NoError(b.t, b.domain.AddDimensions(dimension))
+	return b
+}
+
+// StringDimension adds a var-sized TILEDB_STRING_ASCII dimension, for sparse arrays.
+func (b *SchemaBuilder) StringDimension(name string) *SchemaBuilder {
+	b.t.Helper()
+	dimension, err := tiledb.NewStringDimension(b.ctx, name)
+	require.NoError(b.t, err)
+	defer dimension.Free()
+	require.NoError(b.t, b.domain.AddDimensions(dimension))
+	return b
+}
+
+// AttributeOption configures an attribute added by SchemaBuilder.Attribute.
+type AttributeOption func(t testing.TB, attribute *tiledb.Attribute)
+
+// Var makes the attribute var-sized.
+func Var() AttributeOption {
+	return CellValNum(tiledb.TILEDB_VAR_NUM)
+}
+
+// CellValNum sets the number of values per cell of the attribute.
+func CellValNum(n uint32) AttributeOption {
+	return func(t testing.TB, attribute *tiledb.Attribute) {
+		t.Helper()
+		require.NoError(t, attribute.SetCellValNum(n))
+	}
+}
+
+// Nullable makes the attribute nullable.
+func Nullable() AttributeOption {
+	return func(t testing.TB, attribute *tiledb.Attribute) {
+		t.Helper()
+		require.NoError(t, attribute.SetNullable(true))
+	}
+}
+
+// Attribute adds an attribute configured by opts.
+func (b *SchemaBuilder) Attribute(name string, datatype tiledb.Datatype, opts ...AttributeOption) *SchemaBuilder {
+	b.t.Helper()
+	attribute, err := tiledb.NewAttribute(b.ctx, name, datatype)
+	require.NoError(b.t, err)
+	defer attribute.Free()
+	for _, opt := range opts {
+		opt(b.t, attribute)
+	}
+	require.NoError(b.t, b.schema.AddAttributes(attribute))
+	return b
+}
+
+// AllowsDups allows duplicate coordinates in the sparse array.
+func (b *SchemaBuilder) AllowsDups() *SchemaBuilder {
+	b.t.Helper()
+	require.NoError(b.t, b.schema.SetAllowsDups(true))
+	return b
+}
+
+// Order sets the cell and tile orders of the schema.
+func (b *SchemaBuilder) Order(cellOrder, tileOrder tiledb.Layout) *SchemaBuilder {
+	b.t.Helper()
+	require.NoError(b.t, b.schema.SetCellOrder(cellOrder))
+	require.NoError(b.t, b.schema.SetTileOrder(tileOrder))
+	return b
+}
+
+// Build sets the domain of the schema, checks it and returns it. It is freed when the test completes.
+func (b *SchemaBuilder) Build() *tiledb.ArraySchema {
+	b.t.Helper()
+	require.NoError(b.t, b.schema.SetDomain(b.domain))
+	require.NoError(b.t, b.schema.Check())
+	return b.schema
+}
@@ -0,0 +1,79 @@
+package tiledbtest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tiledb "github.com/TileDB-Inc/TileDB-Go"
+)
+
+type sparseRow struct {
+	ID    int32    `tiledb:"id"`
+	Name  string   `tiledb:"name"`
+	Score *float64 `tiledb:"score"`
+}
+
+type denseRow struct {
+	Row int32 `tiledb:"rows"`
+	Col int32 `tiledb:"cols"`
+	A   int32 `tiledb:"a"`
+}
+
+func TestSparseArray(t *testing.T) {
+	score := 4.5
+	rows := []sparseRow{
+		{ID: 7, Name: "seven", Score: &score},
+		{ID: 2, Name: "two"},
+		{ID: 40, Name: "forty"},
+	}
+
+	ForEachBackend(t, func(t *testing.T, backend Backend) {
+		tdbCtx := NewContext(t)
+		schema := NewSchema(t, tdbCtx, tiledb.TILEDB_SPARSE).
+			Dimension("id", tiledb.TILEDB_INT32, []int32{1, 100}, int32(10)).
+			Attribute("name", tiledb.TILEDB_STRING_UTF8, Var()).
+			Attribute("score", tiledb.TILEDB_FLOAT64, Nullable()).
+			Build()
+		uri := NewArrayWithRows(t, tdbCtx, backend, "sparse", schema, rows)
+		assert.True(t, strings.HasPrefix(uri, string(backend)+"://"), uri)
+
+		AssertRowsUnordered(t, tdbCtx, uri, rows)
+		AssertRows(t, tdbCtx, uri, []sparseRow{rows[1], rows[0], rows[2]})
+
+		WriteRows(t, tdbCtx, uri, []sparseRow{{ID: 3, Name: "three"}})
+		assert.Len(t, ReadRows[sparseRow](t, tdbCtx, uri), 4)
+	})
+}
+
+func TestDenseArray(t *testing.T) {
+	ForEachBackend(t, func(t *testing.T, backend Backend) {
+		tdbCtx := NewContext(t)
+		schema := NewSchema(t, tdbCtx, tiledb.TILEDB_DENSE).
+			Dimension("rows", tiledb.TILEDB_INT32, []int32{1, 4}, int32(2)).
+			Dimension("cols", tiledb.TILEDB_INT32, []int32{1, 4}, int32(2)).
+			Attribute("a", tiledb.TILEDB_INT32).
+			Build()
+		uri := NewArray(t, tdbCtx, backend, "dense", schema)
+		assert.Empty(t, ReadRows[denseRow](t, tdbCtx, uri))
+
+		rows := []denseRow{{1, 1, 1}, {1, 2, 2}, {2, 1, 3}, {2, 2, 4}}
+		WriteRows(t, tdbCtx, uri, rows)
+		AssertRows(t, tdbCtx, uri, rows)
+
+		array := OpenArray(t, tdbCtx, uri, tiledb.TILEDB_READ)
+		_, empty, err := array.NonEmptyDomain()
+		require.NoError(t, err)
+		assert.False(t, empty)
+	})
+}
+
+func TestMemURIs(t *testing.T) {
+	tdbCtx := NewContext(t)
+	first := Mem.URI(t, tdbCtx, "array")
+	second := Mem.URI(t, tdbCtx, "array")
+	assert.NotEqual(t, first, second)
+	assert.False(t, strings.ContainsAny(strings.TrimPrefix(first, "mem://"), " :"), first)
+}
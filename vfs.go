@@ -8,6 +8,7 @@ package tiledb
 import "C"
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -21,6 +22,10 @@ import (
 
 const arrayMetadataFolderName = "__meta"
 
+// vfsChunkSize is the size of the chunks VFS.ReadContext and VFS.WriteContext transfer
+// between checks of their context.Context: 4 MiB.
+const vfsChunkSize = 4 << 20
+
 type vfsFhHandle struct{ *capiHandle }
 
 func freeCapiVfsFh(c unsafe.Pointer) {
@@ -414,16 +419,44 @@ func (v *VFS) Close(fh *VFSfh) error {
 // Read reads part of a file.
 func (v *VFS) Read(fh *VFSfh, offset uint64, nbytes uint64) ([]byte, error) {
 	bytes := make([]byte, nbytes)
-	cbuffer := slicePtr(bytes)
-	ret := C.tiledb_vfs_read(v.context.tiledbContext.Get(), fh.tiledbVFSfh.Get(), C.uint64_t(offset), cbuffer, C.uint64_t(nbytes))
+	if err := v.readInto(fh, offset, bytes); err != nil {
+		return []byte{}, err
+	}
+
+	return bytes, nil
+}
+
+// ReadContext reads part of a file like Read, but returns an error wrapping ErrCanceled when ctx
+// is done before the read completes. The file is read in chunks and ctx is checked between them:
+// a chunk being read is not interrupted, as that would cancel all the tasks of the Context of the VFS.
+func (v *VFS) ReadContext(ctx context.Context, fh *VFSfh, offset uint64, nbytes uint64) ([]byte, error) {
+	bytes := make([]byte, nbytes)
+	for done := uint64(0); done < nbytes; {
+		if ctx.Err() != nil {
+			return []byte{}, canceledError(ctx)
+		}
+		chunk := bytes[done : done+min(nbytes-done, vfsChunkSize)]
+		if err := v.readInto(fh, offset+done, chunk); err != nil {
+			return []byte{}, err
+		}
+		done += uint64(len(chunk))
+	}
+
+	return bytes, nil
+}
+
+// readInto reads len(bytes) bytes of the file from offset into bytes.
+func (v *VFS) readInto(fh *VFSfh, offset uint64, bytes []byte) error {
+	ret := C.tiledb_vfs_read(v.context.tiledbContext.Get(), fh.tiledbVFSfh.Get(), C.uint64_t(offset), slicePtr(bytes), C.uint64_t(len(bytes)))
 	runtime.KeepAlive(v)
 	runtime.KeepAlive(fh)
+	runtime.KeepAlive(bytes)
 
 	if ret != C.TILEDB_OK {
-		return []byte{}, fmt.Errorf("unknown error in VFS.Read: %w", v.context.LastError())
+		return fmt.Errorf("unknown error in VFS.Read: %w", v.context.LastError())
 	}
 
-	return bytes, nil
+	return nil
 }
 
 // Write writes the contents of a buffer into a file. Note that this function only
@@ -443,6 +476,25 @@ func (v *VFS) Write(fh *VFSfh, bytes []byte) error {
 	return nil
 }
 
+// WriteContext appends the contents of a buffer to a file like Write, but returns an error
+// wrapping ErrCanceled when ctx is done before the write completes. The buffer is written in chunks
+// and ctx is checked between them, so part of it can be written when the write is canceled.
+// A chunk being written is not interrupted, like with ReadContext.
+func (v *VFS) WriteContext(ctx context.Context, fh *VFSfh, bytes []byte) error {
+	for len(bytes) > 0 {
+		if ctx.Err() != nil {
+			return canceledError(ctx)
+		}
+		chunk := bytes[:min(len(bytes), vfsChunkSize)]
+		if err := v.Write(fh, chunk); err != nil {
+			return err
+		}
+		bytes = bytes[len(chunk):]
+	}
+
+	return nil
+}
+
 // Sync flushes a file.
 func (v *VFS) Sync(fh *VFSfh) error {
 	ret := C.tiledb_vfs_sync(v.context.tiledbContext.Get(), fh.tiledbVFSfh.Get())
@@ -550,6 +602,9 @@ func (v *VFSfh) Close() error {
 // Read reads part of a file.
 func (v *VFSfh) Read(p []byte) (int, error) {
 	nbytes := uint64(len(p))
+	if nbytes == 0 {
+		return 0, nil
+	}
 
 	// If the size is empty, fetch it
 	if v.size == nil {
@@ -0,0 +1,323 @@
+package tiledb
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+	"time"
+)
+
+/*
+VFSFS is a read-only io/fs.FS of the files under a root URI of a VFS, so that local directories,
+object stores and mem:// can be used with http.FS, template.ParseFS or fs.WalkDir. It implements
+fs.ReadDirFS, fs.ReadFileFS and fs.StatFS, and its files implement io.ReaderAt and io.Seeker.
+
+VFS has no modification times nor permissions: files have mode 0o444, directories 0o555
+and their modification time is the zero time.
+*/
+type VFSFS struct {
+	vfs  *VFS
+	root string
+}
+
+var (
+	_ fs.ReadDirFS  = (*VFSFS)(nil)
+	_ fs.ReadFileFS = (*VFSFS)(nil)
+	_ fs.StatFS     = (*VFSFS)(nil)
+)
+
+// FS returns the file system of the files under root, e.g. a local directory or "s3://bucket/prefix".
+func (v *VFS) FS(root string) *VFSFS {
+	return &VFSFS{vfs: v, root: root}
+}
+
+// uri returns the URI of the file name of the file system.
+func (fsys *VFSFS) uri(op, name string) (string, error) {
+	if !fs.ValidPath(name) {
+		return "", &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	if name == "." {
+		return fsys.root, nil
+	}
+	if strings.HasSuffix(fsys.root, "/") {
+		return fsys.root + name, nil
+	}
+	return fsys.root + "/" + name, nil
+}
+
+// Open opens the file name for reading.
+func (fsys *VFSFS) Open(name string) (fs.File, error) {
+	uri, info, err := fsys.stat("open", name)
+	if err != nil {
+		return nil, err
+	}
+	if info.isDir {
+		return &vfsDir{fsys: fsys, name: name, info: info}, nil
+	}
+
+	fh, err := fsys.vfs.Open(uri, TILEDB_VFS_READ)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	return &vfsFile{fh: fh, info: info}, nil
+}
+
+// Stat returns the fs.FileInfo of the file name.
+func (fsys *VFSFS) Stat(name string) (fs.FileInfo, error) {
+	_, info, err := fsys.stat("stat", name)
+	if err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// ReadDir returns the entries of the directory name, sorted by name.
+func (fsys *VFSFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	uri, info, err := fsys.stat("readdir", name)
+	if err != nil {
+		return nil, err
+	}
+	if !info.isDir {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: errors.New("not a directory")}
+	}
+
+	folders, files, err := fsys.vfs.List(uri)
+	if err != nil {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: err}
+	}
+	entries := make([]fs.DirEntry, 0, len(folders)+len(files))
+	for _, folder := range folders {
+		entries = append(entries, &vfsDirEntry{vfs: fsys.vfs, uri: folder, info: &vfsFileInfo{name: uriBase(folder), isDir: true}})
+	}
+	for _, file := range files {
+		entries = append(entries, &vfsDirEntry{vfs: fsys.vfs, uri: file, info: &vfsFileInfo{name: uriBase(file)}})
+	}
+	slices.SortFunc(entries, func(a, b fs.DirEntry) int { return strings.Compare(a.Name(), b.Name()) })
+	return entries, nil
+}
+
+// ReadFile returns the contents of the file name.
+func (fsys *VFSFS) ReadFile(name string) ([]byte, error) {
+	uri, info, err := fsys.stat("readfile", name)
+	if err != nil {
+		return nil, err
+	}
+	if info.isDir {
+		return nil, &fs.PathError{Op: "readfile", Path: name, Err: errors.New("is a directory")}
+	}
+	if info.size == 0 {
+		return []byte{}, nil
+	}
+
+	fh, err := fsys.vfs.Open(uri, TILEDB_VFS_READ)
+	if err != nil {
+		return nil, &fs.PathError{Op: "readfile", Path: name, Err: err}
+	}
+	defer fh.Close()
+	data, err := fsys.vfs.Read(fh, 0, uint64(info.size))
+	if err != nil {
+		return nil, &fs.PathError{Op: "readfile", Path: name, Err: err}
+	}
+	return data, nil
+}
+
+// stat returns the URI and the fs.FileInfo of the file name.
+func (fsys *VFSFS) stat(op, name string) (string, *vfsFileInfo, error) {
+	uri, err := fsys.uri(op, name)
+	if err != nil {
+		return "", nil, err
+	}
+
+	isDir, err := fsys.vfs.IsDir(uri)
+	if err != nil {
+		return "", nil, &fs.PathError{Op: op, Path: name, Err: err}
+	}
+	if isDir {
+		return uri, &vfsFileInfo{name: path.Base(name), isDir: true}, nil
+	}
+
+	isFile, err := fsys.vfs.IsFile(uri)
+	if err != nil {
+		return "", nil, &fs.PathError{Op: op, Path: name, Err: err}
+	}
+	if !isFile {
+		return "", nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+	}
+	size, err := fsys.vfs.FileSize(uri)
+	if err != nil {
+		return "", nil, &fs.PathError{Op: op, Path: name, Err: err}
+	}
+	return uri, &vfsFileInfo{name: path.Base(name), size: int64(size)}, nil
+}
+
+// uriBase returns the last element of a URI returned by VFS.List. Directories of object stores end with a slash.
+func uriBase(uri string) string {
+	return path.Base(strings.TrimSuffix(uri, "/"))
+}
+
+// vfsFile is a regular file of a VFSFS.
+type vfsFile struct {
+	fh   *VFSfh
+	info *vfsFileInfo
+}
+
+var (
+	_ io.ReaderAt = (*vfsFile)(nil)
+	_ io.Seeker   = (*vfsFile)(nil)
+)
+
+func (f *vfsFile) Stat() (fs.FileInfo, error) {
+	return f.info, nil
+}
+
+func (f *vfsFile) Read(p []byte) (int, error) {
+	if f.fh == nil {
+		return 0, fs.ErrClosed
+	}
+	return f.fh.Read(p)
+}
+
+func (f *vfsFile) ReadAt(p []byte, off int64) (int, error) {
+	if f.fh == nil {
+		return 0, fs.ErrClosed
+	}
+	return f.fh.ReadAt(p, off)
+}
+
+func (f *vfsFile) Seek(offset int64, whence int) (int64, error) {
+	if f.fh == nil {
+		return 0, fs.ErrClosed
+	}
+	return f.fh.Seek(offset, whence)
+}
+
+func (f *vfsFile) Close() error {
+	if f.fh == nil {
+		return fs.ErrClosed
+	}
+	fh := f.fh
+	f.fh = nil
+	return fh.Close()
+}
+
+// vfsDir is a directory of a VFSFS. Its entries are listed by the first call to ReadDir.
+type vfsDir struct {
+	fsys    *VFSFS
+	name    string
+	info    *vfsFileInfo
+	entries []fs.DirEntry
+	listed  bool
+	closed  bool
+}
+
+var _ fs.ReadDirFile = (*vfsDir)(nil)
+
+func (d *vfsDir) Stat() (fs.FileInfo, error) {
+	return d.info, nil
+}
+
+func (d *vfsDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.name, Err: errors.New("is a directory")}
+}
+
+// ReadDir returns the next n entries of the directory, or all the remaining ones if n <= 0.
+func (d *vfsDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	if d.closed {
+		return nil, &fs.PathError{Op: "readdir", Path: d.name, Err: fs.ErrClosed}
+	}
+	if !d.listed {
+		entries, err := d.fsys.ReadDir(d.name)
+		if err != nil {
+			return nil, err
+		}
+		d.entries, d.listed = entries, true
+	}
+
+	if n <= 0 {
+		entries := d.entries
+		d.entries = nil
+		return entries, nil
+	}
+	if len(d.entries) == 0 {
+		return nil, io.EOF
+	}
+	n = min(n, len(d.entries))
+	entries := d.entries[:n]
+	d.entries = d.entries[n:]
+	return entries, nil
+}
+
+func (d *vfsDir) Close() error {
+	if d.closed {
+		return fs.ErrClosed
+	}
+	d.closed = true
+	return nil
+}
+
+// vfsDirEntry is an entry of a directory of a VFSFS. The size of files is fetched by Info.
+type vfsDirEntry struct {
+	vfs  *VFS
+	uri  string
+	info *vfsFileInfo
+}
+
+func (e *vfsDirEntry) Name() string {
+	return e.info.name
+}
+
+func (e *vfsDirEntry) IsDir() bool {
+	return e.info.isDir
+}
+
+func (e *vfsDirEntry) Type() fs.FileMode {
+	return e.info.Mode().Type()
+}
+
+func (e *vfsDirEntry) Info() (fs.FileInfo, error) {
+	if e.info.isDir {
+		return e.info, nil
+	}
+	size, err := e.vfs.FileSize(e.uri)
+	if err != nil {
+		return nil, &fs.PathError{Op: "stat", Path: e.info.name, Err: err}
+	}
+	return &vfsFileInfo{name: e.info.name, size: int64(size)}, nil
+}
+
+// vfsFileInfo is the fs.FileInfo of the files of a VFSFS.
+type vfsFileInfo struct {
+	name  string
+	size  int64
+	isDir bool
+}
+
+func (fi *vfsFileInfo) Name() string {
+	return fi.name
+}
+
+func (fi *vfsFileInfo) Size() int64 {
+	return fi.size
+}
+
+func (fi *vfsFileInfo) Mode() fs.FileMode {
+	if fi.isDir {
+		return fs.ModeDir | 0o555
+	}
+	return 0o444
+}
+
+func (fi *vfsFileInfo) ModTime() time.Time {
+	return time.Time{}
+}
+
+func (fi *vfsFileInfo) IsDir() bool {
+	return fi.isDir
+}
+
+func (fi *vfsFileInfo) Sys() any {
+	return nil
+}
@@ -0,0 +1,67 @@
+package tiledb
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVFSFS(t *testing.T) {
+	config, err := NewConfig()
+	require.NoError(t, err)
+	tdbCtx, err := NewContext(config)
+	require.NoError(t, err)
+	vfs, err := NewVFS(tdbCtx, config)
+	require.NoError(t, err)
+
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "dir", "sub"), 0o755))
+	for name, data := range map[string]string{
+		"a.txt":            "some text",
+		"dir/b.txt":        "more text in a file",
+		"dir/empty":        "",
+		"dir/sub/data.bin": "\x00\x01\x02",
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(root, name), []byte(data), 0o644))
+	}
+
+	fsys := vfs.FS(root)
+	require.NoError(t, fstest.TestFS(fsys, "a.txt", "dir/b.txt", "dir/empty", "dir/sub/data.bin"))
+
+	data, err := fs.ReadFile(fsys, "dir/b.txt")
+	require.NoError(t, err)
+	assert.Equal(t, "more text in a file", string(data))
+
+	f, err := fsys.Open("dir/b.txt")
+	require.NoError(t, err)
+	_, err = f.(io.Seeker).Seek(5, io.SeekStart)
+	require.NoError(t, err)
+	rest, err := io.ReadAll(f)
+	require.NoError(t, err)
+	assert.Equal(t, "text in a file", string(rest))
+	require.NoError(t, f.Close())
+	assert.True(t, errors.Is(f.Close(), fs.ErrClosed))
+
+	var walked []string
+	require.NoError(t, fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+		walked = append(walked, path)
+		return err
+	}))
+	assert.Equal(t, []string{".", "a.txt", "dir", "dir/b.txt", "dir/empty", "dir/sub", "dir/sub/data.bin"}, walked)
+
+	_, err = fsys.Open("missing")
+	assert.True(t, errors.Is(err, fs.ErrNotExist))
+	_, err = fsys.Stat("../a.txt")
+	assert.True(t, errors.Is(err, fs.ErrInvalid))
+	_, err = fsys.ReadFile("dir")
+	assert.Error(t, err)
+	_, err = fsys.ReadDir("a.txt")
+	assert.Error(t, err)
+}